@@ -166,6 +166,11 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	m.Handle("/remove/", s.middleware(dsh.RemoveHandler))
 	m.Handle("/get/", s.middleware(dsh.GetHandler))
 	m.Handle("/rename", s.middleware(dsh.RenameHandler))
+	m.Handle("/revert", s.middleware(dsh.RevertHandler))
+	m.Handle("/squash", s.middleware(dsh.SquashHandler))
+	m.Handle("/rebase", s.middleware(dsh.RebaseHandler))
+	m.Handle("/blame/", s.middleware(dsh.BlameHandler))
+	m.Handle("/changelog/", s.middleware(dsh.ChangelogHandler))
 	m.Handle("/diff", s.middleware(dsh.DiffHandler))
 	// Deprecated, use /get/username/name?component=body or /get/username/name/body.csv
 	m.Handle("/body/", s.middleware(dsh.BodyHandler))
@@ -191,7 +196,10 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	m.Handle("/render/", s.middleware(renderh.RenderHandler))
 
 	lh := NewLogHandlers(s.Instance)
+	lh.readOnly = cfg.API.ReadOnly
 	m.Handle("/history/", s.middleware(lh.LogHandler))
+	m.Handle("/lineage/", s.middleware(lh.LineageHandler))
+	m.Handle("/tags/", s.middleware(lh.TagHandler))
 
 	rch := NewRegistryClientHandlers(s.Instance, cfg.API.ReadOnly)
 	m.Handle("/registry/profile/new", s.middleware(rch.CreateProfileHandler))
@@ -254,6 +254,7 @@ func TestServerReadOnlyRoutes(t *testing.T) {
 		{"GET", "/profile/poster?peername=me", 200},
 		{"GET", "/get/peer/movies", 200},
 		{"GET", "/history/peer/movies", 200},
+		{"GET", "/lineage/peer/movies", 200},
 	}
 
 	for i, c := range cases {
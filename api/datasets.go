@@ -127,6 +127,69 @@ func (h *DatasetHandlers) RenameHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// RevertHandler saves a new version restoring an earlier version
+func (h *DatasetHandlers) RevertHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if h.ReadOnly {
+			readOnlyResponse(w, "/revert")
+			return
+		}
+		h.revertHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+// SquashHandler collapses a range of dataset versions into one
+func (h *DatasetHandlers) SquashHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if h.ReadOnly {
+			readOnlyResponse(w, "/squash")
+			return
+		}
+		h.squashHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+// RebaseHandler re-parents dataset versions onto an older version
+func (h *DatasetHandlers) RebaseHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		if h.ReadOnly {
+			readOnlyResponse(w, "/rebase")
+			return
+		}
+		h.rebaseHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+// BlameHandler attributes rows of a dataset body to the versions that last
+// changed them
+func (h *DatasetHandlers) BlameHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.blameHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+// ChangelogHandler describes the changes each version of a dataset made
+func (h *DatasetHandlers) ChangelogHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.changelogHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
 // BodyHandler gets the contents of a dataset
 func (h *DatasetHandlers) BodyHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -380,6 +443,9 @@ func (h *DatasetHandlers) pullHandler(w http.ResponseWriter, r *http.Request) {
 		LinkDir: r.FormValue("dir"),
 		Remote:  r.FormValue("remote"),
 	}
+	if c := r.FormValue("components"); c != "" {
+		p.Components = strings.Split(c, ",")
+	}
 
 	res := &dataset.Dataset{}
 	err := h.Pull(p, res)
@@ -453,6 +519,9 @@ func (h *DatasetHandlers) saveHandler(w http.ResponseWriter, r *http.Request) {
 		BodyPath:     r.FormValue("bodypath"),
 		Recall:       r.FormValue("recall"),
 		Drop:         r.FormValue("drop"),
+		NoVerify:     r.FormValue("no_verify") == "true",
+		Append:       r.FormValue("append") == "true",
+		Changelog:    r.FormValue("changelog") == "true",
 
 		ConvertFormatToPrev: true,
 		ScriptOutput:        scriptOutput,
@@ -551,6 +620,128 @@ func (h DatasetHandlers) renameHandler(w http.ResponseWriter, r *http.Request) {
 	util.WriteResponse(w, res)
 }
 
+func (h DatasetHandlers) revertHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.RevertParams{}
+	if r.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		p.Ref = r.FormValue("ref")
+		p.Title = r.FormValue("title")
+		p.Message = r.FormValue("message")
+		if comps := r.FormValue("components"); comps != "" {
+			p.Components = strings.Split(comps, ",")
+		}
+	}
+
+	res := &dataset.Dataset{}
+	if err := h.Revert(p, res); err != nil {
+		log.Infof("error reverting dataset: %s", err.Error())
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	util.WriteResponse(w, res)
+}
+
+func (h DatasetHandlers) squashHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.SquashParams{}
+	if r.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		p.Ref = r.FormValue("ref")
+		p.From = r.FormValue("from")
+		p.To = r.FormValue("to")
+		p.Title = r.FormValue("title")
+		p.Message = r.FormValue("message")
+	}
+
+	res := &dataset.Dataset{}
+	if err := h.Squash(p, res); err != nil {
+		log.Infof("error squashing dataset: %s", err.Error())
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	util.WriteResponse(w, res)
+}
+
+func (h DatasetHandlers) rebaseHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.RebaseParams{}
+	if r.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		p.Ref = r.FormValue("ref")
+		p.Onto = r.FormValue("onto")
+		p.From = r.FormValue("from")
+	}
+
+	res := &dataset.Dataset{}
+	if err := h.Rebase(p, res); err != nil {
+		log.Infof("error rebasing dataset: %s", err.Error())
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	util.WriteResponse(w, res)
+}
+
+func (h DatasetHandlers) blameHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := DatasetRefFromPath(r.URL.Path[len("/blame"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p := &lib.BlameParams{
+		Ref:    args.String(),
+		Key:    r.FormValue("key"),
+		Offset: util.ReqParamInt(r, "offset", 0),
+		Limit:  util.ReqParamInt(r, "limit", -1),
+	}
+	if cols := r.FormValue("columns"); cols != "" {
+		p.Columns = strings.Split(cols, ",")
+	}
+
+	res := []lib.BlameEntry{}
+	if err := h.Blame(p, &res); err != nil {
+		log.Infof("error blaming dataset: %s", err.Error())
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	util.WriteResponse(w, res)
+}
+
+func (h DatasetHandlers) changelogHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := DatasetRefFromPath(r.URL.Path[len("/changelog"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p := &lib.ChangelogParams{
+		Ref:   args.String(),
+		Since: r.FormValue("since"),
+	}
+	res := &lib.ChangelogResult{}
+	if err := h.Changelog(p, res); err != nil {
+		log.Infof("error generating changelog: %s", err.Error())
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	util.WriteResponse(w, res)
+}
+
 func loadFileIfPath(path string) (file *os.File, err error) {
 	if path == "" {
 		return nil, nil
@@ -653,6 +844,10 @@ func parseGetReqArgs(r *http.Request, reqPath string) (*GetReqArgs, error) {
 		Offset:   listParams.Offset,
 		All:      getAll,
 		Remote:   r.FormValue("remote"),
+		Evict:    r.FormValue("evict") == "true",
+	}
+	if c := r.FormValue("columns"); c != "" {
+		params.Columns = strings.Split(c, ",")
 	}
 	args := GetReqArgs{
 		Ref:         ref,
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/qri-io/qri/api/util"
 	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/lineage"
 	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/repo"
 )
@@ -92,3 +94,103 @@ func (h *LogHandlers) logHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	return
 }
+
+// LineageHandler is the endpoint for a dataset's lineage graph
+func (h *LogHandlers) LineageHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.lineageHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *LogHandlers) lineageHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := DatasetRefFromPath(r.URL.Path[len("/lineage"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if args.Name == "" {
+		util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("name of dataset needed"))
+		return
+	}
+
+	p := &lib.LineageParams{
+		Ref:       args.String(),
+		Direction: r.FormValue("direction"),
+	}
+	res := &lineage.Graph{}
+	if err := h.lm.Lineage(p, res); err != nil {
+		util.WriteErrResponse(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if r.FormValue("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		res.WriteDOT(w)
+		return
+	}
+	util.WriteResponse(w, res)
+}
+
+// TagHandler is the endpoint for listing & writing dataset version tags
+func (h *LogHandlers) TagHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.tagListHandler(w, r)
+	case http.MethodPost:
+		if h.readOnly {
+			readOnlyResponse(w, "/tags")
+			return
+		}
+		h.tagHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *LogHandlers) tagListHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := DatasetRefFromPath(r.URL.Path[len("/tags"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := []lib.VersionTag{}
+	if err := h.lm.TagList(&lib.TagListParams{Ref: args.String()}, &res); err != nil {
+		util.WriteErrResponse(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	util.WriteResponse(w, res)
+}
+
+func (h *LogHandlers) tagHandler(w http.ResponseWriter, r *http.Request) {
+	args, err := DatasetRefFromPath(r.URL.Path[len("/tags"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p := &lib.TagParams{
+		Ref:   args.String(),
+		Name:  r.FormValue("name"),
+		Force: r.FormValue("force") == "true",
+	}
+	if p.Name == "" {
+		util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("tag name is required"))
+		return
+	}
+
+	res := &lib.VersionTag{}
+	if err := h.lm.Tag(p, res); err != nil {
+		if errors.Is(err, logbook.ErrTagExists) {
+			util.WriteErrResponse(w, http.StatusConflict, err)
+			return
+		}
+		util.WriteErrResponse(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	util.WriteResponse(w, res)
+}
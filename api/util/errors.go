@@ -40,7 +40,7 @@ func RespondWithError(w http.ResponseWriter, err error) {
 		return
 	}
 	var perr *dsref.ParseError
-	if errors.As(err, &perr) {
+	if errors.As(err, &perr) || errors.Is(err, dsref.ErrUnsupportedNetwork) {
 		WriteErrResponse(w, http.StatusBadRequest, err)
 		return
 	}
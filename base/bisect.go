@@ -0,0 +1,256 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+// ErrNoBadVersion is returned by Bisect when the latest version passes a check
+var ErrNoBadVersion = errors.New("the latest version passes the check, there's no bad version to find")
+
+// VersionLoader loads a dataset version by path. Loaders used for bisecting
+// must open the version's body file
+type VersionLoader func(ctx context.Context, path string) (*dataset.Dataset, error)
+
+// BisectCheck tests a dataset version with an open body file. A version that
+// fails the check is bad, and may come with a reason describing why
+type BisectCheck func(ctx context.Context, ds *dataset.Dataset) (good bool, reason string, err error)
+
+// BisectMark records the outcome of testing a version
+type BisectMark struct {
+	Path   string `json:"path"`
+	Good   bool   `json:"good"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// BisectResult is the outcome of a call to Bisect. Exactly one of FirstBad &
+// Next is set
+type BisectResult struct {
+	// FirstBad is the first version that fails the check
+	FirstBad string `json:"firstBad,omitempty"`
+	// LastGood is the version before FirstBad, empty when the first version
+	// of the dataset is bad
+	LastGood string `json:"lastGood,omitempty"`
+	// Next is the version to test next when bisecting without a check
+	Next string `json:"next,omitempty"`
+	// Remaining is the number of versions that could still be the first bad
+	// version
+	Remaining int `json:"remaining"`
+	// Steps estimates the number of versions left to test
+	Steps int `json:"steps"`
+	// Tested lists versions tested with the check, in the order they were
+	// tested
+	Tested []BisectMark `json:"tested,omitempty"`
+}
+
+// Bisect binary searches a dataset history for the first version that fails
+// a check. history lists version paths ordered oldest to newest. marks are
+// versions already known to be good (true) or bad (false), which narrow the
+// search. Without marks the latest version is tested first, & versions before
+// the first are assumed good. When check is nil Bisect doesn't test versions,
+// instead returning the version that should be marked next
+func Bisect(ctx context.Context, history []string, marks map[string]bool, load VersionLoader, check BisectCheck) (*BisectResult, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("bisect requires at least one version")
+	}
+
+	index := make(map[string]int, len(history))
+	for i, path := range history {
+		index[path] = i
+	}
+	good, bad := -1, -1
+	for path, isGood := range marks {
+		i, ok := index[path]
+		if !ok {
+			return nil, fmt.Errorf("version %s isn't in the dataset history", path)
+		}
+		if isGood && i > good {
+			good = i
+		} else if !isGood && (bad == -1 || i < bad) {
+			bad = i
+		}
+	}
+	if bad != -1 && good >= bad {
+		return nil, fmt.Errorf("version %s is marked good, but comes after bad version %s", history[good], history[bad])
+	}
+
+	res := &BisectResult{}
+	// test reports if a version is good, returning false for ok when there's
+	// no check to test the version with
+	test := func(i int) (isGood, ok bool, err error) {
+		if check == nil {
+			return false, false, nil
+		}
+		ds, err := load(ctx, history[i])
+		if err != nil {
+			return false, false, err
+		}
+		if f := ds.BodyFile(); f != nil {
+			defer f.Close()
+		}
+		isGood, reason, err := check(ctx, ds)
+		if err != nil {
+			return false, false, fmt.Errorf("checking version %s: %w", history[i], err)
+		}
+		res.Tested = append(res.Tested, BisectMark{Path: history[i], Good: isGood, Reason: reason})
+		return isGood, true, nil
+	}
+	// next sets the version to test when there's no check
+	next := func(i int) {
+		res.Next = history[i]
+		hi := bad
+		if hi == -1 {
+			hi = len(history)
+		}
+		res.Remaining = hi - good
+		res.Steps = bisectSteps(res.Remaining)
+	}
+
+	if bad == -1 {
+		last := len(history) - 1
+		isGood, ok, err := test(last)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			next(last)
+			return res, nil
+		}
+		if isGood {
+			return res, ErrNoBadVersion
+		}
+		bad = last
+	}
+
+	for bad-good > 1 {
+		mid := good + (bad-good)/2
+		isGood, ok, err := test(mid)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			next(mid)
+			return res, nil
+		}
+		if isGood {
+			good = mid
+		} else {
+			bad = mid
+		}
+	}
+
+	res.FirstBad = history[bad]
+	if good >= 0 {
+		res.LastGood = history[good]
+	}
+	res.Remaining = 1
+	return res, nil
+}
+
+// bisectSteps estimates the number of tests needed to find the first bad
+// version among n candidates
+func bisectSteps(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return int(math.Ceil(math.Log2(float64(n))))
+}
+
+// NewStarlarkCheck creates a check from a starlark script. The script must
+// define a function named check, which is called with the dataset (without a
+// body) & the body, and returns True for good versions. Calling fail(message)
+// also marks the version as bad, with message as the reason
+func NewStarlarkCheck(filename string, script []byte) (BisectCheck, error) {
+	thread := &starlark.Thread{
+		Name: "bisect",
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Infof("bisect check: %s", msg)
+		},
+	}
+	globals, err := starlark.ExecFile(thread, filename, script, nil)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := globals["check"].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("script doesn't define a check function")
+	}
+
+	return func(ctx context.Context, ds *dataset.Dataset) (bool, string, error) {
+		dsv, err := starlarkDataset(ds)
+		if err != nil {
+			return false, "", err
+		}
+		rr, err := dsio.NewEntryReader(ds.Structure, ds.BodyFile())
+		if err != nil {
+			return false, "", fmt.Errorf("error allocating data reader: %s", err)
+		}
+		body, err := ReadEntries(rr)
+		if err != nil {
+			return false, "", err
+		}
+		bodyv, err := util.Marshal(body)
+		if err != nil {
+			return false, "", err
+		}
+
+		v, err := starlark.Call(thread, fn, starlark.Tuple{dsv, bodyv}, nil)
+		if err != nil {
+			var evalErr *starlark.EvalError
+			if errors.As(err, &evalErr) && strings.HasPrefix(evalErr.Msg, "fail: ") {
+				return false, strings.TrimPrefix(evalErr.Msg, "fail: "), nil
+			}
+			return false, "", err
+		}
+		return bool(v.Truth()), "", nil
+	}, nil
+}
+
+// starlarkDataset converts a dataset to a starlark dictionary
+func starlarkDataset(ds *dataset.Dataset) (starlark.Value, error) {
+	data, err := json.Marshal(ds)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return util.Marshal(fields)
+}
+
+// NewSchemaCheck creates a check that validates bodies against a JSON schema.
+// Versions with any validation errors are bad
+func NewSchemaCheck(schema map[string]interface{}) BisectCheck {
+	return func(ctx context.Context, ds *dataset.Dataset) (bool, string, error) {
+		if ds.Structure == nil || ds.BodyFile() == nil {
+			return false, "", fmt.Errorf("dataset version %s has no body to check", ds.Path)
+		}
+		st := &dataset.Structure{
+			Format:       ds.Structure.Format,
+			FormatConfig: ds.Structure.FormatConfig,
+			Schema:       schema,
+		}
+		errs, err := Validate(ctx, nil, ds.BodyFile(), st)
+		if err != nil {
+			return false, "", err
+		}
+		if len(errs) == 0 {
+			return true, "", nil
+		}
+		reason := fmt.Sprintf("%s: %s", errs[0].PropertyPath, errs[0].Message)
+		if len(errs) > 1 {
+			reason = fmt.Sprintf("%s (and %d more validation errors)", reason, len(errs)-1)
+		}
+		return false, reason, nil
+	}
+}
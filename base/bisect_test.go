@@ -0,0 +1,108 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestBisect(t *testing.T) {
+	ctx := context.Background()
+	// the first bad version is v5, the first with a negative value
+	history := []string{"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"}
+	bodies := map[string]string{}
+	for i, path := range history {
+		if i < 5 {
+			bodies[path] = fmt.Sprintf(`[["a",%d]]`, i)
+		} else {
+			bodies[path] = fmt.Sprintf(`[["a",%d]]`, -i)
+		}
+	}
+	load := func(ctx context.Context, path string) (*dataset.Dataset, error) {
+		ds := &dataset.Dataset{
+			Path:      path,
+			Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(bodies[path])))
+		return ds, nil
+	}
+
+	check, err := NewStarlarkCheck("check.star", []byte(`
+def check(ds, body):
+  if body[0][1] < 0:
+    fail("negative value")
+  return True
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Bisect(ctx, history, nil, load, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FirstBad != "v5" || res.LastGood != "v4" {
+		t.Errorf("result mismatch. want first bad v5 after v4, got: %q after %q", res.FirstBad, res.LastGood)
+	}
+	if len(res.Tested) > 4 {
+		t.Errorf("expected at most 4 tests, got %d", len(res.Tested))
+	}
+	for _, mark := range res.Tested {
+		if mark.Path == "v5" && mark.Reason != "negative value" {
+			t.Errorf("reason mismatch. want %q, got: %q", "negative value", mark.Reason)
+		}
+	}
+
+	schemaCheck := NewSchemaCheck(map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": []interface{}{map[string]interface{}{"type": "string"}, map[string]interface{}{"type": "integer", "minimum": 0}},
+		},
+	})
+	res, err = Bisect(ctx, history, map[string]bool{"v2": true}, load, schemaCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FirstBad != "v5" {
+		t.Errorf("schema check: first bad mismatch. want v5, got: %q", res.FirstBad)
+	}
+
+	// bisecting by hand returns the next version to mark
+	marks := map[string]bool{"v0": true, "v7": false}
+	for i := 0; i < 4; i++ {
+		res, err = Bisect(ctx, history, marks, load, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Next == "" {
+			break
+		}
+		good, _, err := check(ctx, mustLoad(t, load, res.Next))
+		if err != nil {
+			t.Fatal(err)
+		}
+		marks[res.Next] = good
+	}
+	if res.FirstBad != "v5" {
+		t.Errorf("manual bisect: first bad mismatch. want v5, got: %q", res.FirstBad)
+	}
+
+	if _, err = Bisect(ctx, history, map[string]bool{"v6": true, "v3": false}, load, nil); err == nil {
+		t.Error("expected a good version after a bad version to error")
+	}
+	if _, err = Bisect(ctx, history[:4], nil, load, check); err != ErrNoBadVersion {
+		t.Errorf("expected ErrNoBadVersion, got: %v", err)
+	}
+}
+
+func mustLoad(t *testing.T, load VersionLoader, path string) *dataset.Dataset {
+	ds, err := load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
@@ -0,0 +1,275 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+)
+
+// BlameLoader loads a dataset version with an open body file
+type BlameLoader func(ctx context.Context, path string) (*dataset.Dataset, error)
+
+// BlameVersion describes the version that last changed a value
+type BlameVersion struct {
+	Path        string    `json:"path"`
+	CommitTitle string    `json:"commitTitle,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// BlameEntry attributes a row, or one cell of a row, to the version that last
+// changed it
+type BlameEntry struct {
+	// Row is the position of the row in the body of the blamed version
+	Row int `json:"row"`
+	// Key is the primary key value of the row
+	Key interface{} `json:"key"`
+	// Column is set when blaming individual cells
+	Column  string       `json:"column,omitempty"`
+	Version BlameVersion `json:"version"`
+}
+
+// BlameParams configures a call to Blame
+type BlameParams struct {
+	// Key names the primary key column rows are matched by across versions.
+	// Defaults to the entry key of object bodies, or the first column of
+	// tabular bodies
+	Key string
+	// Columns blames individual cells of the named columns instead of rows
+	Columns []string
+	// Offset & Limit select a range of rows of the blamed version. A limit of
+	// zero or less selects all rows from offset on
+	Offset, Limit int
+}
+
+// blameCell tracks the oldest version in an unbroken run of versions that
+// share a value
+type blameCell struct {
+	column   string
+	value    interface{}
+	version  int
+	resolved bool
+}
+
+type blameRow struct {
+	index int
+	key   interface{}
+	cells []*blameCell
+}
+
+// resolved reports if every tracked cell of a row has been attributed
+func (r *blameRow) resolved() bool {
+	for _, c := range r.cells {
+		if !c.resolved {
+			return false
+		}
+	}
+	return true
+}
+
+// Blame attributes rows (or cells) of a dataset version to the version that
+// last changed them. history lists version paths ordered newest to oldest,
+// starting with the version to blame. Versions are loaded one at a time,
+// keeping only the selected rows of the blamed version in memory, and loading
+// stops as soon as every row is attributed
+func Blame(ctx context.Context, history []string, load BlameLoader, p BlameParams) ([]BlameEntry, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("blame requires at least one version")
+	}
+	if p.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", p.Offset)
+	}
+
+	versions := make([]BlameVersion, 0, len(history))
+	head, err := load(ctx, history[0])
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, blameVersion(history[0], head))
+
+	var rows []*blameRow
+	err = eachBlameRow(head, p.Key, func(i int, key interface{}, fields map[string]interface{}) error {
+		if i < p.Offset || (p.Limit > 0 && i >= p.Offset+p.Limit) {
+			return nil
+		}
+		row := &blameRow{index: i, key: key}
+		if len(p.Columns) > 0 {
+			for _, col := range p.Columns {
+				val, ok := fields[col]
+				if !ok {
+					return fmt.Errorf("row %d has no column %q", i, col)
+				}
+				row.cells = append(row.cells, &blameCell{column: col, value: val})
+			}
+		} else {
+			row.cells = []*blameCell{{value: fields}}
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pending := map[string]*blameRow{}
+	for _, row := range rows {
+		k := blameKey(row.key)
+		if _, ok := pending[k]; ok {
+			return nil, fmt.Errorf("key %v isn't unique, rows need a unique key to blame", row.key)
+		}
+		pending[k] = row
+	}
+
+	for i := 1; i < len(history) && len(pending) > 0; i++ {
+		ds, err := load(ctx, history[i])
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, blameVersion(history[i], ds))
+
+		seen := map[string]bool{}
+		err = eachBlameRow(ds, p.Key, func(_ int, key interface{}, fields map[string]interface{}) error {
+			k := blameKey(key)
+			row, ok := pending[k]
+			if !ok || seen[k] {
+				return nil
+			}
+			seen[k] = true
+			for _, c := range row.cells {
+				if c.resolved {
+					continue
+				}
+				var prev interface{} = fields
+				if c.column != "" {
+					if prev, ok = fields[c.column]; !ok {
+						c.resolved = true
+						continue
+					}
+				}
+				if reflect.DeepEqual(prev, c.value) {
+					c.version = i
+				} else {
+					c.resolved = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for k, row := range pending {
+			if !seen[k] {
+				// the row doesn't exist in this version, it was added after
+				for _, c := range row.cells {
+					c.resolved = true
+				}
+			}
+			if row.resolved() {
+				delete(pending, k)
+			}
+		}
+	}
+
+	entries := make([]BlameEntry, 0, len(rows))
+	for _, row := range rows {
+		for _, c := range row.cells {
+			entries = append(entries, BlameEntry{
+				Row:     row.index,
+				Key:     row.key,
+				Column:  c.column,
+				Version: versions[c.version],
+			})
+		}
+	}
+	return entries, nil
+}
+
+func blameVersion(path string, ds *dataset.Dataset) BlameVersion {
+	v := BlameVersion{Path: path, Author: ds.Peername}
+	if ds.Commit != nil {
+		v.CommitTitle = ds.Commit.Title
+		v.Timestamp = ds.Commit.Timestamp
+		if v.Author == "" && ds.Commit.Author != nil {
+			v.Author = ds.Commit.Author.ID
+		}
+	}
+	return v
+}
+
+func blameKey(key interface{}) string {
+	return fmt.Sprintf("%T:%v", key, key)
+}
+
+// eachBlameRow streams the rows of a dataset body, calling fn with each row's
+// position, primary key, & values keyed by column name. Rows missing the key
+// column are skipped
+func eachBlameRow(ds *dataset.Dataset, key string, fn func(i int, key interface{}, fields map[string]interface{}) error) error {
+	if ds.Structure == nil || ds.BodyFile() == nil {
+		return fmt.Errorf("dataset version %s has no body to blame", ds.Path)
+	}
+	defer ds.BodyFile().Close()
+
+	var titles []string
+	if cols, _, err := tabular.ColumnsFromJSONSchema(ds.Structure.Schema); err == nil {
+		titles = cols.Titles()
+	}
+
+	rr, err := dsio.NewEntryReader(ds.Structure, ds.BodyFile())
+	if err != nil {
+		return fmt.Errorf("error allocating data reader: %s", err)
+	}
+
+	for i := 0; ; i++ {
+		ent, err := rr.ReadEntry()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		fields := map[string]interface{}{}
+		switch v := ent.Value.(type) {
+		case []interface{}:
+			for j, val := range v {
+				name := fmt.Sprintf("field_%d", j+1)
+				if j < len(titles) {
+					name = titles[j]
+				}
+				fields[name] = val
+			}
+		case map[string]interface{}:
+			fields = v
+		default:
+			fields["value"] = v
+		}
+
+		var k interface{}
+		switch {
+		case key != "":
+			val, ok := fields[key]
+			if !ok {
+				// rows without a key can't be matched across versions
+				continue
+			}
+			k = val
+		case ent.Key != "":
+			k = ent.Key
+		case len(titles) > 0:
+			k = fields[titles[0]]
+		default:
+			return fmt.Errorf("a key column is required to blame this body")
+		}
+
+		if err := fn(i, k, fields); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestBlame(t *testing.T) {
+	ctx := context.Background()
+	bodies := map[string]string{
+		"/mem/v3": `[{"id":"a","n":1},{"id":"b","n":20},{"id":"c","n":3}]`,
+		"/mem/v2": `[{"id":"a","n":1},{"id":"b","n":2}]`,
+		"/mem/v1": `[{"id":"a","n":1},{"id":"b","n":2,"x":true}]`,
+	}
+	history := []string{"/mem/v3", "/mem/v2", "/mem/v1"}
+	loaded := 0
+	load := func(ctx context.Context, path string) (*dataset.Dataset, error) {
+		body, ok := bodies[path]
+		if !ok {
+			return nil, fmt.Errorf("not found: %s", path)
+		}
+		loaded++
+		ds := &dataset.Dataset{
+			Path:      path,
+			Peername:  "peer",
+			Commit:    &dataset.Commit{Title: "commit " + path, Timestamp: time.Date(2001, 1, 1, 1, 0, 0, 0, time.UTC)},
+			Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(body)))
+		return ds, nil
+	}
+
+	got, err := Blame(ctx, history, load, BlameParams{Key: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[interface{}]string{"a": "/mem/v1", "b": "/mem/v3", "c": "/mem/v3"}
+	if len(got) != len(expect) {
+		t.Fatalf("entry count mismatch. want: %d, got: %d", len(expect), len(got))
+	}
+	for _, e := range got {
+		if e.Version.Path != expect[e.Key] {
+			t.Errorf("row %v: version mismatch. want: %q, got: %q", e.Key, expect[e.Key], e.Version.Path)
+		}
+	}
+
+	got, err = Blame(ctx, history, load, BlameParams{Key: "id", Columns: []string{"n"}, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Column != "n" || got[0].Version.Path != "/mem/v1" {
+		t.Errorf("unexpected cell blame: %#v", got)
+	}
+
+	loaded = 0
+	if _, err = Blame(ctx, history, load, BlameParams{Key: "id", Offset: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if loaded != 2 {
+		t.Errorf("expected blame to stop loading once every row is attributed. loaded %d versions", loaded)
+	}
+
+	bodies["/mem/v3"] = `[{"id":"a"},{"id":"a"}]`
+	if _, err = Blame(ctx, history, load, BlameParams{Key: "id"}); err == nil {
+		t.Error("expected duplicate keys to error")
+	}
+}
@@ -0,0 +1,382 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/deepdiff"
+	"github.com/qri-io/qri/base/friendly"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
+)
+
+// ChangelogVersion describes the changes a dataset version made to the
+// version before it
+type ChangelogVersion struct {
+	Path      string    `json:"path"`
+	Timestamp time.Time `json:"timestamp"`
+	Author    string    `json:"author,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	// Summary & Changes describe changed components, Changes has one line per
+	// component
+	Summary string   `json:"summary,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+	// number of body entries, and the change from the previous version
+	Rows       int `json:"rows"`
+	RowsChange int `json:"rowsChange"`
+	// SchemaChanges lists columns added, removed, or retyped
+	SchemaChanges []SchemaChange `json:"schemaChanges,omitempty"`
+	// Reverted is the path of the version a revert restored
+	Reverted string `json:"reverted,omitempty"`
+}
+
+// SchemaChange describes a change to one column of a dataset schema
+type SchemaChange struct {
+	Column string `json:"column"`
+	// one of "added", "removed", or "retyped"
+	Change string `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// Changelog describes the changes made by each version of a dataset history.
+// history is ordered newest first. When since is the path of a version in
+// history, only versions after it are described. Versions are loaded with
+// load, which doesn't need to read bodies
+func Changelog(ctx context.Context, history []logbook.DatasetLogItem, since string, load VersionLoader) ([]ChangelogVersion, error) {
+	end := len(history)
+	if since != "" {
+		end = -1
+		for i, item := range history {
+			if item.Path == since {
+				end = i
+				break
+			}
+		}
+		if end == -1 {
+			return nil, fmt.Errorf("version %s isn't in dataset history", since)
+		}
+	}
+
+	versions := make([]ChangelogVersion, 0, end)
+	var next *dataset.Dataset
+	for i := 0; i <= end && i < len(history); i++ {
+		ds, err := load(ctx, history[i].Path)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			versions = append(versions, changelogVersion(history[i-1], ds, next))
+		}
+		next = ds
+	}
+	if end == len(history) && next != nil {
+		versions = append(versions, changelogVersion(history[len(history)-1], nil, next))
+	}
+	return versions, nil
+}
+
+// NextChangelogVersion describes the changes a version that's being saved
+// makes to prev, the version before it. The version doesn't have a path yet.
+// When the commit has no title the summary of changes is used, commit titles
+// are generated after the changelog is written
+func NextChangelogVersion(prev, ds *dataset.Dataset, author string, now time.Time) ChangelogVersion {
+	item := logbook.DatasetLogItem{
+		VersionInfo: dsref.VersionInfo{Username: author, CommitTime: now},
+	}
+	v := changelogVersion(item, prev, ds)
+	v.Path = ""
+	if v.Title == "" {
+		v.Title = v.Summary
+	}
+	return v
+}
+
+func changelogVersion(item logbook.DatasetLogItem, prev, ds *dataset.Dataset) ChangelogVersion {
+	v := ChangelogVersion{
+		Path:      item.Path,
+		Timestamp: item.CommitTime,
+		Author:    item.Username,
+		Tags:      item.Tags,
+		Title:     item.CommitTitle,
+		Message:   item.CommitMessage,
+		Reverted:  item.Reverted,
+	}
+	if ds.Commit != nil {
+		v.Title = ds.Commit.Title
+		v.Message = ds.Commit.Message
+		if v.Timestamp.IsZero() {
+			v.Timestamp = ds.Commit.Timestamp
+		}
+	}
+	if ds.Structure != nil {
+		v.Rows = ds.Structure.Entries
+	}
+
+	if prev == nil {
+		v.Summary = "created dataset"
+		v.RowsChange = v.Rows
+		v.SchemaChanges = schemaChanges(nil, ds)
+		return v
+	}
+	if prev.Structure != nil {
+		v.RowsChange = v.Rows - prev.Structure.Entries
+	}
+	v.SchemaChanges = schemaChanges(prev, ds)
+
+	deltas, err := deepdiff.New().Diff(context.Background(), changelogComponents(prev), changelogComponents(ds))
+	if err != nil {
+		log.Debugf("diffing version %q: %s", ds.Path, err)
+	}
+	short, long := friendly.DiffDescriptions(deltas, nil, nil, bodyChanged(prev, ds))
+	v.Summary = short
+	v.Changes = changeLines(long)
+	return v
+}
+
+// changelogComponents converts the components of a dataset to a map for
+// diffing, dropping values that change with every version. scripts are
+// compared by path
+func changelogComponents(ds *dataset.Dataset) map[string]interface{} {
+	comps := map[string]interface{}{}
+	if ds.Meta != nil {
+		if m := toMap(ds.Meta); m != nil {
+			delete(m, "path")
+			delete(m, "qri")
+			comps["meta"] = m
+		}
+	}
+	if ds.Structure != nil {
+		if m := toMap(ds.Structure); m != nil {
+			for _, key := range []string{"checksum", "entries", "length", "depth", "errCount", "path", "qri"} {
+				delete(m, key)
+			}
+			comps["structure"] = m
+		}
+	}
+	if ds.Readme != nil {
+		comps["readme"] = map[string]interface{}{"script": ds.Readme.ScriptPath}
+	}
+	if ds.Viz != nil {
+		comps["viz"] = map[string]interface{}{"script": ds.Viz.ScriptPath}
+	}
+	if ds.Transform != nil {
+		comps["transform"] = map[string]interface{}{"script": ds.Transform.ScriptPath}
+	}
+	return comps
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func bodyChanged(prev, ds *dataset.Dataset) bool {
+	if prev.Structure != nil && ds.Structure != nil && prev.Structure.Checksum != "" && ds.Structure.Checksum != "" {
+		return prev.Structure.Checksum != ds.Structure.Checksum
+	}
+	return prev.BodyPath != ds.BodyPath
+}
+
+// changeLines joins the lines of a friendly diff description into one line
+// per component
+func changeLines(long string) []string {
+	var lines []string
+	for _, line := range strings.Split(long, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "\t") && len(lines) > 0:
+			last := lines[len(lines)-1]
+			if strings.HasSuffix(last, ":") {
+				lines[len(lines)-1] = last + " " + strings.TrimSpace(line)
+			} else {
+				lines[len(lines)-1] = last + ", " + strings.TrimSpace(line)
+			}
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+type schemaColumn struct {
+	Title string
+	Type  string
+}
+
+// schemaChanges compares the columns of two dataset schemas
+func schemaChanges(prev, ds *dataset.Dataset) []SchemaChange {
+	prevCols := schemaColumns(prev)
+	cols := schemaColumns(ds)
+
+	prevTypes := map[string]string{}
+	for _, col := range prevCols {
+		prevTypes[col.Title] = col.Type
+	}
+	types := map[string]string{}
+	var changes []SchemaChange
+	for _, col := range cols {
+		types[col.Title] = col.Type
+		prevType, ok := prevTypes[col.Title]
+		if !ok {
+			changes = append(changes, SchemaChange{Column: col.Title, Change: "added", To: col.Type})
+		} else if prevType != col.Type {
+			changes = append(changes, SchemaChange{Column: col.Title, Change: "retyped", From: prevType, To: col.Type})
+		}
+	}
+	for _, col := range prevCols {
+		if _, ok := types[col.Title]; !ok {
+			changes = append(changes, SchemaChange{Column: col.Title, Change: "removed", From: col.Type})
+		}
+	}
+	return changes
+}
+
+// schemaColumns lists the columns of tabular & object-row dataset schemas
+func schemaColumns(ds *dataset.Dataset) []schemaColumn {
+	if ds == nil || ds.Structure == nil {
+		return nil
+	}
+	items, ok := ds.Structure.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var cols []schemaColumn
+	if list, ok := items["items"].([]interface{}); ok {
+		for i, c := range list {
+			col, _ := c.(map[string]interface{})
+			title, _ := col["title"].(string)
+			if title == "" {
+				title = fmt.Sprintf("column %d", i)
+			}
+			cols = append(cols, schemaColumn{Title: title, Type: schemaType(col["type"])})
+		}
+	} else if props, ok := items["properties"].(map[string]interface{}); ok {
+		// object properties have no order, list columns by title so changes
+		// are described the same way every time
+		titles := make([]string, 0, len(props))
+		for title := range props {
+			titles = append(titles, title)
+		}
+		sort.Strings(titles)
+		for _, title := range titles {
+			col, _ := props[title].(map[string]interface{})
+			cols = append(cols, schemaColumn{Title: title, Type: schemaType(col["type"])})
+		}
+	}
+	return cols
+}
+
+func schemaType(t interface{}) string {
+	switch x := t.(type) {
+	case string:
+		return x
+	case []interface{}:
+		types := make([]string, len(x))
+		for i, v := range x {
+			types[i] = fmt.Sprintf("%v", v)
+		}
+		return strings.Join(types, "|")
+	case nil:
+		return "any"
+	}
+	return fmt.Sprintf("%v", t)
+}
+
+// ChangelogMarkdown renders a changelog as a Markdown document
+func ChangelogMarkdown(title string, versions []ChangelogVersion) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "# %s\n", title)
+	for _, v := range versions {
+		heading := v.Title
+		if len(v.Tags) > 0 {
+			heading = fmt.Sprintf("%s: %s", strings.Join(v.Tags, ", "), heading)
+		}
+		fmt.Fprintf(b, "\n## %s\n\n", heading)
+		var details []string
+		if v.Path != "" {
+			details = append(details, fmt.Sprintf("`%s`", v.Path))
+		}
+		if !v.Timestamp.IsZero() {
+			details = append(details, v.Timestamp.UTC().Format("2006-01-02"))
+		}
+		if v.Author != "" {
+			details = append(details, v.Author)
+		}
+		fmt.Fprintf(b, "%s\n", strings.Join(details, " · "))
+
+		if msg := strings.TrimSpace(v.Message); msg != "" && msg != v.Title {
+			fmt.Fprintf(b, "\n%s\n", msg)
+		}
+
+		var lines []string
+		if v.Reverted != "" {
+			lines = append(lines, fmt.Sprintf("reverted to `%s`", v.Reverted))
+		}
+		if v.Summary == "created dataset" {
+			lines = append(lines, "created dataset")
+		}
+		lines = append(lines, v.Changes...)
+		if v.RowsChange != 0 {
+			lines = append(lines, fmt.Sprintf("rows: %d → %d (%+d)", v.Rows-v.RowsChange, v.Rows, v.RowsChange))
+		}
+		for _, c := range v.SchemaChanges {
+			switch c.Change {
+			case "added":
+				lines = append(lines, fmt.Sprintf("added column `%s` (%s)", c.Column, c.To))
+			case "removed":
+				lines = append(lines, fmt.Sprintf("removed column `%s`", c.Column))
+			case "retyped":
+				lines = append(lines, fmt.Sprintf("column `%s` changed type from %s to %s", c.Column, c.From, c.To))
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString("\n")
+			for _, line := range lines {
+				fmt.Fprintf(b, "- %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+const (
+	changelogStart = "<!-- changelog -->"
+	changelogEnd   = "<!-- /changelog -->"
+)
+
+var changelogSection = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(changelogStart) + `.*?` + regexp.QuoteMeta(changelogEnd))
+
+// WriteChangelogToReadme places a markdown changelog in readme text, between
+// changelog comment markers. An existing changelog section is replaced,
+// otherwise the changelog is appended
+func WriteChangelogToReadme(readme, changelog string) string {
+	section := fmt.Sprintf("%s\n%s%s", changelogStart, changelog, changelogEnd)
+	if changelogSection.MatchString(readme) {
+		return changelogSection.ReplaceAllLiteralString(readme, section)
+	}
+	if readme != "" && !strings.HasSuffix(readme, "\n\n") {
+		if strings.HasSuffix(readme, "\n") {
+			readme += "\n"
+		} else {
+			readme += "\n\n"
+		}
+	}
+	return readme + section + "\n"
+}
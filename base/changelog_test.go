@@ -0,0 +1,176 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
+)
+
+func TestChangelog(t *testing.T) {
+	ctx := context.Background()
+	schema := func(cols ...string) map[string]interface{} {
+		items := []interface{}{}
+		for _, c := range cols {
+			parts := strings.Split(c, ":")
+			items = append(items, map[string]interface{}{"title": parts[0], "type": parts[1]})
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": items},
+		}
+	}
+	version := func(path, title, message, metaTitle, checksum string, entries int, cols ...string) *dataset.Dataset {
+		return &dataset.Dataset{
+			Path:      path,
+			Commit:    &dataset.Commit{Title: title, Message: message},
+			Meta:      &dataset.Meta{Title: metaTitle},
+			Structure: &dataset.Structure{Format: "csv", Checksum: checksum, Entries: entries, Schema: schema(cols...)},
+		}
+	}
+	versions := map[string]*dataset.Dataset{
+		"/ipfs/QmOne":   version("/ipfs/QmOne", "created", "", "cities", "QmBodyA", 2, "name:string", "pop:integer"),
+		"/ipfs/QmTwo":   version("/ipfs/QmTwo", "more cities", "added cities", "world cities", "QmBodyB", 5, "name:string", "pop:integer", "country:string"),
+		"/ipfs/QmThree": version("/ipfs/QmThree", "revert", "", "world cities", "QmBodyA", 2, "pop:string", "country:string"),
+	}
+	load := func(ctx context.Context, path string) (*dataset.Dataset, error) {
+		ds, ok := versions[path]
+		if !ok {
+			return nil, fmt.Errorf("not found: %s", path)
+		}
+		return ds, nil
+	}
+	day := func(d int) time.Time { return time.Date(2020, time.March, d, 0, 0, 0, 0, time.UTC) }
+	history := []logbook.DatasetLogItem{
+		{VersionInfo: dsref.VersionInfo{Username: "peer", Path: "/ipfs/QmThree", CommitTime: day(3)}, Reverted: "/ipfs/QmOne"},
+		{VersionInfo: dsref.VersionInfo{Username: "peer", Path: "/ipfs/QmTwo", CommitTime: day(2)}, Tags: []string{"v2"}},
+		{VersionInfo: dsref.VersionInfo{Username: "peer", Path: "/ipfs/QmOne", CommitTime: day(1)}},
+	}
+
+	got, err := Changelog(ctx, history, "", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []ChangelogVersion{
+		{
+			Path: "/ipfs/QmThree", Timestamp: day(3), Author: "peer", Title: "revert",
+			Summary: "updated structure and body",
+			Changes: []string{"structure: updated schema.items.items.0.title", "body changed"},
+			Rows:    2, RowsChange: -3,
+			Reverted: "/ipfs/QmOne",
+			SchemaChanges: []SchemaChange{
+				{Column: "pop", Change: "retyped", From: "integer", To: "string"},
+				{Column: "name", Change: "removed", From: "string"},
+			},
+		},
+		{
+			Path: "/ipfs/QmTwo", Timestamp: day(2), Author: "peer", Tags: []string{"v2"}, Title: "more cities",
+			Message: "added cities",
+			Summary: "updated meta, structure, and body",
+			Changes: []string{"meta: updated title", "structure: added schema.items.items.2", "body changed"},
+			Rows:    5, RowsChange: 3,
+			SchemaChanges: []SchemaChange{{Column: "country", Change: "added", To: "string"}},
+		},
+		{
+			Path: "/ipfs/QmOne", Timestamp: day(1), Author: "peer", Title: "created",
+			Summary: "created dataset",
+			Rows:    2, RowsChange: 2,
+			SchemaChanges: []SchemaChange{{Column: "name", Change: "added", To: "string"}, {Column: "pop", Change: "added", To: "integer"}},
+		},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("changelog mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = Changelog(ctx, history, "/ipfs/QmTwo", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Path != "/ipfs/QmThree" {
+		t.Errorf("expected changelog since QmTwo to only describe QmThree, got: %v", got)
+	}
+	if _, err := Changelog(ctx, history, "/ipfs/QmNope", load); err == nil {
+		t.Error("expected since version outside history to error")
+	}
+
+	md := ChangelogMarkdown("peer/cities changelog", got)
+	for _, s := range []string{
+		"# peer/cities changelog",
+		"## revert",
+		"`/ipfs/QmThree` · 2020-03-03 · peer",
+		"- reverted to `/ipfs/QmOne`",
+		"- rows: 5 → 2 (-3)",
+		"- column `pop` changed type from integer to string",
+		"- removed column `name`",
+	} {
+		if !strings.Contains(md, s) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", s, md)
+		}
+	}
+}
+
+func TestNextChangelogVersion(t *testing.T) {
+	objectSchema := func(cols ...string) map[string]interface{} {
+		props := map[string]interface{}{}
+		for _, c := range cols {
+			props[c] = map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "object", "properties": props},
+		}
+	}
+	prev := &dataset.Dataset{
+		Path:      "/ipfs/QmOne",
+		Commit:    &dataset.Commit{Title: "created"},
+		Structure: &dataset.Structure{Format: "json", Checksum: "QmBodyA", Entries: 2, Schema: objectSchema("name")},
+	}
+	next := &dataset.Dataset{
+		Commit:    &dataset.Commit{},
+		Structure: &dataset.Structure{Format: "json", Checksum: "QmBodyB", Entries: 4, Schema: objectSchema("name", "pop", "country", "area")},
+	}
+	day := time.Date(2020, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+	got := NextChangelogVersion(prev, next, "peer", day)
+	expect := ChangelogVersion{
+		Timestamp: day, Author: "peer",
+		Title:   "body changed",
+		Summary: "body changed",
+		Changes: []string{"body changed"},
+		Rows:    4, RowsChange: 2,
+		SchemaChanges: []SchemaChange{
+			{Column: "area", Change: "added", To: "string"},
+			{Column: "country", Change: "added", To: "string"},
+			{Column: "pop", Change: "added", To: "string"},
+		},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("next version mismatch (-want +got):\n%s", diff)
+	}
+
+	md := ChangelogMarkdown("Changelog", []ChangelogVersion{got})
+	if !strings.Contains(md, "## body changed\n\n2020-03-04 · peer\n") {
+		t.Errorf("expected markdown of a version without a path, got:\n%s", md)
+	}
+}
+
+func TestWriteChangelogToReadme(t *testing.T) {
+	cases := []struct {
+		readme, changelog, expect string
+	}{
+		{"", "# log\n", "<!-- changelog -->\n# log\n<!-- /changelog -->\n"},
+		{"# Title", "# log\n", "# Title\n\n<!-- changelog -->\n# log\n<!-- /changelog -->\n"},
+		{"# Title\n\n<!-- changelog -->\n# old\n<!-- /changelog -->\n\nfooter\n", "# new\n", "# Title\n\n<!-- changelog -->\n# new\n<!-- /changelog -->\n\nfooter\n"},
+	}
+	for i, c := range cases {
+		if got := WriteChangelogToReadme(c.readme, c.changelog); got != c.expect {
+			t.Errorf("case %d: expected:\n%q\ngot:\n%q", i, c.expect, got)
+		}
+	}
+}
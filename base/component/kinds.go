@@ -14,6 +14,7 @@ import (
 	"github.com/qri-io/dataset/detect"
 	"github.com/qri-io/dataset/dsio"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/base/fill"
 	"github.com/qri-io/qri/base/toqtype"
 	"gopkg.in/yaml.v2"
@@ -419,8 +420,27 @@ func (bc *BodyComponent) LoadAndFill(ds *dataset.Dataset) error {
 		if err != nil {
 			return err
 		}
+	} else if dsfs.IsPartitionDir(bc.SourceFile) {
+		if bc.Structure != nil {
+			pbf, err := dsfs.OpenPartitionDir(bc.SourceFile)
+			if err != nil {
+				return err
+			}
+			entries, err = dsio.NewEntryReader(bc.Structure, pbf.WithStructure(bc.Structure))
+		} else {
+			entries, err = OpenPartitionEntryReader(bc.SourceFile, bc.BaseComponent.Format)
+			if err == nil {
+				bc.InferredSchema = entries.Structure().Schema
+			}
+		}
+		if err != nil {
+			return err
+		}
 	} else if bc.Resolver != nil {
-		bf, err := bc.Resolver.Get(context.Background(), bc.Base().SourceFile)
+		bf, err := dsfs.OpenBody(context.Background(), bc.Resolver, &dataset.Dataset{
+			BodyPath:  bc.Base().SourceFile,
+			Structure: bc.Structure,
+		})
 		if err != nil {
 			return err
 		}
@@ -484,8 +504,13 @@ func (bc *BodyComponent) StructuredData() (interface{}, error) {
 	return bc.Value, nil
 }
 
-// WriteTo writes the component as a file to the directory
+// WriteTo writes the component as a file to the directory. Partitioned bodies
+// are written as a "body" directory of partition files
 func (bc *BodyComponent) WriteTo(dirPath string) (targetFile string, err error) {
+	if pbf, ok := bc.BodyFile.(*dsfs.PartitionedBodyFile); ok {
+		targetFile = filepath.Join(dirPath, "body")
+		return targetFile, pbf.WriteDir(targetFile)
+	}
 	if bc.Value == nil {
 		err = bc.LoadAndFill(nil)
 		if err != nil {
@@ -511,7 +536,36 @@ func (bc *BodyComponent) RemoveFrom(dirPath string) error {
 	if err := os.Remove(filepath.Join(dirPath, bodyFilename)); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	return nil
+	return RemoveSourceFile(filepath.Join(dirPath, "body"))
+}
+
+// RemoveSourceFile removes the source file of a component. A directory of body
+// partitions is removed along with its partition files
+func RemoveSourceFile(path string) error {
+	if !dsfs.IsPartitionDir(path) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return dsfs.RemovePartitionDir(path)
+}
+
+// OpenPartitionEntryReader opens an entry reader for a directory of body
+// partitions, determining the schema automatically
+func OpenPartitionEntryReader(dir, format string) (dsio.EntryReader, error) {
+	pbf, err := dsfs.OpenPartitionDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	st := dataset.Structure{Format: format}
+	schema, _, err := detect.Schema(&st, pbf)
+	pbf.Close()
+	if err != nil {
+		return nil, err
+	}
+	st.Schema = schema
+	return dsio.NewEntryReader(&st, pbf.WithStructure(&st))
 }
 
 // OpenEntryReader opens a entry reader for the file, determining the schema automatically
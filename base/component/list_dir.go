@@ -3,12 +3,14 @@ package component
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/base/fill"
 )
 
@@ -34,6 +36,24 @@ func ListDirectoryComponents(dir string) (Component, error) {
 	// Note that this traversal will be in a non-deterministic order, so nothing in this loop
 	// should depend on list order.
 	for _, fi := range finfos {
+		absPath, _ := filepath.Abs(filepath.Join(dir, fi.Name()))
+		if fi.IsDir() {
+			// A "body" directory holds the files of a partitioned body
+			if strings.ToLower(fi.Name()) != "body" {
+				continue
+			}
+			elem, err := partitionDirComponent(absPath)
+			if err != nil {
+				elem.SetErrorAsProblem("parse", err)
+			}
+			if holder := topLevel.GetSubcomponent("body"); holder != nil {
+				markConflict(holder.Base(), absPath)
+				continue
+			}
+			topLevel.SetSubcomponent("body", elem)
+			continue
+		}
+
 		ext := filepath.Ext(fi.Name())
 		componentName := strings.ToLower(strings.TrimSuffix(fi.Name(), ext))
 		allowedExtensions, ok := knownFilenames[componentName]
@@ -45,20 +65,9 @@ func ListDirectoryComponents(dir string) (Component, error) {
 			// Also ignore the file if it has an unknown file extension
 			continue
 		}
-		absPath, _ := filepath.Abs(filepath.Join(dir, fi.Name()))
 		// Check for conflict between this file and those already observed
 		if holder := topLevel.GetSubcomponent(componentName); holder != nil {
-			elem := holder.Base()
-			elem.ProblemKind = "conflict"
-			// Collect a message containing the paths of conflicting files
-			msg := elem.ProblemMessage
-			if msg == "" {
-				msg = filepath.Base(elem.SourceFile)
-			}
-			// Sort the problem files so that the message is deterministic
-			conflictFiles := append(strings.Split(msg, " "), filepath.Base(absPath))
-			sort.Strings(conflictFiles)
-			elem.ProblemMessage = strings.Join(conflictFiles, " ")
+			markConflict(holder.Base(), absPath)
 			continue
 		}
 		topLevel.SetSubcomponent(
@@ -76,6 +85,41 @@ func ListDirectoryComponents(dir string) (Component, error) {
 	return &topLevel, nil
 }
 
+// markConflict records a conflict between a component and another file
+func markConflict(elem *BaseComponent, absPath string) {
+	elem.ProblemKind = "conflict"
+	// Collect a message containing the paths of conflicting files
+	msg := elem.ProblemMessage
+	if msg == "" {
+		msg = filepath.Base(elem.SourceFile)
+	}
+	// Sort the problem files so that the message is deterministic
+	conflictFiles := append(strings.Split(msg, " "), filepath.Base(absPath))
+	sort.Strings(conflictFiles)
+	elem.ProblemMessage = strings.Join(conflictFiles, " ")
+}
+
+// partitionDirComponent describes a directory of body partitions. The
+// modification time is that of the most recently modified partition
+func partitionDirComponent(dir string) (BaseComponent, error) {
+	elem := BaseComponent{SourceFile: dir}
+	parts, err := dsfs.ListPartitionDir(dir)
+	if err != nil {
+		return elem, err
+	}
+	elem.Format = normalizeExtensionFormat(filepath.Ext(parts[0].Name))
+	for _, p := range parts {
+		fi, err := os.Stat(p.Path)
+		if err != nil {
+			return elem, err
+		}
+		if fi.ModTime().After(elem.ModTime) {
+			elem.ModTime = fi.ModTime()
+		}
+	}
+	return elem, nil
+}
+
 // ExpandListedComponents will read whatever is necessary in order to discover all of the components
 // that exist within this observation. For example, if a "dataset" exists, it will be read to find
 // out if it contains a "meta", a "structure", etc. No other components are expanded, but this
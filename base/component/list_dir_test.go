@@ -2,6 +2,9 @@ package component
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -55,6 +58,50 @@ func TestListDirectoryComponents(t *testing.T) {
 	}
 }
 
+func TestListDirectoryComponentsPartitionedBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "list_partitioned_body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bodyDir := filepath.Join(dir, "body")
+	if err := os.Mkdir(bodyDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(bodyDir, "b.csv"), []byte("a,b\n3,4\n"), WritePerm)
+	ioutil.WriteFile(filepath.Join(bodyDir, "a.csv"), []byte("a,b\n1,2\n"), WritePerm)
+
+	components, err := ListDirectoryComponents(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := components.Base().GetSubcomponent("body").(*BodyComponent)
+	if body.SourceFile != bodyDir || body.Format != "csv" {
+		t.Errorf("expected csv body sourced from %q, got %q sourced from %q", bodyDir, body.Format, body.SourceFile)
+	}
+	if err := body.LoadAndFill(nil); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(body.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("[[1,2],[3,4]]", string(data)); diff != "" {
+		t.Errorf("body component (-want +got):\n%s", diff)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "body.csv"), []byte("a,b\n1,2\n"), WritePerm)
+	components, err = ListDirectoryComponents(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body = components.Base().GetSubcomponent("body").(*BodyComponent)
+	if body.ProblemKind != "conflict" || body.ProblemMessage != "body body.csv" {
+		t.Errorf("expected conflict between body directory and body.csv, got %q %q", body.ProblemKind, body.ProblemMessage)
+	}
+}
+
 func TestIsKnownFilename(t *testing.T) {
 	known := GetKnownFilenames()
 
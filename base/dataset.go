@@ -48,7 +48,7 @@ func (l loader) LoadDataset(ctx context.Context, ref dsref.Ref, source string) (
 // set File handlers that are ready for reading
 func OpenDataset(ctx context.Context, fsys qfs.Filesystem, ds *dataset.Dataset) (err error) {
 	if ds.BodyFile() == nil {
-		if err = ds.OpenBodyFile(ctx, fsys); err != nil {
+		if err = openBodyFile(ctx, fsys, ds); err != nil {
 			log.Debug(err)
 			return fmt.Errorf("opening body file: %w", err)
 		}
@@ -96,6 +96,20 @@ func OpenDataset(ctx context.Context, fsys qfs.Filesystem, ds *dataset.Dataset)
 	return
 }
 
+// openBodyFile sets the body file of a dataset. partitioned bodies are opened
+// for reading as a single body
+func openBodyFile(ctx context.Context, fsys qfs.Filesystem, ds *dataset.Dataset) error {
+	if ds.Body != nil || ds.BodyBytes != nil || ds.BodyPath == "" || (fsys == nil && !dsfs.IsPartitionDir(ds.BodyPath)) {
+		return ds.OpenBodyFile(ctx, fsys)
+	}
+	f, err := dsfs.OpenBody(ctx, fsys, ds)
+	if err != nil {
+		return fmt.Errorf("opening dataset.bodyPath '%s': %s", ds.BodyPath, err)
+	}
+	ds.SetBodyFile(f)
+	return nil
+}
+
 func isMerkleDagError(err error) bool {
 	return err.Error() == "merkledag: not found"
 }
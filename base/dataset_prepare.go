@@ -13,6 +13,7 @@ import (
 	"github.com/qri-io/dataset/detect"
 	"github.com/qri-io/dataset/validate"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/dsref"
 	qerr "github.com/qri-io/qri/errors"
 	"github.com/qri-io/qri/logbook"
@@ -201,6 +202,13 @@ func InferStructure(ds *dataset.Dataset) error {
 		ds.Structure.Format = guessedStructure.Format
 	}
 
+	if pbf, ok := body.(*dsfs.PartitionedBodyFile); ok {
+		// partitions can be re-read from the start, and are read according to
+		// the inferred structure
+		ds.SetBodyFile(pbf.WithStructure(ds.Structure))
+		return nil
+	}
+
 	// glue whatever we just read back onto the reader
 	// TODO (b5)- this may ruin readers that transparently depend on a read-closer
 	// we should consider a method on qfs.File that allows this non-destructive read pattern
@@ -7,7 +7,8 @@ import (
 	"github.com/qri-io/qfs"
 )
 
-// LoadBody loads the data this dataset points to from the store
+// LoadBody loads the data this dataset points to from the store. Partitioned
+// bodies are read as a single body
 func LoadBody(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset) (qfs.File, error) {
-	return fs.Get(ctx, ds.BodyPath)
+	return OpenBody(ctx, fs, ds)
 }
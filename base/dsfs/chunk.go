@@ -0,0 +1,238 @@
+package dsfs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/qri-io/qfs"
+)
+
+const (
+	// bodyChunksFilename is the name a chunked body manifest will be written to
+	bodyChunksFilename = "/body_chunks.json"
+	// chunkManifestKind is the qri kind of a body chunk manifest
+	chunkManifestKind = "bc:0"
+)
+
+var (
+	// BodyChunkThreshold is the size in bytes a body must reach before it's
+	// stored as a set of chunks. Smaller bodies are stored as a single file
+	BodyChunkThreshold = 1 << 20 // 1MB
+	// BodyChunkMinSize is the smallest chunk a body will be split into, except
+	// for the last chunk
+	BodyChunkMinSize = 64 << 10 // 64KB
+	// BodyChunkAvgSize is the target chunk size. must be a power of two
+	BodyChunkAvgSize = 256 << 10 // 256KB
+	// BodyChunkMaxSize is the largest chunk a body will be split into
+	BodyChunkMaxSize = 1 << 20 // 1MB
+)
+
+// BodyChunk is a stored piece of a chunked body
+type BodyChunk struct {
+	// Path to the chunk contents
+	Path string `json:"path"`
+	// Size of the chunk in bytes
+	Size int `json:"size"`
+}
+
+// ChunkManifest lists the chunks that make up a body in read order. Chunk
+// boundaries are picked by the contents of the body, so an edit only changes
+// the chunks it touches. Unchanged chunks are stored at the same path, and
+// shared between versions
+type ChunkManifest struct {
+	Qri    string      `json:"qri"`
+	Format string      `json:"format"`
+	Chunks []BodyChunk `json:"chunks"`
+}
+
+// Length returns the combined size of all chunks in bytes
+func (m *ChunkManifest) Length() (l int) {
+	for _, c := range m.Chunks {
+		l += c.Size
+	}
+	return l
+}
+
+// ChunkedBodyFile reads the chunks listed in a manifest as a single body
+type ChunkedBodyFile struct {
+	manifest *ChunkManifest
+	open     func(path string) (io.ReadCloser, error)
+
+	idx int
+	cur io.ReadCloser
+}
+
+var _ qfs.File = (*ChunkedBodyFile)(nil)
+
+// OpenChunkManifest reads the chunks listed in a manifest from a filesystem
+// as a single body
+func OpenChunkManifest(ctx context.Context, fs qfs.PathResolver, m *ChunkManifest) *ChunkedBodyFile {
+	return &ChunkedBodyFile{
+		manifest: m,
+		open: func(path string) (io.ReadCloser, error) {
+			return fs.Get(ctx, path)
+		},
+	}
+}
+
+// Manifest returns the manifest of chunks this body reads from
+func (cbf *ChunkedBodyFile) Manifest() *ChunkManifest {
+	return cbf.manifest
+}
+
+// Read implements the io.Reader interface, reading chunks in order
+func (cbf *ChunkedBodyFile) Read(p []byte) (int, error) {
+	for {
+		if cbf.cur == nil {
+			if cbf.idx >= len(cbf.manifest.Chunks) {
+				return 0, io.EOF
+			}
+			rc, err := cbf.open(cbf.manifest.Chunks[cbf.idx].Path)
+			if err != nil {
+				return 0, fmt.Errorf("opening body chunk %d: %w", cbf.idx, err)
+			}
+			cbf.idx++
+			cbf.cur = rc
+		}
+
+		n, err := cbf.cur.Read(p)
+		if err == io.EOF {
+			cbf.cur.Close()
+			cbf.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close closes the chunk currently being read
+func (cbf *ChunkedBodyFile) Close() error {
+	if cbf.cur != nil {
+		err := cbf.cur.Close()
+		cbf.cur = nil
+		return err
+	}
+	return nil
+}
+
+// FileName returns the name of the body, eg: "body.csv"
+func (cbf *ChunkedBodyFile) FileName() string {
+	return fmt.Sprintf("body.%s", cbf.manifest.Format)
+}
+
+// FullPath returns the name of the body, chunked bodies have no single path
+func (cbf *ChunkedBodyFile) FullPath() string {
+	return cbf.FileName()
+}
+
+// IsDirectory returns false, chunks are read as a single file
+func (cbf *ChunkedBodyFile) IsDirectory() bool {
+	return false
+}
+
+// NextFile returns qfs.ErrNotDirectory
+func (cbf *ChunkedBodyFile) NextFile() (qfs.File, error) {
+	return nil, qfs.ErrNotDirectory
+}
+
+// MediaType returns the media type of the body format
+func (cbf *ChunkedBodyFile) MediaType() string {
+	return qfs.NewMemfileBytes(cbf.FileName(), nil).MediaType()
+}
+
+// ModTime returns the zero time
+func (cbf *ChunkedBodyFile) ModTime() time.Time {
+	return time.Time{}
+}
+
+// storeChunks splits body data into content-defined chunks, writing each
+// chunk to a filesystem
+func storeChunks(ctx context.Context, fs qfs.Filesystem, r io.Reader) ([]BodyChunk, error) {
+	var (
+		chunks []BodyChunk
+		chnk   = newChunker(r)
+	)
+	for {
+		data, err := chnk.Next()
+		if err == io.EOF {
+			return chunks, nil
+		} else if err != nil {
+			return nil, err
+		}
+		path, err := fs.Put(ctx, qfs.NewMemfileBytes("chunk", data))
+		if err != nil {
+			return nil, fmt.Errorf("storing body chunk %d: %w", len(chunks), err)
+		}
+		chunks = append(chunks, BodyChunk{Path: path, Size: len(data)})
+	}
+}
+
+// chunker splits a stream of bytes into chunks at positions picked by a
+// rolling "gear" hash of the preceding bytes. inserting or removing bytes
+// only moves the boundaries of nearby chunks, later boundaries line up with
+// the ones picked before the edit
+type chunker struct {
+	r    *bufio.Reader
+	min  int
+	max  int
+	mask uint64
+	buf  []byte
+}
+
+func newChunker(r io.Reader) *chunker {
+	return &chunker{
+		r:    bufio.NewReader(r),
+		min:  BodyChunkMinSize,
+		max:  BodyChunkMaxSize,
+		mask: uint64(BodyChunkAvgSize - 1),
+		buf:  make([]byte, 0, BodyChunkMaxSize),
+	}
+}
+
+// Next returns the next chunk of data, returning io.EOF when the stream is
+// exhausted. the returned slice is only valid until the next call to Next
+func (c *chunker) Next() ([]byte, error) {
+	c.buf = c.buf[:0]
+	var hash uint64
+	for len(c.buf) < c.max {
+		b, err := c.r.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		c.buf = append(c.buf, b)
+		if len(c.buf) < c.min {
+			continue
+		}
+		hash = (hash << 1) + gearTable[b]
+		if hash&c.mask == 0 {
+			break
+		}
+	}
+	if len(c.buf) == 0 {
+		return nil, io.EOF
+	}
+	return c.buf, nil
+}
+
+// gearTable maps each byte value to a pseudo-random 64 bit value. the table
+// must never change, changing it changes where every body is chunked
+var gearTable = func() (table [256]uint64) {
+	// splitmix64 with a fixed seed
+	seed := uint64(0x71726920636863) // "qri chc"
+	for i := range table {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
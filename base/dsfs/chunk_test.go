@@ -0,0 +1,197 @@
+package dsfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	testPeers "github.com/qri-io/qri/config/test"
+)
+
+func TestChunkerBoundariesResync(t *testing.T) {
+	defer setSmallChunkSizes()()
+
+	data := testChunkCSV(2000, -1)
+	edited := testChunkCSV(2000, 1000)
+
+	a := chunkContents(t, data)
+	b := chunkContents(t, edited)
+	if len(a) < 4 {
+		t.Fatalf("expected data to be split into several chunks, got %d", len(a))
+	}
+
+	shared := map[string]bool{}
+	for _, c := range a {
+		shared[c] = true
+	}
+	changed := 0
+	for _, c := range b {
+		if !shared[c] {
+			changed++
+		}
+	}
+	// an edit can shift the boundaries of chunks following the edited chunk
+	// before boundaries line up again
+	if changed > 3 {
+		t.Errorf("expected a single row edit to change at most 3 chunks, changed %d of %d", changed, len(b))
+	}
+}
+
+func TestCreateDatasetChunkedBody(t *testing.T) {
+	defer setSmallChunkSizes()()
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+
+	prevTs := Timestamp
+	defer func() { Timestamp = prevTs }()
+	Timestamp = func() time.Time { return time.Date(2001, 01, 01, 01, 01, 01, 01, time.UTC) }
+
+	pk := testPeers.GetTestPeerInfo(10).PrivKey
+	newDs := func(body []byte) *dataset.Dataset {
+		ds := &dataset.Dataset{
+			Commit: &dataset.Commit{Title: "chunks"},
+			Structure: &dataset.Structure{
+				Format:       "csv",
+				FormatConfig: map[string]interface{}{"headerRow": true},
+				Schema:       BaseTabularSchema,
+			},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", body))
+		return ds
+	}
+
+	data := testChunkCSV(2000, -1)
+	path, err := CreateDataset(ctx, fs, fs, newDs(data), nil, pk, SaveSwitches{})
+	if err != nil {
+		t.Fatalf("CreateDataset: %s", err)
+	}
+	first, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Structure.Entries != 2000 {
+		t.Errorf("entries mismatch. want: 2000, got: %d", first.Structure.Entries)
+	}
+	if first.Structure.Length != len(data) {
+		t.Errorf("length mismatch. want: %d, got: %d", len(data), first.Structure.Length)
+	}
+	firstBlocks, err := BodyBlockPaths(ctx, fs, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(firstBlocks) < 4 {
+		t.Fatalf("expected body to be stored as several chunks, got %d", len(firstBlocks))
+	}
+
+	edited := testChunkCSV(2000, 1000)
+	prev, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevBody, err := LoadBody(ctx, fs, prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev.SetBodyFile(prevBody)
+	if path, err = CreateDataset(ctx, fs, fs, newDs(edited), prev, pk, SaveSwitches{}); err != nil {
+		t.Fatalf("CreateDataset: %s", err)
+	}
+	second, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := LoadBody(ctx, fs, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(edited, got) {
+		t.Errorf("chunked body doesn't match saved body")
+	}
+
+	secondBlocks, err := BodyBlockPaths(ctx, fs, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := map[string]bool{}
+	for _, p := range firstBlocks {
+		shared[p] = true
+	}
+	changed := 0
+	for _, p := range secondBlocks {
+		if !shared[p] {
+			changed++
+		}
+	}
+	if changed == 0 || changed > 3 {
+		t.Errorf("expected a single row edit to store between 1 and 3 new chunks, stored %d of %d", changed, len(secondBlocks))
+	}
+
+	small := []byte("city,pop\ntoronto,40000000\n")
+	if path, err = CreateDataset(ctx, fs, fs, newDs(small), nil, pk, SaveSwitches{}); err != nil {
+		t.Fatalf("CreateDataset: %s", err)
+	}
+	third, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blocks, err := BodyBlockPaths(ctx, fs, third); err != nil || blocks != nil {
+		t.Errorf("expected small body to be stored as a single file. blocks: %v err: %v", blocks, err)
+	}
+	body, err = LoadBody(ctx, fs, third)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = ioutil.ReadAll(body); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(small), string(got)); diff != "" {
+		t.Errorf("small body mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// setSmallChunkSizes shrinks chunk sizes so tests can work with small bodies,
+// returning a function that restores the defaults
+func setSmallChunkSizes() func() {
+	threshold, min, avg, max := BodyChunkThreshold, BodyChunkMinSize, BodyChunkAvgSize, BodyChunkMaxSize
+	BodyChunkThreshold, BodyChunkMinSize, BodyChunkAvgSize, BodyChunkMaxSize = 4096, 256, 1024, 4096
+	return func() {
+		BodyChunkThreshold, BodyChunkMinSize, BodyChunkAvgSize, BodyChunkMaxSize = threshold, min, avg, max
+	}
+}
+
+// testChunkCSV generates a csv body with a header & n rows, changing the
+// value of the row at index edit
+func testChunkCSV(n, edit int) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("city,pop\n")
+	for i := 0; i < n; i++ {
+		pop := i * 7919 % 100003
+		if i == edit {
+			pop = -1
+		}
+		fmt.Fprintf(buf, "city_%d,%d\n", i, pop)
+	}
+	return buf.Bytes()
+}
+
+func chunkContents(t *testing.T, data []byte) (chunks []string) {
+	chnk := newChunker(bytes.NewReader(data))
+	for {
+		c, err := chnk.Next()
+		if err != nil {
+			return chunks
+		}
+		chunks = append(chunks, string(c))
+	}
+}
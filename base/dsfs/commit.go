@@ -73,6 +73,10 @@ func commitFileAddFunc(privKey crypto.PrivKey) addWriteFileFunc {
 			if cff, ok := wfs.body.(*computeFieldsFile); ok {
 				updateScriptPaths(ds, added)
 
+				if cff.sw.Append {
+					cff.appendCommitDescription()
+				}
+
 				if err := confirmByteChangesExist(cff.ds, cff.prev, added, wfs.body.FullPath(), cff.sw.ForceIfNoChanges); err != nil {
 					return nil, fmt.Errorf("error saving: %w", err)
 				}
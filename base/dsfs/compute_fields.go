@@ -1,10 +1,14 @@
 package dsfs
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,9 +23,11 @@ import (
 type computeFieldsFile struct {
 	*sync.Mutex
 
-	fs qfs.Filesystem
-	pk crypto.PrivKey
-	sw SaveSwitches
+	ctx  context.Context
+	fs   qfs.Filesystem
+	dest qfs.Filesystem
+	pk   crypto.PrivKey
+	sw   SaveSwitches
 
 	ds, prev *dataset.Dataset
 
@@ -39,6 +45,19 @@ type computeFieldsFile struct {
 	teeReader  io.Reader
 	done       chan error
 
+	// partitioned bodies are processed in full, then read as a manifest of
+	// stored partitions
+	partitions     *PartitionedBodyFile
+	manifestReader io.Reader
+	// large bodies are split into chunks as they're read, then read as a
+	// manifest of stored chunks
+	chunked bool
+
+	// appended saves write the previous body followed by new rows, only the
+	// new rows are processed, preceded by header
+	header   string
+	appended int
+
 	batches   int
 	bytesRead int
 }
@@ -48,7 +67,7 @@ var (
 	_ statsComponentFile = (*computeFieldsFile)(nil)
 )
 
-func newComputeFieldsFile(ctx context.Context, dsLk *sync.Mutex, fs qfs.Filesystem, pk crypto.PrivKey, ds, prev *dataset.Dataset, sw SaveSwitches) (qfs.File, error) {
+func newComputeFieldsFile(ctx context.Context, dsLk *sync.Mutex, fs, dest qfs.Filesystem, pk crypto.PrivKey, ds, prev *dataset.Dataset, sw SaveSwitches) (qfs.File, error) {
 	var (
 		bf     = ds.BodyFile()
 		bfPrev qfs.File
@@ -67,12 +86,46 @@ func newComputeFieldsFile(ctx context.Context, dsLk *sync.Mutex, fs qfs.Filesyst
 		bf = bfPrev
 	}
 
+	_, partitioned := bf.(*PartitionedBodyFile)
+
 	pr, pw := io.Pipe()
-	tr := io.TeeReader(bf, pw)
+	var (
+		tr      io.Reader
+		header  string
+		chunked bool
+	)
+	if !sw.Append {
+		br := bufio.NewReaderSize(bf, BodyChunkThreshold)
+		chunked = !partitioned && exceedsSize(br, BodyChunkThreshold)
+		tr = io.TeeReader(br, pw)
+	}
+
+	if sw.Append {
+		if bfPrev == nil || bf == bfPrev {
+			return nil, fmt.Errorf("appending requires a previous body and rows to append")
+		}
+		if ds.Structure == nil || ds.Structure.Format != dataset.CSVDataFormat.String() {
+			return nil, fmt.Errorf("appending is only supported for csv bodies")
+		}
+		body, rows, hdr, err := appendReaders(bfPrev, bf, hasHeaderRow(ds.Structure))
+		if err != nil {
+			return nil, err
+		}
+		header = hdr
+		rowsBuf := bufio.NewReaderSize(rows, BodyChunkThreshold)
+		if prev.Structure != nil && prev.Structure.Length >= BodyChunkThreshold {
+			chunked = true
+		} else if prev.Structure != nil {
+			chunked = exceedsSize(rowsBuf, BodyChunkThreshold-prev.Structure.Length)
+		}
+		tr = io.MultiReader(body, io.TeeReader(rowsBuf, pw))
+	}
 
 	cff := &computeFieldsFile{
 		Mutex:      dsLk,
+		ctx:        ctx,
 		fs:         fs,
+		dest:       dest,
 		pk:         pk,
 		sw:         sw,
 		ds:         ds,
@@ -81,8 +134,16 @@ func newComputeFieldsFile(ctx context.Context, dsLk *sync.Mutex, fs qfs.Filesyst
 		pipeReader: pr,
 		pipeWriter: pw,
 		teeReader:  tr,
+		header:     header,
+		chunked:    chunked,
 		done:       make(chan error),
 	}
+	if pbf, ok := bf.(*PartitionedBodyFile); ok {
+		if pbf.Manifest() == nil {
+			return nil, fmt.Errorf("body partitions must be stored before computing fields")
+		}
+		cff.partitions = pbf
+	}
 
 	go cff.handleRows(ctx)
 
@@ -90,10 +151,16 @@ func newComputeFieldsFile(ctx context.Context, dsLk *sync.Mutex, fs qfs.Filesyst
 }
 
 func (cff *computeFieldsFile) FileName() string {
-	return fmt.Sprintf("/body.%s", cff.ds.Structure.Format)
+	return cff.FullPath()
 }
 
 func (cff *computeFieldsFile) FullPath() string {
+	if cff.partitions != nil {
+		return bodyPartitionsFilename
+	}
+	if cff.chunked {
+		return bodyChunksFilename
+	}
 	return fmt.Sprintf("/body.%s", cff.ds.Structure.Format)
 }
 
@@ -114,6 +181,9 @@ func (cff *computeFieldsFile) NextFile() (qfs.File, error) {
 }
 
 func (cff *computeFieldsFile) Read(p []byte) (n int, err error) {
+	if cff.partitions != nil || cff.chunked {
+		return cff.readManifest(p)
+	}
 	n, err = cff.teeReader.Read(p)
 
 	cff.Lock()
@@ -127,6 +197,66 @@ func (cff *computeFieldsFile) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
+// readManifest processes the entire body, then reads a manifest of the stored
+// partitions or chunks as the body file contents
+func (cff *computeFieldsFile) readManifest(p []byte) (int, error) {
+	if cff.manifestReader == nil {
+		var (
+			manifest interface{}
+			body     = &countingBodyReader{cff}
+		)
+		if cff.chunked {
+			chunks, err := storeChunks(cff.ctx, cff.dest, body)
+			if err != nil {
+				cff.pipeWriter.CloseWithError(err)
+				return 0, err
+			}
+			manifest = &ChunkManifest{
+				Qri:    chunkManifestKind,
+				Format: cff.ds.Structure.Format,
+				Chunks: chunks,
+			}
+		} else {
+			if _, err := io.Copy(ioutil.Discard, body); err != nil {
+				cff.pipeWriter.CloseWithError(err)
+				return 0, err
+			}
+			manifest = cff.partitions.Manifest()
+		}
+		cff.pipeWriter.Close()
+
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			return 0, err
+		}
+		cff.manifestReader = bytes.NewReader(data)
+	}
+	return cff.manifestReader.Read(p)
+}
+
+// countingBodyReader reads the body of a computeFieldsFile, counting bytes read
+type countingBodyReader struct {
+	cff *computeFieldsFile
+}
+
+func (r *countingBodyReader) Read(p []byte) (int, error) {
+	n, err := r.cff.teeReader.Read(p)
+	r.cff.Lock()
+	r.cff.bytesRead += n
+	r.cff.Unlock()
+	return n, err
+}
+
+// exceedsSize returns true if at least size bytes can be read from br,
+// without consuming any data. br must be able to buffer size bytes
+func exceedsSize(br *bufio.Reader, size int) bool {
+	if size <= 0 {
+		return true
+	}
+	_, err := br.Peek(size)
+	return err == nil
+}
+
 func (cff *computeFieldsFile) Close() error {
 	cff.pipeWriter.Close()
 	return nil
@@ -145,12 +275,88 @@ type statsComponentFile interface {
 }
 
 func (cff *computeFieldsFile) StatsComponent() (*dataset.Stats, error) {
+	stats := dsstats.ToMap(cff.acc)
+	if cff.sw.Append {
+		var prev interface{}
+		if cff.prev.Stats != nil {
+			prev = cff.prev.Stats.Stats
+		}
+		merged, err := mergeAppendedStats(prev, stats)
+		if err != nil {
+			return nil, fmt.Errorf("merging appended stats: %w", err)
+		}
+		stats = merged
+	}
 	return &dataset.Stats{
 		Qri:   dataset.KindStats.String(),
-		Stats: dsstats.ToMap(cff.acc),
+		Stats: stats,
 	}, nil
 }
 
+// appendCommitDescription describes an append save by the number of rows
+// appended, filling in any commit fields that aren't already set
+func (cff *computeFieldsFile) appendCommitDescription() {
+	desc := fmt.Sprintf("appended %d rows", cff.appended)
+	if cff.appended == 1 {
+		desc = "appended 1 row"
+	}
+	if cff.ds.Commit.Title == "" {
+		cff.ds.Commit.Title = desc
+	}
+	if cff.ds.Commit.Message == "" {
+		cff.ds.Commit.Message = desc
+	}
+}
+
+// appendReaders splits an append save into a reader of the combined body and
+// a reader of just the appended rows, which are read through the body reader.
+// when the body has a header row, a matching header leading the appended rows
+// is dropped, and the header is returned for processing appended rows
+func appendReaders(prev, rows io.Reader, headerRow bool) (body, appended io.Reader, header string, err error) {
+	prevBuf := bufio.NewReader(prev)
+	rowsBuf := bufio.NewReader(rows)
+	body = &terminatedReader{r: prevBuf}
+	appended = rowsBuf
+	if !headerRow {
+		return body, appended, "", nil
+	}
+
+	header, err = prevBuf.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, nil, "", err
+	}
+	body = &terminatedReader{r: io.MultiReader(strings.NewReader(header), prevBuf)}
+	header = strings.TrimRight(header, "\r\n")
+
+	first, err := rowsBuf.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, nil, "", err
+	}
+	if strings.TrimRight(first, "\r\n") != header {
+		appended = io.MultiReader(strings.NewReader(first), rowsBuf)
+	}
+	return body, appended, header + "\n", nil
+}
+
+// terminatedReader ensures non-empty content read from r ends with a newline
+type terminatedReader struct {
+	r    io.Reader
+	last byte
+}
+
+func (tr *terminatedReader) Read(p []byte) (int, error) {
+	n, err := tr.r.Read(p)
+	if n > 0 {
+		tr.last = p[n-1]
+	}
+	if err == io.EOF && tr.last != 0 && tr.last != '\n' {
+		tr.r = strings.NewReader("\n")
+		tr.last = '\n'
+		err = nil
+	}
+	return n, err
+}
+
 func (cff *computeFieldsFile) handleRows(ctx context.Context) {
 	var (
 		batchBuf      *dsio.EntryBuffer
@@ -160,7 +366,12 @@ func (cff *computeFieldsFile) handleRows(ctx context.Context) {
 		depth         = 0
 	)
 
-	r, err := dsio.NewEntryReader(st, cff.pipeReader)
+	var rows io.Reader = cff.pipeReader
+	if cff.header != "" {
+		rows = io.MultiReader(strings.NewReader(cff.header), cff.pipeReader)
+	}
+
+	r, err := dsio.NewEntryReader(st, rows)
 	if err != nil {
 		log.Debugf("creating entry reader: %s", err)
 		cff.done <- fmt.Errorf("creating entry reader: %w", err)
@@ -188,13 +399,16 @@ func (cff *computeFieldsFile) handleRows(ctx context.Context) {
 		return
 	}
 
-	cff.diffMessageBuf, err = dsio.NewEntryBuffer(&dataset.Structure{
-		Format: "json",
-		Schema: st.Schema,
-	})
-	if err != nil {
-		cff.done <- fmt.Errorf("allocating data buffer: %w", err)
-		return
+	// appended rows alone can't be diffed against the previous body
+	if !cff.sw.Append {
+		cff.diffMessageBuf, err = dsio.NewEntryBuffer(&dataset.Structure{
+			Format: "json",
+			Schema: st.Schema,
+		})
+		if err != nil {
+			cff.done <- fmt.Errorf("allocating data buffer: %w", err)
+			return
+		}
 	}
 
 	go func() {
@@ -268,6 +482,17 @@ func (cff *computeFieldsFile) handleRows(ctx context.Context) {
 		cff.ds.Structure.Entries = entries
 		cff.ds.Structure.Depth = depth + 1 // need to add one for the original enclosure
 		cff.ds.Structure.Length = cff.bytesRead
+		if cff.sw.Append {
+			// only appended rows were processed, add in the previous body's values
+			cff.appended = entries
+			if prevSt := cff.prev.Structure; prevSt != nil {
+				cff.ds.Structure.ErrCount += prevSt.ErrCount
+				cff.ds.Structure.Entries += prevSt.Entries
+				if prevSt.Depth > cff.ds.Structure.Depth {
+					cff.ds.Structure.Depth = prevSt.Depth
+				}
+			}
+		}
 
 		// as we're using a manual setup on the EntryReader we also need
 		// to manually close the accumulator to finalize results before write
@@ -2,6 +2,7 @@ package dsfs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -11,6 +12,7 @@ import (
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/validate"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/dsref"
 )
 
 // number of entries to per batch when processing body data in WriteDataset
@@ -68,6 +70,13 @@ func LoadDatasetRefs(ctx context.Context, fs qfs.Filesystem, path string) (*data
 	pathWithBasename := PackageFilepath(fs, path, PackageFileDataset)
 	log.Debugf("getting %s", pathWithBasename)
 	data, err := fileBytes(fs.Get(ctx, pathWithBasename))
+	if errors.Is(err, qfs.ErrNotFound) {
+		// the dataset may be stored under the other version of its CID
+		if alt, ok := dsref.AltPath(path); ok {
+			log.Debugf("getting %s", alt)
+			data, err = fileBytes(fs.Get(ctx, PackageFilepath(fs, alt, PackageFileDataset)))
+		}
+	}
 	if err != nil {
 		log.Debug(err.Error())
 		return nil, fmt.Errorf("reading %s file: %w", PackageFileDataset.String(), err)
@@ -127,8 +136,21 @@ type SaveSwitches struct {
 	FileHint string
 	// Drop is a string of components to remove before saving
 	Drop string
+	// Append is whether the body file holds rows to append to the previous body
+	Append bool
+	// ReadmeHook rewrites the readme script of the version being saved once
+	// its body, structure & stats are computed
+	ReadmeHook ReadmeHook
+	// Reverts is the path of an earlier version the save restores, recorded in
+	// the history of the dataset
+	Reverts string
 }
 
+// ReadmeHook is a function that rewrites a readme script while a dataset is
+// written. ds has the computed body, structure & stats of the version being
+// written, but no commit title or path
+type ReadmeHook func(ctx context.Context, ds *dataset.Dataset, script []byte) ([]byte, error)
+
 // CreateDataset places a dataset into the store.
 // Store is where we're going to store the data
 // Dataset to be saved
@@ -189,10 +211,20 @@ func CreateDataset(
 		bf = bfPrev
 	}
 
+	if pbf, ok := bf.(*PartitionedBodyFile); ok {
+		// partitions are stored individually so unchanged partitions are shared
+		// between versions. the body itself is written as a manifest
+		stored, err := pbf.WithStructure(ds.Structure).Store(ctx, destination)
+		if err != nil {
+			return "", err
+		}
+		ds.SetBodyFile(stored)
+	}
+
 	// lock for editing dataset pointer
 	var dsLk = &sync.Mutex{}
 
-	bodyFile, err := newComputeFieldsFile(ctx, dsLk, source, pk, ds, prev, sw)
+	bodyFile, err := newComputeFieldsFile(ctx, dsLk, source, destination, pk, ds, prev, sw)
 	if err != nil {
 		return "", err
 	}
@@ -236,7 +268,7 @@ func WriteDataset(
 		addTransformFile,
 		structureFileAddFunc(destination),
 		addStatsFile,
-		addReadmeFile,
+		readmeFileAddFunc(sw),
 		vizFilesAddFunc(destination, sw),
 		commitFileAddFunc(pk),
 		addDatasetFile,
@@ -259,14 +291,14 @@ type writeFiles struct {
 	meta qfs.File // no deps
 	body qfs.File // no deps
 
-	readmeScript    qfs.File // no deps
 	vizScript       qfs.File // no deps
 	transformScript qfs.File // no deps
 
-	transform   qfs.File // requires transformScript if it exists
-	structure   qfs.File // requires body if it exists
-	stats       qfs.File // requires body, structure if they exist
-	vizRendered qfs.File // requires body, meta, transform, structure, stats, readme if they exist
+	transform    qfs.File // requires transformScript if it exists
+	structure    qfs.File // requires body if it exists
+	stats        qfs.File // requires body, structure if they exist
+	readmeScript qfs.File // requires body, structure, stats if they exist & a readme hook is set
+	vizRendered  qfs.File // requires body, meta, transform, structure, stats, readme if they exist
 
 	commit  qfs.File // requires meta, transform, body, structure, stats, readme, vizScript, vizRendered if they exist
 	dataset qfs.File // requires all other components
@@ -277,12 +309,12 @@ func (wfs *writeFiles) files() []qfs.File {
 	candidates := []qfs.File{
 		wfs.meta,
 		wfs.body,
-		wfs.readmeScript,
 		wfs.vizScript,
 		wfs.transformScript,
 		wfs.transform,
 		wfs.structure,
 		wfs.stats,
+		wfs.readmeScript,
 		wfs.vizRendered,
 		wfs.commit,
 		wfs.dataset,
@@ -20,6 +20,7 @@ import (
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base/toqtype"
 	testPeers "github.com/qri-io/qri/config/test"
+	"github.com/qri-io/qri/dsref"
 )
 
 func TestLoadDataset(t *testing.T) {
@@ -63,6 +64,22 @@ func TestLoadDataset(t *testing.T) {
 		t.Errorf("path should not contain the basename of the dataset file: %s", loadedDataset.Path)
 	}
 
+	// datasets stored under a CIDv0 can be loaded by the CIDv1 of their path
+	v1Path, err := dsref.CIDv1Path(apath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1Dataset, err := LoadDataset(ctx, fs, v1Path)
+	if err != nil {
+		t.Fatalf("loading dataset by CIDv1 path: %s", err)
+	}
+	if v1Dataset.Path != v1Path {
+		t.Errorf("expected dataset loaded by CIDv1 to keep path %q, got %q", v1Path, v1Dataset.Path)
+	}
+	if v1Dataset.Commit.Title != loadedDataset.Commit.Title {
+		t.Errorf("expected CIDv1 dataset to match loaded dataset. want commit title %q, got %q", loadedDataset.Commit.Title, v1Dataset.Commit.Title)
+	}
+
 	cases := []struct {
 		ds  *dataset.Dataset
 		err string
@@ -6,6 +6,7 @@ import (
 
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qfs/muxfs"
+	"github.com/qri-io/qri/dsref"
 )
 
 const (
@@ -98,8 +99,12 @@ func (p PackageFile) Filename() string {
 	return fmt.Sprintf("/%s", filenames[p])
 }
 
-// GetHashBase strips paths to return just the hash
+// GetHashBase strips paths to return just the hash. Hashes are the second
+// segment of /network/hash paths on any network
 func GetHashBase(in string) string {
+	if network, hash, _, err := dsref.SplitPath(in); err == nil && !dsref.IsContentHash(network) {
+		return hash
+	}
 	in = strings.TrimLeft(in, "/")
 	for _, fsType := range muxfs.KnownFSTypes() {
 		in = strings.TrimPrefix(in, fsType)
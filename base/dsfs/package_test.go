@@ -52,6 +52,25 @@ func TestPackageFilepath(t *testing.T) {
 	}
 }
 
+func TestGetHashBase(t *testing.T) {
+	cases := []struct {
+		in, expect string
+	}{
+		{"QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M", "QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M"},
+		{"/ipfs/QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M", "QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M"},
+		{"/mem/QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M/dataset.json", "QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M"},
+		{"/QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M/dataset.json", "QmZfwmhbcgSDGqGaoMMYx8jxBGauZw75zPjnZAyfwPso7M"},
+		{"/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu/dataset.json", "bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu"},
+		{"/arweave/Some1Hash/dataset.json", "Some1Hash"},
+	}
+
+	for i, c := range cases {
+		if got := GetHashBase(c.in); got != c.expect {
+			t.Errorf("case %d GetHashBase(%q) mismatch. want %q, got %q", i, c.in, c.expect, got)
+		}
+	}
+}
+
 func makeTestIPFSRepo(ctx context.Context, path string) (fs *qipfs.Filestore, destroy func(), err error) {
 	if path == "" {
 		tmp, err := ioutil.TempDir("", "temp-ipfs-repo")
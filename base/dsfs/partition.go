@@ -0,0 +1,518 @@
+package dsfs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+const (
+	// bodyPartitionsFilename is the name a partitioned body manifest will be
+	// written to
+	bodyPartitionsFilename = "/body_partitions.json"
+	// partitionManifestKind is the qri kind of a body partition manifest
+	partitionManifestKind = "bp:0"
+
+	// BodyManifestConfigKey is the structure format config key that records
+	// the kind of manifest a body is stored as. Bodies stored as a single file
+	// don't set this key
+	BodyManifestConfigKey = "bodyManifest"
+	// bodyManifestPartitions marks a body stored as a partition manifest
+	bodyManifestPartitions = "partitions"
+	// bodyManifestChunks marks a body stored as a chunk manifest
+	bodyManifestChunks = "chunks"
+)
+
+// BodyPartition is a single file in a partitioned body
+type BodyPartition struct {
+	// Name of the partition file, eg: "2020-01.csv"
+	Name string `json:"name"`
+	// Path to the partition file contents
+	Path string `json:"path"`
+}
+
+// PartitionManifest lists the files that make up a partitioned body in read
+// order. Partitioned bodies are stored as a manifest, with each partition
+// stored as a separate file so unchanged partitions are shared between
+// versions
+type PartitionManifest struct {
+	Qri        string          `json:"qri"`
+	Partitions []BodyPartition `json:"partitions"`
+}
+
+// PartitionedBodyFile reads an ordered set of partition files as a single
+// continuous body. When the body has a header row, partitions after the first
+// may repeat the header of the first partition. Repeated headers are dropped
+// so readers see one header for the entire body
+type PartitionedBodyFile struct {
+	name       string
+	partitions []BodyPartition
+	open       func(path string) (io.ReadCloser, error)
+	// stored is true when partition paths refer to stored files
+	stored    bool
+	headerRow bool
+
+	idx     int
+	cur     *bufio.Reader
+	closer  io.Closer
+	header  []byte
+	lastEOL bool
+}
+
+var _ qfs.File = (*PartitionedBodyFile)(nil)
+
+// IsPartitionDir returns true if path is a local directory, which is read as
+// a partitioned body
+func IsPartitionDir(path string) bool {
+	if qfs.PathKind(path) != "local" {
+		return false
+	}
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// ListPartitionDir returns the partition files in a local directory, sorted by
+// filename. Hidden files and subdirectories are ignored. All partitions must
+// share the same file extension
+func ListPartitionDir(dir string) ([]BodyPartition, error) {
+	finfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		parts []BodyPartition
+		ext   string
+	)
+	for _, fi := range finfos {
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		if ext == "" {
+			ext = filepath.Ext(fi.Name())
+		} else if filepath.Ext(fi.Name()) != ext {
+			return nil, fmt.Errorf("body partitions must all have the same file extension, found %q and %q", ext, filepath.Ext(fi.Name()))
+		}
+		parts = append(parts, BodyPartition{Name: fi.Name(), Path: filepath.Join(dir, fi.Name())})
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("body directory %q has no partition files", dir)
+	}
+	if ext != ".csv" {
+		return nil, fmt.Errorf("partitioned bodies must be csv files, found %q", ext)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Name < parts[j].Name })
+	return parts, nil
+}
+
+// OpenPartitionDir opens a local directory of partition files as a single body
+func OpenPartitionDir(dir string) (*PartitionedBodyFile, error) {
+	parts, err := ListPartitionDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &PartitionedBodyFile{
+		name:       partitionedBodyName(parts),
+		partitions: parts,
+		open: func(path string) (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}, nil
+}
+
+// OpenBody opens the body of a dataset for reading. Bodies that are local
+// directories or stored partition manifests are read as a single continuous
+// body
+func OpenBody(ctx context.Context, fs qfs.PathResolver, ds *dataset.Dataset) (qfs.File, error) {
+	return openBody(ctx, fs, ds, bodyManifest(ds.Structure))
+}
+
+// openBody opens a dataset body, using the kind of manifest the body was
+// stored as to tell manifests from plain body files
+func openBody(ctx context.Context, fs qfs.PathResolver, ds *dataset.Dataset, manifest string) (qfs.File, error) {
+	if IsPartitionDir(ds.BodyPath) {
+		pbf, err := OpenPartitionDir(ds.BodyPath)
+		if err != nil {
+			return nil, err
+		}
+		return pbf.WithStructure(ds.Structure), nil
+	}
+
+	f, err := fs.Get(ctx, ds.BodyPath)
+	if err != nil {
+		return nil, err
+	}
+	switch manifest {
+	case bodyManifestPartitions:
+		defer f.Close()
+		m := &PartitionManifest{}
+		if err := json.NewDecoder(f).Decode(m); err != nil {
+			return nil, fmt.Errorf("reading body partition manifest: %w", err)
+		}
+		return OpenPartitionManifest(ctx, fs, m).WithStructure(ds.Structure), nil
+	case bodyManifestChunks:
+		defer f.Close()
+		m := &ChunkManifest{}
+		if err := json.NewDecoder(f).Decode(m); err != nil {
+			return nil, fmt.Errorf("reading body chunk manifest: %w", err)
+		}
+		return OpenChunkManifest(ctx, fs, m), nil
+	default:
+		return f, nil
+	}
+}
+
+// bodyManifest returns the kind of manifest a body was stored as, recorded in
+// the format config of the dataset structure. Returns an empty string for
+// bodies stored as a single file
+func bodyManifest(st *dataset.Structure) string {
+	if st == nil || st.FormatConfig == nil {
+		return ""
+	}
+	manifest, _ := st.FormatConfig[BodyManifestConfigKey].(string)
+	return manifest
+}
+
+// bodyFilenameManifest returns the kind of manifest written under a body
+// filename, or an empty string for plain body files
+func bodyFilenameManifest(bodyFilename string) string {
+	switch bodyFilename {
+	case bodyPartitionsFilename:
+		return bodyManifestPartitions
+	case bodyChunksFilename:
+		return bodyManifestChunks
+	default:
+		return ""
+	}
+}
+
+// setBodyManifest records the kind of manifest a body is stored as in a
+// structure, identified by the filename the body was written under
+func setBodyManifest(st *dataset.Structure, bodyFilename string) {
+	manifest := bodyFilenameManifest(bodyFilename)
+	if manifest == "" {
+		delete(st.FormatConfig, BodyManifestConfigKey)
+		return
+	}
+	if st.FormatConfig == nil {
+		st.FormatConfig = map[string]interface{}{}
+	}
+	st.FormatConfig[BodyManifestConfigKey] = manifest
+}
+
+// BodyBlockPaths lists the paths of stored files a body is read from when the
+// body is stored as a manifest of partitions or chunks. These files aren't
+// linked from the dataset itself, so they must be transferred separately.
+// Returns nil for bodies stored as a single file
+func BodyBlockPaths(ctx context.Context, fs qfs.PathResolver, ds *dataset.Dataset) ([]string, error) {
+	if ds.BodyPath == "" {
+		return nil, nil
+	}
+	bf, err := OpenBody(ctx, fs, ds)
+	if err != nil {
+		return nil, err
+	}
+	defer bf.Close()
+
+	var (
+		paths []string
+		seen  = map[string]bool{}
+	)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	switch body := bf.(type) {
+	case *PartitionedBodyFile:
+		for _, p := range body.Partitions() {
+			add(p.Path)
+		}
+	case *ChunkedBodyFile:
+		for _, c := range body.Manifest().Chunks {
+			add(c.Path)
+		}
+	}
+	return paths, nil
+}
+
+// OpenPartitionManifest reads the partitions listed in a manifest from a
+// filesystem as a single body
+func OpenPartitionManifest(ctx context.Context, fs qfs.PathResolver, m *PartitionManifest) *PartitionedBodyFile {
+	return &PartitionedBodyFile{
+		name:       partitionedBodyName(m.Partitions),
+		partitions: m.Partitions,
+		stored:     true,
+		open: func(path string) (io.ReadCloser, error) {
+			return fs.Get(ctx, path)
+		},
+	}
+}
+
+func partitionedBodyName(parts []BodyPartition) string {
+	if len(parts) == 0 {
+		return "body"
+	}
+	return "body" + filepath.Ext(parts[0].Name)
+}
+
+// Partitions returns the partitions that make up this body in read order
+func (pbf *PartitionedBodyFile) Partitions() []BodyPartition {
+	return pbf.partitions
+}
+
+// Manifest returns the manifest of a body that has been stored, nil if
+// partitions have not been stored
+func (pbf *PartitionedBodyFile) Manifest() *PartitionManifest {
+	if !pbf.stored {
+		return nil
+	}
+	return &PartitionManifest{Qri: partitionManifestKind, Partitions: pbf.partitions}
+}
+
+// WithStructure returns an unread copy of the body file that reads according
+// to a structure. csv structures with a header row drop repeated headers
+func (pbf *PartitionedBodyFile) WithStructure(st *dataset.Structure) *PartitionedBodyFile {
+	return &PartitionedBodyFile{
+		name:       pbf.name,
+		partitions: pbf.partitions,
+		open:       pbf.open,
+		stored:     pbf.stored,
+		headerRow:  hasHeaderRow(st),
+	}
+}
+
+// Store writes each partition to a filesystem, returning an unread body file
+// that reads from the stored partitions. Partitions with unchanged contents
+// will be stored at the same path
+func (pbf *PartitionedBodyFile) Store(ctx context.Context, fs qfs.Filesystem) (*PartitionedBodyFile, error) {
+	stored := make([]BodyPartition, len(pbf.partitions))
+	for i, p := range pbf.partitions {
+		r, err := pbf.open(p.Path)
+		if err != nil {
+			return nil, fmt.Errorf("opening body partition %q: %w", p.Name, err)
+		}
+		path, err := fs.Put(ctx, qfs.NewMemfileReader(p.Name, r))
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("storing body partition %q: %w", p.Name, err)
+		}
+		stored[i] = BodyPartition{Name: p.Name, Path: path}
+	}
+	return &PartitionedBodyFile{
+		name:       pbf.name,
+		partitions: stored,
+		stored:     true,
+		headerRow:  pbf.headerRow,
+		open: func(path string) (io.ReadCloser, error) {
+			return fs.Get(ctx, path)
+		},
+	}, nil
+}
+
+// WriteDir writes the contents of each partition to a local directory,
+// removing partition files that are no longer part of the body
+func (pbf *PartitionedBodyFile) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	keep := map[string]bool{}
+	for _, p := range pbf.partitions {
+		keep[p.Name] = true
+		if err := pbf.writePartition(p, filepath.Join(dir, p.Name)); err != nil {
+			return err
+		}
+	}
+
+	finfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range finfos {
+		if !fi.IsDir() && !strings.HasPrefix(fi.Name(), ".") && !keep[fi.Name()] {
+			if err := os.Remove(filepath.Join(dir, fi.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (pbf *PartitionedBodyFile) writePartition(p BodyPartition, target string) error {
+	r, err := pbf.open(p.Path)
+	if err != nil {
+		return fmt.Errorf("opening body partition %q: %w", p.Name, err)
+	}
+	defer r.Close()
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RemovePartitionDir removes the partition files in a local directory, and the
+// directory itself if nothing else remains in it
+func RemovePartitionDir(dir string) error {
+	finfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	remaining := 0
+	for _, fi := range finfos {
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+			remaining++
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, fi.Name())); err != nil {
+			return err
+		}
+	}
+	// leave directories holding anything other than partitions in place
+	if remaining > 0 {
+		return nil
+	}
+	return os.Remove(dir)
+}
+
+// Read implements the io.Reader interface, reading partitions in order
+func (pbf *PartitionedBodyFile) Read(p []byte) (int, error) {
+	for {
+		if pbf.cur == nil {
+			if pbf.idx >= len(pbf.partitions) {
+				return 0, io.EOF
+			}
+			if err := pbf.openPartition(); err != nil {
+				return 0, err
+			}
+			// partitions without a trailing newline would join their last row
+			// with the next partition's first row
+			if pbf.idx > 1 && !pbf.lastEOL && len(p) > 0 {
+				p[0] = '\n'
+				pbf.lastEOL = true
+				return 1, nil
+			}
+		}
+
+		n, err := pbf.cur.Read(p)
+		if n > 0 {
+			pbf.lastEOL = p[n-1] == '\n'
+		}
+		if err == io.EOF {
+			pbf.closer.Close()
+			pbf.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// openPartition opens the next partition, skipping any repeated header
+func (pbf *PartitionedBodyFile) openPartition() error {
+	part := pbf.partitions[pbf.idx]
+	rc, err := pbf.open(part.Path)
+	if err != nil {
+		return fmt.Errorf("opening body partition %q: %w", part.Name, err)
+	}
+	pbf.idx++
+	pbf.closer = rc
+	pbf.cur = bufio.NewReader(rc)
+
+	if !pbf.headerRow {
+		return nil
+	}
+	if pbf.idx == 1 {
+		line, err := pbf.cur.Peek(headerPeekSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return err
+		}
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		pbf.header = append([]byte(nil), line...)
+		return nil
+	}
+	if len(pbf.header) == 0 {
+		return nil
+	}
+	if line, _ := pbf.cur.Peek(len(pbf.header)); bytes.Equal(normalizeEOL(line), normalizeEOL(pbf.header)) {
+		pbf.cur.Discard(len(pbf.header))
+	}
+	return nil
+}
+
+// headerPeekSize bounds the length of a header row that can be deduplicated
+const headerPeekSize = 4096
+
+func normalizeEOL(line []byte) []byte {
+	return bytes.TrimRight(line, "\r\n")
+}
+
+func hasHeaderRow(st *dataset.Structure) bool {
+	if st == nil || st.FormatConfig == nil {
+		return false
+	}
+	headerRow, _ := st.FormatConfig["headerRow"].(bool)
+	return headerRow
+}
+
+// Close closes the partition currently being read
+func (pbf *PartitionedBodyFile) Close() error {
+	if pbf.cur != nil {
+		pbf.cur = nil
+		return pbf.closer.Close()
+	}
+	return nil
+}
+
+// FileName returns the name of the body, eg: "body.csv"
+func (pbf *PartitionedBodyFile) FileName() string {
+	return pbf.name
+}
+
+// FullPath returns the name of the body, partitioned bodies have no single path
+func (pbf *PartitionedBodyFile) FullPath() string {
+	return pbf.name
+}
+
+// IsDirectory returns false, partitions are read as a single file
+func (pbf *PartitionedBodyFile) IsDirectory() bool {
+	return false
+}
+
+// NextFile returns qfs.ErrNotDirectory
+func (pbf *PartitionedBodyFile) NextFile() (qfs.File, error) {
+	return nil, qfs.ErrNotDirectory
+}
+
+// MediaType returns the media type of the partition files
+func (pbf *PartitionedBodyFile) MediaType() string {
+	return qfs.NewMemfileBytes(pbf.name, nil).MediaType()
+}
+
+// ModTime returns the zero time
+func (pbf *PartitionedBodyFile) ModTime() time.Time {
+	return time.Time{}
+}
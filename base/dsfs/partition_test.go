@@ -0,0 +1,260 @@
+package dsfs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	testPeers "github.com/qri-io/qri/config/test"
+)
+
+func TestPartitionedBodyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_partitioned_body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writePartitions(t, dir, map[string]string{
+		"2020-02.csv": "city,pop\ntoronto,40000000\n",
+		// no trailing newline
+		"2020-01.csv": "city,pop\nnew york,8500000",
+		".hidden.csv": "not,a partition\n",
+	})
+
+	pbf, err := OpenPartitionDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, p := range pbf.Partitions() {
+		names = append(names, p.Name)
+	}
+	if diff := cmp.Diff([]string{"2020-01.csv", "2020-02.csv"}, names); diff != "" {
+		t.Errorf("partition order mismatch (-want +got):\n%s", diff)
+	}
+
+	st := &dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}}
+	data, err := ioutil.ReadAll(pbf.WithStructure(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "city,pop\nnew york,8500000\ntoronto,40000000\n"
+	if diff := cmp.Diff(expect, string(data)); diff != "" {
+		t.Errorf("body with header row mismatch (-want +got):\n%s", diff)
+	}
+
+	data, err = ioutil.ReadAll(pbf.WithStructure(&dataset.Structure{Format: "csv"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = "city,pop\nnew york,8500000\ncity,pop\ntoronto,40000000\n"
+	if diff := cmp.Diff(expect, string(data)); diff != "" {
+		t.Errorf("body without header row mismatch (-want +got):\n%s", diff)
+	}
+
+	writePartitions(t, dir, map[string]string{"2020-03.json": "[]"})
+	if _, err := OpenPartitionDir(dir); err == nil {
+		t.Error("expected mixed partition extensions to error")
+	}
+}
+
+func TestCreateDatasetPartitionedBody(t *testing.T) {
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+
+	prevTs := Timestamp
+	defer func() { Timestamp = prevTs }()
+	Timestamp = func() time.Time { return time.Date(2001, 01, 01, 01, 01, 01, 01, time.UTC) }
+
+	dir, err := ioutil.TempDir("", "test_create_partitioned_body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writePartitions(t, dir, map[string]string{
+		"2020-01.csv": "city,pop\nnew york,8500000\n",
+		"2020-02.csv": "city,pop\ntoronto,40000000\n",
+	})
+
+	pk := testPeers.GetTestPeerInfo(10).PrivKey
+	newDs := func() *dataset.Dataset {
+		ds := &dataset.Dataset{
+			Commit: &dataset.Commit{Title: "partitions"},
+			Structure: &dataset.Structure{
+				Format:       "csv",
+				FormatConfig: map[string]interface{}{"headerRow": true},
+				Schema:       BaseTabularSchema,
+			},
+		}
+		pbf, err := OpenPartitionDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds.SetBodyFile(pbf)
+		return ds
+	}
+
+	path, err := CreateDataset(ctx, fs, fs, newDs(), nil, pk, SaveSwitches{})
+	if err != nil {
+		t.Fatalf("CreateDataset: %s", err)
+	}
+	first, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Structure.Entries != 2 {
+		t.Errorf("entries mismatch. want: 2, got: %d", first.Structure.Entries)
+	}
+	if got := first.Structure.FormatConfig[BodyManifestConfigKey]; got != bodyManifestPartitions {
+		t.Errorf("expected structure to record a partition manifest, got: %v", got)
+	}
+	if strings.HasSuffix(first.BodyPath, bodyPartitionsFilename) {
+		t.Errorf("expected body path to be a plain content address, got: %q", first.BodyPath)
+	}
+	firstParts := loadPartitions(ctx, t, fs, first)
+
+	writePartitions(t, dir, map[string]string{"2020-02.csv": "city,pop\ntoronto,40000001\n"})
+	next := newDs()
+	prev, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevBody, err := LoadBody(ctx, fs, prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev.SetBodyFile(prevBody)
+	path, err = CreateDataset(ctx, fs, fs, next, prev, pk, SaveSwitches{})
+	if err != nil {
+		t.Fatalf("CreateDataset: %s", err)
+	}
+	second, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondParts := loadPartitions(ctx, t, fs, second)
+
+	if firstParts[0].Path != secondParts[0].Path {
+		t.Errorf("expected unchanged partition to be stored at the same path. first: %q second: %q", firstParts[0].Path, secondParts[0].Path)
+	}
+	if firstParts[1].Path == secondParts[1].Path {
+		t.Errorf("expected changed partition to be stored at a new path")
+	}
+
+	body, err := LoadBody(ctx, fs, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "city,pop\nnew york,8500000\ntoronto,40000001\n"
+	if diff := cmp.Diff(expect, string(data)); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err = CreateDataset(ctx, fs, fs, newDs(), second, pk, SaveSwitches{}); err == nil {
+		t.Error("expected saving unchanged partitions to error")
+	}
+}
+
+func TestOpenBodyManifestLookalike(t *testing.T) {
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+	pk := testPeers.GetTestPeerInfo(10).PrivKey
+
+	// a body that happens to look like a manifest is still a plain body
+	body := `{"qri":"bp:0","partitions":[]}`
+	ds := &dataset.Dataset{
+		Commit: &dataset.Commit{Title: "lookalike"},
+		Structure: &dataset.Structure{
+			Format: "json",
+			Schema: dataset.BaseSchemaObject,
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(body)))
+
+	path, err := CreateDataset(ctx, fs, fs, ds, nil, pk, SaveSwitches{})
+	if err != nil {
+		t.Fatalf("CreateDataset: %s", err)
+	}
+	loaded, err := LoadDataset(ctx, fs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bf, err := LoadBody(ctx, fs, loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bf.(*PartitionedBodyFile); ok {
+		t.Fatal("expected body that looks like a manifest not to be read as partitions")
+	}
+	data, err := ioutil.ReadAll(bf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(body, string(data)); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRemovePartitionDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_remove_partition_dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writePartitions(t, dir, map[string]string{
+		"2020-01.csv": "city,pop\nnew york,8500000\n",
+		".hidden":     "keep me",
+	})
+	if err := RemovePartitionDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2020-01.csv")); !os.IsNotExist(err) {
+		t.Error("expected partition file to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".hidden")); err != nil {
+		t.Errorf("expected directory with other files to be kept: %s", err)
+	}
+
+	os.Remove(filepath.Join(dir, ".hidden"))
+	writePartitions(t, dir, map[string]string{"2020-01.csv": "city,pop\n"})
+	if err := RemovePartitionDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("expected empty partition directory to be removed")
+	}
+}
+
+func writePartitions(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func loadPartitions(ctx context.Context, t *testing.T, fs qfs.Filesystem, ds *dataset.Dataset) []BodyPartition {
+	body, err := LoadBody(ctx, fs, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pbf, ok := body.(*PartitionedBodyFile)
+	if !ok {
+		t.Fatalf("expected body to be partitioned, got %T", body)
+	}
+	return pbf.Partitions()
+}
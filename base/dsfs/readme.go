@@ -1,8 +1,11 @@
 package dsfs
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
@@ -51,14 +54,35 @@ func LoadReadmeScript(ctx context.Context, fs qfs.Filesystem, dspath string) (qf
 	return fs.Get(ctx, ds.Readme.ScriptPath)
 }
 
-func addReadmeFile(ds *dataset.Dataset, wfs *writeFiles) error {
-	if ds.Readme == nil {
-		return nil
-	}
+func readmeFileAddFunc(sw SaveSwitches) addWriteFileFunc {
+	return func(ds *dataset.Dataset, wfs *writeFiles) error {
+		if ds.Readme == nil {
+			return nil
+		}
+
+		ds.Readme.DropTransientValues()
+		rmsf := ds.Readme.ScriptFile()
+		if rmsf == nil {
+			return nil
+		}
+		if sw.ReadmeHook == nil {
+			wfs.readmeScript = qfs.NewMemfileReader(PackageFileReadmeScript.Filename(), rmsf)
+			return nil
+		}
+
+		hook := func(ctx context.Context, f qfs.File, added map[string]string) (io.Reader, error) {
+			script, err := ioutil.ReadAll(rmsf)
+			if err != nil {
+				return nil, err
+			}
+			if script, err = sw.ReadmeHook(ctx, ds, script); err != nil {
+				return nil, err
+			}
+			return bytes.NewReader(script), nil
+		}
 
-	ds.Readme.DropTransientValues()
-	if rmsf := ds.Readme.ScriptFile(); rmsf != nil {
-		wfs.readmeScript = qfs.NewMemfileReader(PackageFileReadmeScript.Filename(), rmsf)
+		// the hook runs once the body & components computed from it are added
+		wfs.readmeScript = qfs.NewWriteHookFile(emptyFile(PackageFileReadmeScript.Filename()), hook, filePaths(wfs.files())...)
+		return nil
 	}
-	return nil
 }
@@ -2,10 +2,15 @@ package dsfs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"sort"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsstats"
+	"github.com/qri-io/dataset/dsstats/histosketch"
 	"github.com/qri-io/qfs"
 )
 
@@ -59,3 +64,166 @@ func addStatsFile(ds *dataset.Dataset, wfs *writeFiles) error {
 	wfs.stats = qfs.NewWriteHookFile(qfs.NewMemfileBytes(PackageFileStats.Filename(), []byte{}), hook, wfs.structure.FullPath())
 	return nil
 }
+
+// mergeAppendedStats folds stats accumulated from appended rows into the
+// stats of the previous version, producing stats for the combined body.
+// Counts & extremes merge exactly, histograms, medians & unique counts are
+// approximations
+func mergeAppendedStats(prev interface{}, appended []map[string]interface{}) ([]map[string]interface{}, error) {
+	if prev == nil {
+		return nil, fmt.Errorf("previous version has no stats to append to")
+	}
+	var prevCols, nextCols []map[string]interface{}
+	if err := normalizeStats(prev, &prevCols); err != nil {
+		return nil, fmt.Errorf("reading previous stats: %w", err)
+	}
+	if err := normalizeStats(appended, &nextCols); err != nil {
+		return nil, err
+	}
+
+	if len(nextCols) == 0 {
+		return prevCols, nil
+	}
+	if len(prevCols) != len(nextCols) {
+		return nil, fmt.Errorf("can't merge stats for %d columns into stats for %d columns", len(nextCols), len(prevCols))
+	}
+
+	merged := make([]map[string]interface{}, len(prevCols))
+	for i, p := range prevCols {
+		n := nextCols[i]
+		if p["key"] != n["key"] {
+			return nil, fmt.Errorf("can't merge stats for key %v into stats for key %v", n["key"], p["key"])
+		}
+		m, err := mergeColumnStats(p, n)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", i, err)
+		}
+		merged[i] = m
+	}
+	return merged, nil
+}
+
+// normalizeStats round-trips stats through JSON so stats loaded from the
+// store & fresh accumulator output share the same go types
+func normalizeStats(stats interface{}, dst *[]map[string]interface{}) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func mergeColumnStats(p, n map[string]interface{}) (map[string]interface{}, error) {
+	pCount, nCount := statNum(p, "count"), statNum(n, "count")
+	// stats with no counted values carry nothing but a type
+	if nCount == 0 {
+		return p, nil
+	}
+	if pCount == 0 {
+		n["type"] = p["type"]
+		if key, ok := p["key"]; ok {
+			n["key"] = key
+		}
+		return n, nil
+	}
+	if p["type"] != n["type"] {
+		return nil, fmt.Errorf("stats type %v doesn't match previous type %v", n["type"], p["type"])
+	}
+
+	m := map[string]interface{}{"type": p["type"], "count": pCount + nCount}
+	if key, ok := p["key"]; ok {
+		m["key"] = key
+	}
+
+	switch p["type"] {
+	case "numeric":
+		m["min"] = math.Min(statNum(p, "min"), statNum(n, "min"))
+		m["max"] = math.Max(statNum(p, "max"), statNum(n, "max"))
+		m["mean"] = (statNum(p, "mean")*pCount + statNum(n, "mean")*nCount) / (pCount + nCount)
+
+		sketch := histosketch.New(dsstats.HistogramCentroidCount)
+		addHistogram(sketch, p)
+		addHistogram(sketch, n)
+		bins, freqs := sketch.Read()
+		m["median"] = sketch.Median()
+		m["histogram"] = map[string]interface{}{"bins": bins, "frequencies": freqs}
+	case "string":
+		m["minLength"] = math.Min(statNum(p, "minLength"), statNum(n, "minLength"))
+		m["maxLength"] = math.Max(statNum(p, "maxLength"), statNum(n, "maxLength"))
+
+		pFreqs, _ := p["frequencies"].(map[string]interface{})
+		nFreqs, _ := n["frequencies"].(map[string]interface{})
+		freqs := map[string]float64{}
+		overlap := 0.0
+		for k, v := range pFreqs {
+			freqs[k] += toFloat(v)
+		}
+		for k, v := range nFreqs {
+			if _, ok := freqs[k]; ok {
+				overlap++
+			}
+			freqs[k] += toFloat(v)
+		}
+		m["frequencies"] = topFrequencies(freqs, dsstats.StopFreqCountThreshold)
+		if unique := statNum(p, "unique") + statNum(n, "unique") - overlap; unique > 0 {
+			m["unique"] = unique
+		}
+	case "boolean":
+		m["trueCount"] = statNum(p, "trueCount") + statNum(n, "trueCount")
+		m["falseCount"] = statNum(p, "falseCount") + statNum(n, "falseCount")
+	case "null":
+	default:
+		return nil, fmt.Errorf("can't merge stats of type %v", p["type"])
+	}
+	return m, nil
+}
+
+// addHistogram adds the bins of a numeric stat's histogram to a sketch,
+// weighting the midpoint of each bin by its frequency
+func addHistogram(sketch *histosketch.Sketch, stat map[string]interface{}) {
+	h, ok := stat["histogram"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	bins, _ := h["bins"].([]interface{})
+	freqs, _ := h["frequencies"].([]interface{})
+	for i, f := range freqs {
+		if i+1 >= len(bins) {
+			break
+		}
+		if count := int64(math.Round(toFloat(f))); count > 0 {
+			sketch.AddMany((toFloat(bins[i])+toFloat(bins[i+1]))/2, count)
+		}
+	}
+}
+
+// topFrequencies keeps the n most frequent values
+func topFrequencies(freqs map[string]float64, n int) map[string]float64 {
+	if len(freqs) <= n {
+		return freqs
+	}
+	keys := make([]string, 0, len(freqs))
+	for k := range freqs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if freqs[keys[i]] == freqs[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return freqs[keys[i]] > freqs[keys[j]]
+	})
+	top := make(map[string]float64, n)
+	for _, k := range keys[:n] {
+		top[k] = freqs[k]
+	}
+	return top
+}
+
+func statNum(stat map[string]interface{}, key string) float64 {
+	return toFloat(stat[key])
+}
+
+func toFloat(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
@@ -0,0 +1,61 @@
+package dsfs
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/dsstats"
+)
+
+func TestMergeAppendedStats(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: dataset.BaseSchemaArray,
+	}
+	accumulate := func(rows ...[]interface{}) []map[string]interface{} {
+		acc := dsstats.NewAccumulator(st)
+		for i, row := range rows {
+			if err := acc.WriteEntry(dsio.Entry{Index: i, Value: row}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		acc.Close()
+		return dsstats.ToMap(acc)
+	}
+
+	prev := accumulate([]interface{}{"a", 1.0, true}, []interface{}{"bb", 3.0, false})
+	next := accumulate([]interface{}{"a", 5.0, true})
+
+	merged, err := mergeAppendedStats(prev, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str := merged[0]
+	if str["count"] != 3.0 || str["minLength"] != 1.0 || str["maxLength"] != 2.0 {
+		t.Errorf("string stats mismatch: %v", str)
+	}
+	if freqs := str["frequencies"].(map[string]float64); freqs["a"] != 2 || freqs["bb"] != 1 {
+		t.Errorf("string frequencies mismatch: %v", freqs)
+	}
+
+	num := merged[1]
+	if num["count"] != 3.0 || num["min"] != 1.0 || num["max"] != 5.0 || num["mean"] != 3.0 {
+		t.Errorf("numeric stats mismatch: %v", num)
+	}
+
+	b := merged[2]
+	if b["count"] != 3.0 || b["trueCount"] != 2.0 || b["falseCount"] != 1.0 {
+		t.Errorf("boolean stats mismatch: %v", b)
+	}
+
+	if _, err := mergeAppendedStats(nil, next); err == nil {
+		t.Error("expected merging without previous stats to error")
+	}
+
+	mismatched := accumulate([]interface{}{1.0, "a", true})
+	if _, err := mergeAppendedStats(prev, mismatched); err == nil {
+		t.Error("expected merging mismatched types to error")
+	}
+}
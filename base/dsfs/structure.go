@@ -68,6 +68,7 @@ func structureFileAddFunc(destFS qfs.Filesystem) addWriteFileFunc {
 					ds.Structure.Checksum = path
 				}
 			}
+			setBodyManifest(ds.Structure, wfs.body.FullPath())
 
 			return JSONFile(f.FullPath(), ds.Structure)
 		}
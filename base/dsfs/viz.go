@@ -70,7 +70,10 @@ func renderVizWriteHook(fs qfs.Filesystem, ds *dataset.Dataset, wfs *writeFiles)
 
 		renderDs := &dataset.Dataset{}
 		renderDs.Assign(ds)
-		bf, err := fs.Get(ctx, added[wfs.body.FullPath()])
+		bf, err := openBody(ctx, fs, &dataset.Dataset{
+			BodyPath:  added[wfs.body.FullPath()],
+			Structure: ds.Structure,
+		}, bodyFilenameManifest(wfs.body.FullPath()))
 		if err != nil {
 			return nil, err
 		}
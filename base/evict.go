@@ -0,0 +1,128 @@
+package base
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipfs/go-ipfs/core/corerepo"
+	"github.com/qri-io/qfs/qipfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/repo"
+)
+
+// EvictVersions removes dataset versions older than the keep most recent
+// versions from local storage, returning the paths of evicted versions.
+// Only versions the logbook records as pushed to a remote are evicted.
+// Evicted versions remain in dataset history, and can be fetched from the
+// remote when they're next loaded. Deleting from IPFS only unpins, so eviction
+// runs garbage collection on the IPFS store to free space
+func EvictVersions(ctx context.Context, r repo.Repo, ref dsref.Ref, keep int) ([]string, error) {
+	return evictVersions(ctx, r, ref, keep, "")
+}
+
+// EvictVersion removes the dataset version at ref.Path from local storage if
+// it isn't one of the keep most recent versions and has been pushed to a
+// remote, reporting if the version was evicted
+func EvictVersion(ctx context.Context, r repo.Repo, ref dsref.Ref, keep int) (bool, error) {
+	if ref.Path == "" {
+		return false, fmt.Errorf("need a dataset reference with a path")
+	}
+	evicted, err := evictVersions(ctx, r, ref, keep, ref.Path)
+	return len(evicted) > 0, err
+}
+
+func evictVersions(ctx context.Context, r repo.Repo, ref dsref.Ref, keep int, onlyPath string) ([]string, error) {
+	if keep < 1 {
+		return nil, fmt.Errorf("at least one version must be kept")
+	}
+
+	items, err := r.Logbook().Items(ctx, ref, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	var evict, retain []string
+	for i, item := range items {
+		if i >= keep && item.Published && (onlyPath == "" || item.Path == onlyPath) {
+			evict = append(evict, item.Path)
+		} else {
+			retain = append(retain, item.Path)
+		}
+	}
+	if len(evict) == 0 {
+		return nil, nil
+	}
+	evicted, err := dropVersions(ctx, r, evict, retain)
+	if len(evicted) > 0 {
+		if gcErr := collectGarbage(ctx, r); gcErr != nil && err == nil {
+			err = gcErr
+		}
+	}
+	return evicted, err
+}
+
+// collectGarbage removes unpinned blocks from the repo's IPFS store, if it
+// has one
+func collectGarbage(ctx context.Context, r repo.Repo) error {
+	ipfsfs, ok := r.Filesystem().Filesystem(qipfs.FilestoreType).(*qipfs.Filestore)
+	if !ok || ipfsfs.Node() == nil {
+		return nil
+	}
+	return corerepo.GarbageCollect(ipfsfs.Node(), ctx)
+}
+
+// dropVersions removes the versions at the drop paths from local storage,
+// along with body blocks no retained version reads from, returning the paths
+// of removed versions. Versions that aren't stored locally are skipped
+func dropVersions(ctx context.Context, r repo.Repo, drop, retain []string) ([]string, error) {
+	fs := r.Filesystem()
+	localBodyBlocks := func(path string) []string {
+		if local, err := fs.Has(ctx, path); err != nil || !local {
+			return nil
+		}
+		ds, err := dsfs.LoadDataset(ctx, fs, path)
+		if err != nil {
+			log.Debugf("loading version %q: %s", path, err)
+			return nil
+		}
+		blocks, err := dsfs.BodyBlockPaths(ctx, fs, ds)
+		if err != nil {
+			log.Debugf("listing body blocks of version %q: %s", path, err)
+			return nil
+		}
+		return blocks
+	}
+
+	// partitions & chunks can be shared between versions. never remove blocks
+	// a retained version reads from, and only remove each block once
+	skip := map[string]bool{}
+	for _, path := range retain {
+		for _, b := range localBodyBlocks(path) {
+			skip[b] = true
+		}
+	}
+
+	var dropped []string
+	for _, path := range drop {
+		if local, err := fs.Has(ctx, path); err != nil || !local {
+			continue
+		}
+		blocks := localBodyBlocks(path)
+		if err := fs.Delete(ctx, path); err != nil {
+			return dropped, err
+		}
+		for _, b := range blocks {
+			if skip[b] {
+				continue
+			}
+			skip[b] = true
+			if err := fs.Delete(ctx, b); err != nil {
+				return dropped, err
+			}
+		}
+		log.Debugf("dropped version %q", path)
+		dropped = append(dropped, path)
+	}
+	return dropped, nil
+}
@@ -0,0 +1,125 @@
+package base
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+func saveEvictTestVersion(t *testing.T, run *TestRunner, i int) dsref.Ref {
+	t.Helper()
+	ds := run.BuildDataset("evict_test", "json")
+	ds.Meta = &dataset.Meta{Title: fmt.Sprintf("version %d", i)}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(fmt.Sprintf("[%d]", i))))
+	ref, err := run.SaveDataset(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func TestEvictVersions(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+
+	var refs []dsref.Ref
+	for i := 1; i <= 4; i++ {
+		refs = append(refs, saveEvictTestVersion(t, run, i))
+	}
+	head := refs[len(refs)-1]
+
+	if _, err := EvictVersions(ctx, r, head, 0); err == nil {
+		t.Error("expected keeping zero versions to error")
+	}
+
+	// unpublished versions are never evicted
+	evicted, err := EvictVersions(ctx, r, head, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 0 {
+		t.Errorf("expected no unpublished versions to be evicted, got: %v", evicted)
+	}
+
+	initID, err := r.Logbook().RefToInitID(dsref.Ref{Username: head.Username, Name: head.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// publish the three most recent versions, leaving the first unpublished
+	if _, _, err := r.Logbook().WriteRemotePush(ctx, initID, 3, "example/remote/address"); err != nil {
+		t.Fatal(err)
+	}
+	refs = append(refs, saveEvictTestVersion(t, run, 5), saveEvictTestVersion(t, run, 6))
+	head = refs[len(refs)-1]
+
+	evicted, err = EvictVersions(ctx, r, head, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 3 {
+		t.Errorf("expected 3 published versions to be evicted, got: %v", evicted)
+	}
+	for i, ref := range refs {
+		local, err := r.Filesystem().Has(ctx, ref.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shouldEvict := i >= 1 && i <= 3
+		if local == shouldEvict {
+			t.Errorf("version %d local: %t, expected evicted: %t", i+1, local, shouldEvict)
+		}
+	}
+
+	items, err := r.Logbook().Items(ctx, head, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != len(refs) {
+		t.Errorf("expected eviction to leave history intact. want %d versions, got %d", len(refs), len(items))
+	}
+}
+
+func TestEvictVersion(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+
+	first := saveEvictTestVersion(t, run, 1)
+	initID, err := r.Logbook().RefToInitID(dsref.Ref{Username: first.Username, Name: first.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Logbook().WriteRemotePush(ctx, initID, 1, "example/remote/address"); err != nil {
+		t.Fatal(err)
+	}
+	head := saveEvictTestVersion(t, run, 2)
+
+	if _, err := EvictVersion(ctx, r, dsref.Ref{Username: head.Username, Name: head.Name}, 1); err == nil {
+		t.Error("expected evicting a reference without a path to error")
+	}
+
+	evicted, err := EvictVersion(ctx, r, first, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evicted {
+		t.Error("expected version within kept versions not to be evicted")
+	}
+
+	evicted, err = EvictVersion(ctx, r, first, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !evicted {
+		t.Error("expected published version to be evicted")
+	}
+	if local, _ := r.Filesystem().Has(ctx, first.Path); local {
+		t.Error("expected evicted version to be removed from the filesystem")
+	}
+}
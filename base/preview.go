@@ -8,6 +8,8 @@ import (
 	"io/ioutil"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/dsref"
@@ -20,6 +22,9 @@ const (
 	// MaxReadmePreviewBytes determines the maximum amount of bytes a readme
 	// preview can be. three bytes less than 1000 to make room for an elipsis
 	MaxReadmePreviewBytes = 997
+	// MaxBodyPageRows is the highest number of entries a page of body data can
+	// contain
+	MaxBodyPageRows = 1000
 )
 
 // CreatePreview generates a preview for a dataset version
@@ -59,7 +64,7 @@ func CreatePreview(ctx context.Context, fs qfs.Filesystem, ref dsref.Ref) (ds *d
 		}
 	}
 
-	if err = ds.OpenBodyFile(ctx, fs); err != nil {
+	if err = openBodyFile(ctx, fs, ds); err != nil {
 		log.Errorf("CreatePreview opening body file: %s", err.Error())
 		return nil, err
 	}
@@ -81,3 +86,95 @@ func CreatePreview(ctx context.Context, fs qfs.Filesystem, ref dsref.Ref) (ds *d
 	ds.Body = json.RawMessage(data)
 	return ds, nil
 }
+
+// ReadBodyPage reads a page of entries from the body of a dataset version as
+// JSON. limits outside the range 1-MaxBodyPageRows read MaxBodyPageRows
+// entries. Naming columns keeps only those columns of each row, in the order
+// they're named, which requires a tabular body
+func ReadBodyPage(ctx context.Context, fs qfs.Filesystem, ref dsref.Ref, offset, limit int, columns []string) (json.RawMessage, error) {
+	if ref.Path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", offset)
+	}
+	if limit <= 0 || limit > MaxBodyPageRows {
+		limit = MaxBodyPageRows
+	}
+
+	ds, err := dsfs.LoadDataset(ctx, fs, ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("dataset has no structure")
+	}
+
+	var cols []int
+	if len(columns) > 0 {
+		if cols, err = columnIndices(ds.Structure, columns); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = openBodyFile(ctx, fs, ds); err != nil {
+		return nil, err
+	}
+	defer ds.BodyFile().Close()
+
+	rr, err := dsio.NewEntryReader(ds.Structure, ds.BodyFile())
+	if err != nil {
+		return nil, fmt.Errorf("error allocating data reader: %s", err)
+	}
+	page, err := ReadEntries(&dsio.PagedReader{
+		Reader: rr,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cols != nil {
+		rows, _ := page.([]interface{})
+		for i, row := range rows {
+			vals, ok := row.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("selecting columns: row %d is not an array", offset+i)
+			}
+			projected := make([]interface{}, len(cols))
+			for j, col := range cols {
+				if col < len(vals) {
+					projected[j] = vals[col]
+				}
+			}
+			rows[i] = projected
+		}
+	}
+
+	return json.Marshal(page)
+}
+
+// columnIndices finds the position of named columns in a tabular structure
+func columnIndices(st *dataset.Structure, columns []string) ([]int, error) {
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("selecting columns: %w", err)
+	}
+	titles := cols.Titles()
+
+	indices := make([]int, len(columns))
+	for i, name := range columns {
+		indices[i] = -1
+		for j, title := range titles {
+			if title == name {
+				indices[i] = j
+				break
+			}
+		}
+		if indices[i] == -1 {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return indices, nil
+}
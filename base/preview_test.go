@@ -55,3 +55,39 @@ func TestCreatePreview(t *testing.T) {
 		dstest.UpdateGoldenFileIfEnvVarSet("testdata/expect/TestCreatePreview.transform.json", got)
 	}
 }
+
+func TestReadBodyPage(t *testing.T) {
+	r := newTestRepo(t)
+	ref := addCitiesDataset(t, r)
+	ctx := context.Background()
+
+	cases := []struct {
+		description   string
+		offset, limit int
+		columns       []string
+		expect        string
+	}{
+		{"first page", 0, 2, nil, `[["toronto",40000000,55.5,false],["new york",8500000,44.4,true]]`},
+		{"offset", 3, 5, nil, `[["chatham",35000,65.25,true],["raleigh",250000,50.65,true]]`},
+		{"past the end", 10, 2, nil, `[]`},
+		{"columns", 1, 2, []string{"in_usa", "city"}, `[[true,"new york"],[true,"chicago"]]`},
+	}
+
+	for _, c := range cases {
+		got, err := ReadBodyPage(ctx, r.Filesystem(), ref, c.offset, c.limit, c.columns)
+		if err != nil {
+			t.Errorf("case %q: unexpected error: %s", c.description, err)
+			continue
+		}
+		if string(got) != c.expect {
+			t.Errorf("case %q: result mismatch. want: %s, got: %s", c.description, c.expect, string(got))
+		}
+	}
+
+	if _, err := ReadBodyPage(ctx, r.Filesystem(), ref, 0, 2, []string{"population"}); err == nil {
+		t.Error("expected unknown column to error")
+	}
+	if _, err := ReadBodyPage(ctx, r.Filesystem(), ref, -1, 2, nil); err == nil {
+		t.Error("expected negative offset to error")
+	}
+}
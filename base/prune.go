@@ -0,0 +1,181 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
+	"github.com/qri-io/qri/repo"
+)
+
+// PrunableVersions selects the versions of a dataset history a retention
+// policy doesn't keep. history is ordered newest first. The latest version is
+// always kept, and a policy without rules keeps all versions
+func PrunableVersions(history []logbook.DatasetLogItem, p *config.RetentionPolicy, now time.Time) ([]logbook.DatasetLogItem, error) {
+	if p.KeepsAll() || len(history) == 0 {
+		return nil, nil
+	}
+	within, err := p.Within()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make([]bool, len(history))
+	keep[0] = true
+	var older []int
+	for i, item := range history {
+		switch {
+		case i < p.KeepLast:
+			keep[i] = true
+		case within > 0 && now.Sub(item.CommitTime) <= within:
+			keep[i] = true
+		default:
+			older = append(older, i)
+		}
+		if p.KeepTagged && len(item.Tags) > 0 {
+			keep[i] = true
+		}
+	}
+
+	// beyond versions kept by count & age, keep the latest version in each of
+	// the most recent days, weeks & months
+	buckets := []struct {
+		n   int
+		key func(t time.Time) string
+	}{
+		{p.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{p.KeepWeekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-%d", year, week)
+		}},
+		{p.KeepMonthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	for _, b := range buckets {
+		seen := map[string]bool{}
+		for _, i := range older {
+			if len(seen) == b.n {
+				break
+			}
+			key := b.key(history[i].CommitTime.UTC())
+			if !seen[key] {
+				seen[key] = true
+				keep[i] = true
+			}
+		}
+	}
+
+	var prune []logbook.DatasetLogItem
+	for i, item := range history {
+		if !keep[i] {
+			prune = append(prune, item)
+		}
+	}
+	return prune, nil
+}
+
+// PruneVersions removes the versions of a dataset a retention policy doesn't
+// keep from history & local storage, returning the pruned versions. The
+// logbook records pruned versions with a prune operation. ref must have an
+// InitID. When dryRun is true versions that would be pruned are returned, but
+// nothing is removed
+func PruneVersions(ctx context.Context, r repo.Repo, ref dsref.Ref, p *config.RetentionPolicy, dryRun bool) ([]logbook.DatasetLogItem, error) {
+	if ref.InitID == "" {
+		return nil, fmt.Errorf("pruning versions requires a reference with an InitID")
+	}
+	history, err := r.Logbook().Items(ctx, ref, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	prune, err := PrunableVersions(history, p, time.Now())
+	if err != nil || len(prune) == 0 || dryRun {
+		return prune, err
+	}
+
+	drop, retain := splitPruned(history, prune)
+	if err := r.Logbook().WriteVersionPrune(ctx, ref.InitID, drop, ""); err != nil {
+		return nil, err
+	}
+	// pruned versions are no longer referenced by history, failing to remove
+	// them leaves blocks for garbage collection
+	if _, err := dropVersions(ctx, r, drop, retain); err != nil {
+		log.Debugf("removing pruned versions of %q: %s", ref.Human(), err)
+	}
+	return prune, nil
+}
+
+// PruneStoredVersions removes versions of a dataset from local storage
+// without changing dataset history, returning the paths of removed versions.
+// Only versions the author has already removed from history are dropped, and
+// of those only the ones a retention policy doesn't keep. Remotes use this to
+// enforce retention on the versions they store for other users, who own the
+// history. Versions in history stay stored, because the author's push
+// operations list them as held by the remote
+func PruneStoredVersions(ctx context.Context, r repo.Repo, ref dsref.Ref, p *config.RetentionPolicy) ([]string, error) {
+	if p.KeepsAll() {
+		return nil, nil
+	}
+	book := r.Logbook()
+	if ref.InitID == "" {
+		initID, err := book.RefToInitID(dsref.Ref{Username: ref.Username, Name: ref.Name})
+		if err != nil {
+			return nil, err
+		}
+		ref.InitID = initID
+	}
+	history, err := book.Items(ctx, ref, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	dropped, err := book.DroppedVersions(ctx, ref.InitID)
+	if err != nil || len(dropped) == 0 {
+		return nil, err
+	}
+
+	// apply the policy to every version the author has written, so dropped
+	// versions count toward the versions a policy keeps
+	all := append(append([]logbook.DatasetLogItem{}, history...), dropped...)
+	sort.SliceStable(all[1:], func(i, j int) bool {
+		return all[i+1].CommitTime.After(all[j+1].CommitTime)
+	})
+	prune, err := PrunableVersions(all, p, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	isDropped := map[string]bool{}
+	for _, item := range dropped {
+		isDropped[item.Path] = true
+	}
+	var drop, retain []string
+	for _, item := range prune {
+		if isDropped[item.Path] {
+			drop = append(drop, item.Path)
+		}
+	}
+	for _, item := range history {
+		retain = append(retain, item.Path)
+	}
+	if len(drop) == 0 {
+		return nil, nil
+	}
+	return dropVersions(ctx, r, drop, retain)
+}
+
+// splitPruned divides the paths of a history into pruned & retained versions
+func splitPruned(history, pruned []logbook.DatasetLogItem) (drop, retain []string) {
+	isPruned := map[string]bool{}
+	for _, item := range pruned {
+		isPruned[item.Path] = true
+	}
+	for _, item := range history {
+		if isPruned[item.Path] {
+			drop = append(drop, item.Path)
+		} else {
+			retain = append(retain, item.Path)
+		}
+	}
+	return drop, retain
+}
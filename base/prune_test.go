@@ -0,0 +1,143 @@
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
+)
+
+func TestPrunableVersions(t *testing.T) {
+	now := time.Date(2020, time.June, 15, 12, 0, 0, 0, time.UTC)
+	item := func(path string, t time.Time, tags ...string) logbook.DatasetLogItem {
+		return logbook.DatasetLogItem{
+			VersionInfo: dsref.VersionInfo{Path: path, CommitTime: t},
+			Tags:        tags,
+		}
+	}
+	history := []logbook.DatasetLogItem{
+		item("/ipfs/QmJun15b", now.Add(-time.Hour)),
+		item("/ipfs/QmJun15a", now.Add(-2*time.Hour)),
+		item("/ipfs/QmJun14b", now.Add(-24*time.Hour)),
+		item("/ipfs/QmJun14a", now.Add(-26*time.Hour)),
+		item("/ipfs/QmJun1", time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)),
+		item("/ipfs/QmMay20", time.Date(2020, time.May, 20, 0, 0, 0, 0, time.UTC), "v1"),
+		item("/ipfs/QmMay2", time.Date(2020, time.May, 2, 0, 0, 0, 0, time.UTC)),
+		item("/ipfs/QmApr1", time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	cases := []struct {
+		description string
+		policy      *config.RetentionPolicy
+		expect      []string
+	}{
+		{"nil policy keeps all", nil, nil},
+		{"empty policy keeps all", &config.RetentionPolicy{}, nil},
+		{"keep last", &config.RetentionPolicy{KeepLast: 6},
+			[]string{"/ipfs/QmMay2", "/ipfs/QmApr1"}},
+		{"latest is always kept", &config.RetentionPolicy{KeepTagged: true},
+			[]string{"/ipfs/QmJun15a", "/ipfs/QmJun14b", "/ipfs/QmJun14a", "/ipfs/QmJun1", "/ipfs/QmMay2", "/ipfs/QmApr1"}},
+		{"keep within", &config.RetentionPolicy{KeepWithin: "1d"},
+			[]string{"/ipfs/QmJun14a", "/ipfs/QmJun1", "/ipfs/QmMay20", "/ipfs/QmMay2", "/ipfs/QmApr1"}},
+		{"keep daily beyond last", &config.RetentionPolicy{KeepLast: 2, KeepDaily: 2},
+			[]string{"/ipfs/QmJun14a", "/ipfs/QmMay20", "/ipfs/QmMay2", "/ipfs/QmApr1"}},
+		{"keep monthly", &config.RetentionPolicy{KeepMonthly: 3},
+			[]string{"/ipfs/QmJun15a", "/ipfs/QmJun14b", "/ipfs/QmJun14a", "/ipfs/QmJun1", "/ipfs/QmMay2"}},
+		// June 14th 2020 is a sunday, the last day of the week before
+		{"keep weekly & tagged", &config.RetentionPolicy{KeepWeekly: 2, KeepTagged: true},
+			[]string{"/ipfs/QmJun15a", "/ipfs/QmJun14a", "/ipfs/QmJun1", "/ipfs/QmMay2", "/ipfs/QmApr1"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			pruned, err := PrunableVersions(history, c.policy, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, item := range pruned {
+				got = append(got, item.Path)
+			}
+			if diff := cmp.Diff(c.expect, got); diff != "" {
+				t.Errorf("pruned versions mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := PrunableVersions(history, &config.RetentionPolicy{KeepWithin: "forever"}, now); err == nil {
+		t.Error("expected invalid duration to error")
+	}
+}
+
+func TestPruneVersions(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+
+	var refs []dsref.Ref
+	for i := 1; i <= 4; i++ {
+		refs = append(refs, saveEvictTestVersion(t, run, i))
+	}
+	head := refs[len(refs)-1]
+	initID, err := r.Logbook().RefToInitID(dsref.Ref{Username: head.Username, Name: head.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.InitID = initID
+	if err := r.Logbook().WriteTag(ctx, initID, "v1", refs[0].Path, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := PruneVersions(ctx, r, dsref.Ref{Username: head.Username, Name: head.Name}, nil, false); err == nil {
+		t.Error("expected pruning without an InitID to error")
+	}
+
+	policy := &config.RetentionPolicy{KeepLast: 1, KeepTagged: true}
+	pruned, err := PruneVersions(ctx, r, head, policy, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != 2 {
+		t.Errorf("expected dry run to list 2 versions, got: %v", pruned)
+	}
+	if items, _ := r.Logbook().Items(ctx, head, 0, -1); len(items) != 4 {
+		t.Errorf("expected dry run to leave history intact, got %d versions", len(items))
+	}
+
+	if _, err = PruneVersions(ctx, r, head, policy, false); err != nil {
+		t.Fatal(err)
+	}
+	items, err := r.Logbook().Items(ctx, head, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, item := range items {
+		paths = append(paths, item.Path)
+	}
+	if diff := cmp.Diff([]string{refs[3].Path, refs[0].Path}, paths); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+	for i, ref := range refs {
+		local, err := r.Filesystem().Has(ctx, ref.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shouldPrune := i == 1 || i == 2
+		if local == shouldPrune {
+			t.Errorf("version %d local: %t, expected pruned: %t", i+1, local, shouldPrune)
+		}
+	}
+
+	resolved := dsref.Ref{Username: head.Username, Name: head.Name}
+	if _, err := r.Logbook().ResolveRef(ctx, &resolved); err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Path != head.Path {
+		t.Errorf("expected head to remain %q, got %q", head.Path, resolved.Path)
+	}
+}
@@ -0,0 +1,123 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+// RevertComponents lists the components a revert can restore, in the order
+// they're written to commit messages
+var RevertComponents = []string{"meta", "structure", "body", "readme", "transform", "viz"}
+
+// PrepareRevert loads the components of a dataset version a revert restores.
+// Listing no components restores all of them. Components are loaded with
+// LoadRevs, components the version doesn't have are returned as a drop string
+// to remove them when saving. Restoring a body always restores its structure
+func PrepareRevert(ctx context.Context, fs qfs.Filesystem, ref dsref.Ref, components []string) (ds *dataset.Dataset, drop string, err error) {
+	if ref.Path == "" {
+		return nil, "", fmt.Errorf("can only revert to a resolved reference with a path value")
+	}
+	if components, err = revertComponents(components); err != nil {
+		return nil, "", err
+	}
+
+	target, err := dsfs.LoadDataset(ctx, fs, ref.Path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		revs  []*dsref.Rev
+		drops []string
+	)
+	for _, comp := range components {
+		rev, err := dsref.ParseRev(comp)
+		if err != nil {
+			return nil, "", err
+		}
+		if hasComponent(target, comp) {
+			revs = append(revs, rev)
+		} else {
+			drops = append(drops, rev.Field)
+		}
+	}
+
+	if ds, err = LoadRevs(ctx, fs, ref, revs); err != nil {
+		return nil, "", err
+	}
+	return ds, strings.Join(drops, ","), nil
+}
+
+// revertComponents validates & orders a list of components to revert
+func revertComponents(components []string) ([]string, error) {
+	if len(components) == 0 {
+		return RevertComponents, nil
+	}
+	selected := map[string]bool{}
+	for _, comp := range components {
+		valid := false
+		for _, c := range RevertComponents {
+			if comp == c {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("can't revert component %q. must be one of %s", comp, strings.Join(RevertComponents, ", "))
+		}
+		selected[comp] = true
+	}
+	if selected["body"] {
+		selected["structure"] = true
+	}
+
+	ordered := []string{}
+	for _, c := range RevertComponents {
+		if selected[c] {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered, nil
+}
+
+func hasComponent(ds *dataset.Dataset, comp string) bool {
+	switch comp {
+	case "meta":
+		return ds.Meta != nil
+	case "structure":
+		return ds.Structure != nil
+	case "body":
+		return ds.BodyPath != ""
+	case "readme":
+		return ds.Readme != nil
+	case "transform":
+		return ds.Transform != nil
+	case "viz":
+		return ds.Viz != nil
+	}
+	return false
+}
+
+// RevertCommit creates the commit for a version that reverts components to
+// an earlier version. The reverted version isn't part of the commit, saves
+// record it in dataset history with the Reverts save switch
+func RevertCommit(path string, components []string, title, message string) *dataset.Commit {
+	components, _ = revertComponents(components)
+	if title == "" {
+		if len(components) == len(RevertComponents) {
+			title = fmt.Sprintf("revert to %s", path)
+		} else {
+			title = fmt.Sprintf("revert %s to %s", strings.Join(components, ", "), path)
+		}
+	}
+	return &dataset.Commit{
+		Title:   title,
+		Message: message,
+	}
+}
@@ -0,0 +1,36 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRevertComponents(t *testing.T) {
+	got, err := revertComponents([]string{"readme", "body"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"structure", "body", "readme"}, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := revertComponents([]string{"stats"}); err == nil {
+		t.Error("expected reverting stats to error")
+	}
+}
+
+func TestRevertCommit(t *testing.T) {
+	cm := RevertCommit("/ipfs/QmVersion", nil, "", "")
+	if cm.Title != "revert to /ipfs/QmVersion" {
+		t.Errorf("title mismatch. got: %q", cm.Title)
+	}
+
+	cm = RevertCommit("/ipfs/QmVersion", []string{"meta"}, "", "bad meta edit")
+	if cm.Title != "revert meta to /ipfs/QmVersion" {
+		t.Errorf("title mismatch. got: %q", cm.Title)
+	}
+	if cm.Message != "bad meta edit" {
+		t.Errorf("message mismatch. got: %q", cm.Message)
+	}
+}
@@ -61,6 +61,18 @@ func SaveDataset(ctx context.Context, r repo.Repo, writeDest qfs.Filesystem, ini
 		mutable.Commit = nil
 	}
 
+	if sw.Append {
+		if prevPath == "" {
+			return nil, fmt.Errorf("can't append to a dataset with no previous version")
+		}
+		if changes.BodyFile() == nil {
+			return nil, fmt.Errorf("appending requires a body file of rows to append")
+		}
+		if _, ok := prev.BodyFile().(*dsfs.PartitionedBodyFile); ok {
+			return nil, fmt.Errorf("can't append to a partitioned body, add a partition file instead")
+		}
+	}
+
 	// Save requires either a body or a structure.
 	// TODO(dustmop): Saving with only a structure is currently broken. See TestSaveBasicCommands
 	// in cmd/save_test.go
@@ -109,7 +121,11 @@ func SaveDataset(ctx context.Context, r repo.Repo, writeDest qfs.Filesystem, ini
 	}
 
 	// Write the save to logbook
-	err = r.Logbook().WriteVersionSave(ctx, initID, ds)
+	if sw.Reverts != "" {
+		err = r.Logbook().WriteVersionRevert(ctx, initID, ds, sw.Reverts)
+	} else {
+		err = r.Logbook().WriteVersionSave(ctx, initID, ds)
+	}
 	if err != nil && err != logbook.ErrNoLogbook {
 		return ds, err
 	}
@@ -184,7 +200,7 @@ func CreateDataset(ctx context.Context, r repo.Repo, writeDest qfs.Filesystem, d
 	// references to files in a store that won't exist after this function call
 	// TODO (b5): this should be replaced with a call to OpenDataset with a qfs that
 	// knows about the store
-	if resBody, err = r.Filesystem().Get(ctx, ds.BodyPath); err != nil {
+	if resBody, err = dsfs.LoadBody(ctx, r.Filesystem(), ds); err != nil {
 		log.Error("error getting from store:", err.Error())
 	}
 	ds.SetBodyFile(resBody)
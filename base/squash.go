@@ -0,0 +1,208 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
+	"github.com/qri-io/qri/repo"
+)
+
+// Squash collapses a range of versions into a single version. history lists
+// every version path of the dataset ref refers to, ordered oldest to newest.
+// from & to are the indexes of the oldest & newest versions to squash. The
+// squashed version has the components & commit time of the newest version in
+// the range. Versions after the range are re-parented onto the squashed
+// version, keeping their commits. The rewrite is recorded in the logbook,
+// and the new head version is returned
+func Squash(ctx context.Context, r repo.Repo, ref dsref.Ref, history []string, from, to int, title, message string) (*dataset.Dataset, error) {
+	if ref.InitID == "" {
+		return nil, fmt.Errorf("squash requires an initID")
+	}
+	if from < 0 || to >= len(history) || from > to {
+		return nil, fmt.Errorf("invalid range of versions to squash")
+	}
+	if from == to {
+		return nil, fmt.Errorf("squashing requires at least two versions")
+	}
+
+	fs := r.Filesystem()
+	newest, err := dsfs.LoadDataset(ctx, fs, history[to])
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		title = fmt.Sprintf("squash %d versions", to-from+1)
+	}
+	if message == "" {
+		titles := make([]string, 0, to-from+1)
+		for _, path := range history[from:to] {
+			ds, err := dsfs.LoadDataset(ctx, fs, path)
+			if err != nil {
+				return nil, err
+			}
+			titles = append(titles, "* "+ds.Commit.Title)
+		}
+		message = strings.Join(append(titles, "* "+newest.Commit.Title), "\n")
+	}
+	cm := &dataset.Commit{
+		Title:     title,
+		Message:   message,
+		Timestamp: newest.Commit.Timestamp,
+	}
+
+	prevPath := ""
+	if from > 0 {
+		prevPath = history[from-1]
+	}
+	head, err := rewriteVersion(ctx, r, ref, newest, prevPath, cm)
+	if err != nil {
+		return nil, err
+	}
+	versions := []logbook.RewrittenVersion{
+		{Dataset: head, Replaces: history[from : to+1]},
+	}
+
+	for _, path := range history[to+1:] {
+		ds, err := dsfs.LoadDataset(ctx, fs, path)
+		if err != nil {
+			return nil, err
+		}
+		if head, err = rewriteVersion(ctx, r, ref, ds, head.Path, nil); err != nil {
+			return nil, err
+		}
+		versions = append(versions, logbook.RewrittenVersion{Dataset: head, Replaces: []string{path}})
+	}
+
+	note := fmt.Sprintf("squash %s through %s", history[from], history[to])
+	if err := writeRewrite(ctx, r, ref, history, len(history)-from, versions, note); err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+// Rebase re-parents versions onto an older version, dropping the versions in
+// between. history lists every version path of the dataset ref refers to,
+// ordered oldest to newest. Versions from index from through the latest
+// version are re-parented onto the version at index onto, keeping their
+// commits. Like squashing, the rewrite is recorded in the logbook, and the new
+// head version is returned
+func Rebase(ctx context.Context, r repo.Repo, ref dsref.Ref, history []string, onto, from int) (*dataset.Dataset, error) {
+	if ref.InitID == "" {
+		return nil, fmt.Errorf("rebase requires an initID")
+	}
+	if onto < 0 || from >= len(history) || onto >= from {
+		return nil, fmt.Errorf("invalid versions to rebase, the version to rebase onto must be older")
+	}
+	if onto == from-1 {
+		return nil, fmt.Errorf("version %s is already based on %s", history[from], history[onto])
+	}
+
+	fs := r.Filesystem()
+	var (
+		head     *dataset.Dataset
+		prevPath = history[onto]
+		versions []logbook.RewrittenVersion
+	)
+	for _, path := range history[from:] {
+		ds, err := dsfs.LoadDataset(ctx, fs, path)
+		if err != nil {
+			return nil, err
+		}
+		if head, err = rewriteVersion(ctx, r, ref, ds, prevPath, nil); err != nil {
+			return nil, err
+		}
+		versions = append(versions, logbook.RewrittenVersion{Dataset: head, Replaces: []string{path}})
+		prevPath = head.Path
+	}
+
+	note := fmt.Sprintf("rebase %s onto %s", history[from], history[onto])
+	if err := writeRewrite(ctx, r, ref, history, len(history)-onto-1, versions, note); err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+// writeRewrite records rewritten versions replacing the newest removed
+// versions of history, and points the stored reference at the new head
+func writeRewrite(ctx context.Context, r repo.Repo, ref dsref.Ref, history []string, removed int, versions []logbook.RewrittenVersion, note string) error {
+	if err := r.Logbook().WriteVersionRewrite(ctx, ref.InitID, removed, versions, note); err != nil {
+		return err
+	}
+
+	// TODO(dustmop): Reference is updated here to keep the refstore in sync,
+	// like CreateDataset. Updating logbook will be enough once the refstore
+	// is removed
+	old := ref
+	old.Path = history[len(history)-1]
+	repo.DeleteVersionInfoShim(r, old)
+	vi := dsref.ConvertDatasetToVersionInfo(versions[len(versions)-1].Dataset)
+	return repo.PutVersionInfoShim(r, &vi)
+}
+
+// rewriteVersion writes a copy of a loaded dataset version with a different
+// previous version. The commit is kept unless cm is provided
+func rewriteVersion(ctx context.Context, r repo.Repo, ref dsref.Ref, ds *dataset.Dataset, prevPath string, cm *dataset.Commit) (*dataset.Dataset, error) {
+	fs := r.Filesystem()
+	if err := OpenDataset(ctx, fs, ds); err != nil {
+		return nil, err
+	}
+	if body := ds.BodyFile(); body != nil && ds.Structure != nil {
+		if _, ok := body.(*dsfs.PartitionedBodyFile); !ok {
+			// write hooks match files by name, give the stored body a package name
+			ds.SetBodyFile(qfs.NewMemfileReader(fmt.Sprintf("/body.%s", ds.Structure.Format), body))
+		}
+	}
+	if cm != nil {
+		cm.Author = ds.Commit.Author
+		ds.Commit = cm
+	}
+	ds.Commit.Signature = ""
+	ds.PreviousPath = prevPath
+	dropComponentPaths(ds)
+
+	path, err := dsfs.WriteDataset(ctx, &sync.Mutex{}, fs.DefaultWriteFS(), ds, r.PrivateKey(), dsfs.SaveSwitches{Pin: true})
+	if err != nil {
+		return nil, err
+	}
+	if ds, err = dsfs.LoadDataset(ctx, fs, path); err != nil {
+		return nil, err
+	}
+	ds.ProfileID = ref.ProfileID
+	ds.Peername = ref.Username
+	ds.Name = ref.Name
+	return ds, nil
+}
+
+// dropComponentPaths clears the paths of a loaded dataset & its components,
+// keeping all other values
+func dropComponentPaths(ds *dataset.Dataset) {
+	ds.Path = ""
+	if ds.Commit != nil {
+		ds.Commit.Path = ""
+	}
+	if ds.Meta != nil {
+		ds.Meta.Path = ""
+	}
+	if ds.Structure != nil {
+		ds.Structure.Path = ""
+	}
+	if ds.Stats != nil {
+		ds.Stats.Path = ""
+	}
+	if ds.Readme != nil {
+		ds.Readme.Path = ""
+	}
+	if ds.Transform != nil {
+		ds.Transform.Path = ""
+	}
+	if ds.Viz != nil {
+		ds.Viz.Path = ""
+	}
+}
@@ -0,0 +1,153 @@
+package base
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+func TestSquash(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+
+	var history []string
+	var head dsref.Ref
+	for i := 1; i <= 5; i++ {
+		ds := run.BuildDataset("squash_test", "json")
+		ds.Meta = &dataset.Meta{Title: fmt.Sprintf("version %d", i)}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(fmt.Sprintf("[%d]", i))))
+		ref, err := run.SaveDataset(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		history = append(history, ref.Path)
+		head = ref
+	}
+	initID, err := r.Logbook().RefToInitID(dsref.Ref{Username: head.Username, Name: head.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.InitID = initID
+
+	if _, err := Squash(ctx, r, head, history, 2, 2, "", ""); err == nil {
+		t.Error("expected squashing a single version to error")
+	}
+	if err := r.Logbook().WriteTag(ctx, initID, "v4", history[3], false); err != nil {
+		t.Fatal(err)
+	}
+
+	// squash versions 2 through 4, re-parenting version 5
+	res, err := Squash(ctx, r, head, history, 1, 3, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := r.Logbook().Items(ctx, head, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 versions after squashing, got %d", len(items))
+	}
+	if items[0].Path != res.Path {
+		t.Errorf("head mismatch. want: %q, got: %q", res.Path, items[0].Path)
+	}
+	if items[2].Path != history[0] {
+		t.Errorf("expected versions before the squashed range to be kept. want: %q, got: %q", history[0], items[2].Path)
+	}
+	if items[1].CommitTitle != "squash 3 versions" {
+		t.Errorf("squashed commit title mismatch. got: %q", items[1].CommitTitle)
+	}
+
+	if res.PreviousPath != items[1].Path {
+		t.Errorf("expected head to be re-parented onto squashed version %q, got: %q", items[1].Path, res.PreviousPath)
+	}
+	if res.Meta == nil || res.Meta.Title != "version 5" {
+		t.Errorf("expected re-parented version to keep its components, got meta: %v", res.Meta)
+	}
+	squashed, err := dsfs.LoadDataset(ctx, r.Filesystem(), items[1].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if squashed.PreviousPath != history[0] || squashed.Meta.Title != "version 4" {
+		t.Errorf("unexpected squashed version. previous path: %q, meta title: %q", squashed.PreviousPath, squashed.Meta.Title)
+	}
+
+	tags, err := r.Logbook().Tags(ctx, initID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Path != items[1].Path {
+		t.Errorf("expected tag to follow the squashed version, got: %v", tags)
+	}
+}
+
+func TestRebase(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+
+	var history []string
+	var head dsref.Ref
+	for i := 1; i <= 5; i++ {
+		ds := run.BuildDataset("rebase_test", "json")
+		ds.Meta = &dataset.Meta{Title: fmt.Sprintf("version %d", i)}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(fmt.Sprintf("[%d]", i))))
+		ref, err := run.SaveDataset(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		history = append(history, ref.Path)
+		head = ref
+	}
+	initID, err := r.Logbook().RefToInitID(dsref.Ref{Username: head.Username, Name: head.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.InitID = initID
+
+	if _, err := Rebase(ctx, r, head, history, 2, 3); err == nil {
+		t.Error("expected rebasing a version onto its own parent to error")
+	}
+	if _, err := Rebase(ctx, r, head, history, 3, 1); err == nil {
+		t.Error("expected rebasing onto a newer version to error")
+	}
+
+	// re-parent versions 4 & 5 onto version 1, dropping versions 2 & 3
+	res, err := Rebase(ctx, r, head, history, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := r.Logbook().Items(ctx, head, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 versions after rebasing, got %d", len(items))
+	}
+	if items[0].Path != res.Path {
+		t.Errorf("head mismatch. want: %q, got: %q", res.Path, items[0].Path)
+	}
+	if items[2].Path != history[0] {
+		t.Errorf("expected the version rebased onto to be kept. want: %q, got: %q", history[0], items[2].Path)
+	}
+
+	rebased, err := dsfs.LoadDataset(ctx, r.Filesystem(), items[1].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebased.PreviousPath != history[0] || rebased.Meta.Title != "version 4" {
+		t.Errorf("unexpected rebased version. previous path: %q, meta title: %q", rebased.PreviousPath, rebased.Meta.Title)
+	}
+	if res.PreviousPath != items[1].Path || res.Meta.Title != "version 5" {
+		t.Errorf("unexpected head. previous path: %q, meta title: %q", res.PreviousPath, res.Meta.Title)
+	}
+}
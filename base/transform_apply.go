@@ -15,7 +15,8 @@ import (
 
 // TODO(dustmop): Tests. Especially once the `apply` command exists.
 
-// TransformApply applies the transform script to order to modify the changing dataset
+// TransformApply applies the transform script to order to modify the changing dataset.
+// opts are passed to script execution after the default options
 func TransformApply(
 	ctx context.Context,
 	ds *dataset.Dataset,
@@ -24,6 +25,7 @@ func TransformApply(
 	str ioes.IOStreams,
 	scriptOut io.Writer,
 	secrets map[string]string,
+	opts ...func(*startf.ExecOpts),
 ) error {
 	pro, err := r.Profile()
 	if err != nil {
@@ -50,13 +52,13 @@ func TransformApply(
 	// the startf package will use this function to ensure the same components aren't modified
 	mutateCheck := startf.MutatedComponentsFunc(target)
 
-	opts := []func(*startf.ExecOpts){
+	opts = append([]func(*startf.ExecOpts){
 		startf.AddQriRepo(r),
 		startf.AddMutateFieldCheck(mutateCheck),
 		startf.SetErrWriter(scriptOut),
 		startf.SetSecrets(secrets),
 		startf.AddDatasetLoader(loader),
-	}
+	}, opts...)
 
 	if err = startf.ExecScript(ctx, target, head, opts...); err != nil {
 		return err
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewBisectCommand creates a new `qri bisect` cobra command
+func NewBisectCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &BisectOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "bisect DATASET",
+		Short: "find the version that broke a dataset",
+		Long: `Bisect binary searches the history of a dataset for the first version that
+fails a check, reporting the version & its commit. Bisecting tests about
+log2(N) versions of a history N versions long.
+
+Checks are either a starlark script passed with --check, or a JSON schema
+passed with --schema. Check scripts define a check function that's called
+with the dataset & its body, and return True for good versions. Calling
+fail("reason") also marks a version as bad, and the reason is reported with
+the result:
+
+  def check(ds, body):
+    for row in body:
+      if row[1] < 0:
+        fail("negative duration for %s" % row[0])
+    return True
+
+Without a check, bisect prints the next version to inspect. Mark versions
+with --good & --bad, and run bisect again with the same marks until the first
+bad version is found. Marks also narrow a search that uses a check. If no
+version is marked bad the latest version is tested first, and the versions
+before the first are assumed good.`,
+		Example: `  # Find the version that introduced negative durations:
+  $ qri bisect me/movies --check no_negative_durations.star
+
+  # Find the first version that doesn't match a schema:
+  $ qri bisect me/movies --schema schema.json
+
+  # Bisect by hand, marking the 10th version back as good:
+  $ qri bisect me/movies --good me/movies~10 --bad me/movies`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.CheckFilename, "check", "", "starlark script defining a check(ds, body) function")
+	cmd.MarkFlagFilename("check", "star")
+	cmd.Flags().StringVar(&o.SchemaFilename, "schema", "", "JSON schema good versions validate against")
+	cmd.MarkFlagFilename("schema", "json")
+	cmd.Flags().StringSliceVar(&o.Good, "good", nil, "versions known to be good, as paths or references")
+	cmd.Flags().StringSliceVar(&o.Bad, "bad", nil, "versions known to be bad, as paths or references")
+	cmd.Flags().StringVar(&o.Format, "format", "text", "output format. One of: [text|json]")
+
+	return cmd
+}
+
+// BisectOptions encapsulates state for the bisect command
+type BisectOptions struct {
+	ioes.IOStreams
+
+	Refs           *RefSelect
+	CheckFilename  string
+	SchemaFilename string
+	Good           []string
+	Bad            []string
+	Format         string
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *BisectOptions) Complete(f Factory, args []string) (err error) {
+	if o.Format != "text" && o.Format != "json" {
+		return fmt.Errorf(`%q is not a valid output format. Please use one of: "text", "json"`, o.Format)
+	}
+	if o.CheckFilename != "" && o.SchemaFilename != "" {
+		return errors.New(lib.ErrBadArgs, "please provide either --check or --schema, not both")
+	}
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the bisect command
+func (o *BisectOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.BisectParams{
+		Ref:            o.Refs.Ref(),
+		CheckFilename:  o.CheckFilename,
+		SchemaFilename: o.SchemaFilename,
+		Good:           o.Good,
+		Bad:            o.Bad,
+	}
+	res := &lib.BisectResult{}
+	if err := o.DatasetMethods.Bisect(p, res); err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	for _, mark := range res.Tested {
+		result := "good"
+		if !mark.Good {
+			result = "bad"
+		}
+		printInfo(o.ErrOut, "tested %s: %s", mark.Path, result)
+	}
+
+	if res.Next != nil {
+		printInfo(o.Out, "next version to check, %d versions left (about %d steps):", res.Remaining, res.Steps)
+		fmt.Fprint(o.Out, dslogItemStringer(*res.Next).String())
+		printInfo(o.Out, "mark it with --good %s or --bad %s", res.Next.Path, res.Next.Path)
+		return nil
+	}
+
+	printSuccess(o.Out, "first bad version:")
+	fmt.Fprint(o.Out, dslogItemStringer(*res.FirstBad).String())
+	if res.Reason != "" {
+		printInfo(o.Out, "reason: %s", res.Reason)
+	}
+	return nil
+}
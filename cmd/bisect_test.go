@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBisectCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_bisect", "qri_test_bisect")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml --title=ten me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --title=twenty me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_thirty.csv --title=thirty me/test_movies")
+
+	dir, err := ioutil.TempDir("", "qri_test_bisect_check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	check := filepath.Join(dir, "check.star")
+	script := `
+def check(ds, body):
+  if len(body) > 15:
+    fail("too many rows: %d" % len(body))
+  return True
+`
+	if err := ioutil.WriteFile(check, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := r.MustExec(t, "qri bisect me/test_movies --check "+check)
+	if !strings.Contains(output, "first bad version") || !strings.Contains(output, "twenty") {
+		t.Errorf("expected the twenty row version to be the first bad version, got:\n%s", output)
+	}
+	if !strings.Contains(output, "too many rows") {
+		t.Errorf("expected output to include the reason the check failed, got:\n%s", output)
+	}
+
+	// bisect by hand
+	output = r.MustExec(t, "qri bisect me/test_movies --good me/test_movies~2")
+	if !strings.Contains(output, "next version to check") || !strings.Contains(output, "thirty") {
+		t.Errorf("expected the latest version to be checked first, got:\n%s", output)
+	}
+	output = r.MustExec(t, "qri bisect me/test_movies --good me/test_movies~2 --bad me/test_movies")
+	if !strings.Contains(output, "twenty") {
+		t.Errorf("expected the twenty row version to be checked next, got:\n%s", output)
+	}
+	output = r.MustExec(t, "qri bisect me/test_movies --good me/test_movies~2 --bad me/test_movies,me/test_movies~1")
+	if !strings.Contains(output, "first bad version") || !strings.Contains(output, "twenty") {
+		t.Errorf("expected the twenty row version to be the first bad version, got:\n%s", output)
+	}
+
+	if err := r.ExecCommand("qri bisect me/test_movies --good me/test_movies --bad me/test_movies~1"); err == nil {
+		t.Error("expected a good version after a bad version to error")
+	}
+	if err := r.ExecCommand("qri bisect me/test_movies --check " + check + " --schema schema.json"); err == nil {
+		t.Error("expected providing both a check & a schema to error")
+	}
+}
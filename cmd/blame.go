@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewBlameCommand creates a new `qri blame` cobra command
+func NewBlameCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &BlameOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "blame DATASET",
+		Short: "show which version last changed each row of a dataset",
+		Long: `Blame shows the version, commit title, author, and time of the commit that
+last changed each row of a dataset body. Rows are matched across versions by
+a primary key column, set with --key. Object bodies default to matching by
+entry key, tabular bodies default to the first column.
+
+Use --column to blame individual cells of one or more columns instead of
+whole rows, and --offset & --limit to blame a range of rows. History is read
+one version at a time, stopping as soon as every row has been attributed.`,
+		Example: `  # Blame every row of me/annual_pop, matching rows by country code:
+  $ qri blame me/annual_pop --key country_code
+
+  # Find the version that last changed the population of the first ten rows:
+  $ qri blame me/annual_pop --key country_code --column population --limit 10`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Key, "key", "", "primary key column used to match rows across versions")
+	cmd.Flags().StringSliceVar(&o.Columns, "column", nil, "blame cells of these columns instead of whole rows")
+	cmd.Flags().IntVar(&o.Offset, "offset", 0, "number of rows to skip")
+	cmd.Flags().IntVar(&o.Limit, "limit", -1, "maximum number of rows to blame, default all")
+	cmd.Flags().StringVar(&o.Format, "format", "text", "output format. One of: [text|json]")
+
+	return cmd
+}
+
+// BlameOptions encapsulates state for the blame command
+type BlameOptions struct {
+	ioes.IOStreams
+
+	Refs    *RefSelect
+	Key     string
+	Columns []string
+	Offset  int
+	Limit   int
+	Format  string
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *BlameOptions) Complete(f Factory, args []string) (err error) {
+	if o.Format != "text" && o.Format != "json" {
+		return fmt.Errorf(`%q is not a valid output format. Please use one of: "text", "json"`, o.Format)
+	}
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the blame command
+func (o *BlameOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.BlameParams{
+		Ref:     o.Refs.Ref(),
+		Key:     o.Key,
+		Columns: o.Columns,
+		Offset:  o.Offset,
+		Limit:   o.Limit,
+	}
+	res := []lib.BlameEntry{}
+	if err := o.DatasetMethods.Blame(p, &res); err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	for _, e := range res {
+		key := fmt.Sprintf("%v", e.Key)
+		if e.Column != "" {
+			key = fmt.Sprintf("%s.%s", key, e.Column)
+		}
+		fmt.Fprintf(o.Out, "%s\t%s\t%s\t%s\t%s\n",
+			shortPath(e.Version.Path),
+			e.Version.Timestamp.In(StringerLocation).Format(time.RFC3339),
+			e.Version.Author,
+			key,
+			e.Version.CommitTitle,
+		)
+	}
+	return nil
+}
+
+// shortPath abbreviates a version path to the last characters of its hash
+func shortPath(path string) string {
+	if len(path) > 8 {
+		return path[len(path)-8:]
+	}
+	return path
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlameCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_blame", "qri_test_blame")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml --title=first me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --title=second me/test_movies")
+
+	// rows from the first version keep their attribution
+	output := r.MustExec(t, "qri blame me/test_movies --limit 1")
+	if strings.Count(output, "\n") != 1 || !strings.Contains(output, "first") {
+		t.Errorf("expected first row to be blamed on the first version, got:\n%s", output)
+	}
+
+	// rows added by the second version are blamed on it
+	output = r.MustExec(t, "qri blame me/test_movies --offset 15 --limit 2 --column duration")
+	if strings.Count(output, "second") != 2 || !strings.Contains(output, ".duration") {
+		t.Errorf("expected added rows to be blamed on the second version, got:\n%s", output)
+	}
+
+	if err := r.ExecCommand("qri blame me/test_movies --key nope"); err != nil {
+		t.Errorf("expected rows without a key to be skipped, got error: %s", err)
+	}
+	if err := r.ExecCommand("qri blame me/test_movies --format xml"); err == nil {
+		t.Error("expected invalid format to error")
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewChangelogCommand creates a new `qri changelog` cobra command
+func NewChangelogCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &ChangelogOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "changelog DATASET",
+		Short: "describe the changes each version of a dataset made",
+		Long: `Changelog walks the history of a dataset and describes what each version
+changed: the commit title & message, components that changed, columns added,
+removed, or retyped, and the change in number of rows. Versions are listed
+newest first, grouped under their commit title & tags.
+
+Use --since to describe only versions after a tag, version path, or revision
+like ~3. Changelogs are written as markdown, or as json with --format json.
+
+To keep a changelog in a dataset's readme, save with --changelog. The readme
+section between <!-- changelog --> and <!-- /changelog --> comments is
+replaced with the changelog of the versions before the one being saved.`,
+		Example: `  # Show the changelog of a dataset:
+  $ qri changelog me/annual_pop
+
+  # Describe changes since the version tagged v1.0.0:
+  $ qri changelog me/annual_pop --since v1.0.0
+
+  # Update the changelog in the readme of a dataset with the next save:
+  $ qri save me/annual_pop --body new_data.csv --changelog`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Since, "since", "", "only describe versions after this tag, path, or revision")
+	cmd.Flags().StringVar(&o.Format, "format", "markdown", "output format. One of: [markdown|json]")
+
+	return cmd
+}
+
+// ChangelogOptions encapsulates state for the changelog command
+type ChangelogOptions struct {
+	ioes.IOStreams
+
+	Refs   *RefSelect
+	Since  string
+	Format string
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *ChangelogOptions) Complete(f Factory, args []string) (err error) {
+	if o.Format != "markdown" && o.Format != "json" {
+		return fmt.Errorf(`%q is not a valid output format. Please use one of: "markdown", "json"`, o.Format)
+	}
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the changelog command
+func (o *ChangelogOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.ChangelogParams{
+		Ref:   o.Refs.Ref(),
+		Since: o.Since,
+	}
+	res := &lib.ChangelogResult{}
+	if err := o.DatasetMethods.Changelog(p, res); err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res.Versions)
+	}
+	fmt.Fprint(o.Out, res.Markdown)
+	return nil
+}
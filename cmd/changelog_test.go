@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangelogCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_changelog", "qri_test_changelog")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml --title=first me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --title=second me/test_movies")
+	r.MustExec(t, "qri tag me/test_movies~1 v1")
+
+	output := r.MustExec(t, "qri changelog me/test_movies")
+	for _, expect := range []string{"# test_peer_changelog/test_movies changelog", "## second", "## v1: first", "created dataset", "rows: 8 → 18 (+10)"} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected changelog to contain %q, got:\n%s", expect, output)
+		}
+	}
+
+	output = r.MustExec(t, "qri changelog me/test_movies --since v1 --format json")
+	if strings.Contains(output, `"first"`) || !strings.Contains(output, `"rowsChange": 10`) {
+		t.Errorf("expected json changelog of versions after v1, got:\n%s", output)
+	}
+
+	r.MustExec(t, "qri save --file=testdata/movies/more_movies.md --body=testdata/movies/body_ten.csv --title=third --changelog me/test_movies")
+	output = r.MustExec(t, "qri get readme.script me/test_movies")
+	for _, expect := range []string{"<!-- changelog -->", "## third", "rows: 18 → 8 (-10)", "## second"} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected readme changelog to contain %q, got:\n%s", expect, output)
+		}
+	}
+
+	if err := r.ExecCommand("qri changelog me/test_movies --format xml"); err == nil {
+		t.Error("expected invalid format to error")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo/gen"
 	"github.com/spf13/cobra"
 )
 
@@ -111,12 +112,38 @@ field checkout: https://github.com/qri-io/qri/blob/master/config/readme.md`,
 		},
 	}
 
+	rotateKey := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "replace your private key with a new one",
+		Long: `'qri config rotate-key' generates a new private key and replaces the key
+of your profile with it. The change is signed with your current key and
+recorded in your logbook, so peers that sync your history can verify that
+the new key belongs to you. Your profile ID stays the same.
+
+If a registry is configured it's told of the new key first. Your logbook is
+re-encrypted with the new key. Keys can't be rotated while 'qri connect' is
+running. If the p2p node uses your profile key it uses the new key the next
+time it starts.
+
+Keep the old key until peers have synced your history after the rotation.`,
+		Example: `  # Rotate to a new key:
+  $ qri config rotate-key`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f); err != nil {
+				return err
+			}
+			return o.RotateKey(f.CryptoGenerator())
+		},
+	}
+
 	get.Flags().BoolVar(&o.WithPrivateKeys, "with-private-keys", false, "include private keys in export")
 	get.Flags().BoolVarP(&o.Concise, "concise", "c", false, "print output without indentation, only applies to json format")
 	get.Flags().StringVarP(&o.Format, "format", "f", "yaml", "data format to export. either json or yaml")
 	get.Flags().StringVarP(&o.Output, "output", "o", "", "path to export to")
 	cmd.AddCommand(get)
 	cmd.AddCommand(set)
+	cmd.AddCommand(rotateKey)
 
 	return cmd
 }
@@ -229,6 +256,17 @@ func (o *ConfigOptions) Set(args []string) (err error) {
 	return nil
 }
 
+// RotateKey replaces the profile private key with a newly generated key
+func (o *ConfigOptions) RotateKey(g gen.CryptoGenerator) error {
+	privKey, _ := g.GeneratePrivateKeyAndPeerID()
+	res := &config.ProfilePod{}
+	if err := o.ProfileMethods.RotateKey(&lib.RotateKeyParams{PrivKey: privKey}, res); err != nil {
+		return err
+	}
+	printSuccess(o.Out, "rotated private key for profile %s", res.ID)
+	return nil
+}
+
 func setPhotoPath(m *lib.ProfileMethods, proppath, filepath string) error {
 	f, err := loadFileIfPath(filepath)
 	if err != nil {
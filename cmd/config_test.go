@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigRotateKey(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_rotate_key", "qri_test_config_rotate_key")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml me/test_movies")
+	id := r.MustExec(t, "qri config get profile.id")
+	prevKey := r.MustExec(t, "qri config get profile.privkey --with-private-keys")
+
+	output := r.MustExec(t, "qri config rotate-key")
+	if !strings.Contains(output, "rotated private key") {
+		t.Errorf("expected rotate-key to report success, got: %s", output)
+	}
+
+	if got := r.MustExec(t, "qri config get profile.id"); got != id {
+		t.Errorf("expected profile id to stay %s, got %s", id, got)
+	}
+	if got := r.MustExec(t, "qri config get profile.privkey --with-private-keys"); got == prevKey {
+		t.Error("expected private key to change")
+	}
+
+	// history written before the rotation is still readable & writable
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv me/test_movies")
+	output = r.MustExec(t, "qri log me/test_movies")
+	if count := strings.Count(output, "Commit:"); count != 2 {
+		t.Errorf("expected 2 versions in log after rotating, got %d:\n%s", count, output)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/base/component"
 	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/p2p"
 	"github.com/spf13/cobra"
 )
 
@@ -256,6 +257,16 @@ func (o *DAGOptions) Info() (err error) {
 				}
 				out += fmt.Sprintf("%s\n", humanize.Bytes(info.Sizes[index]))
 			}
+			if o.Label == "" {
+				dedup := &p2p.DedupInfo{}
+				if err = o.DatasetMethods.DAGDedup(s, dedup); err != nil {
+					return err
+				}
+				if dedup.PrevPath != "" {
+					out += fmt.Sprintf("Dedup Ratio: %.1f%% (%s of %s, %d of %d blocks shared with previous version)\n",
+						dedup.Ratio()*100, humanize.Bytes(dedup.SharedSize), humanize.Bytes(dedup.Size), dedup.SharedBlocks, dedup.Blocks)
+				}
+			}
 			buffer = []byte(out)
 
 		}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/errors"
 )
 
@@ -189,3 +196,48 @@ func TestDAGInfo(t *testing.T) {
 		run.IOReset()
 	}
 }
+
+func TestDAGInfoDedupRatio(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_dag_dedup", "qri_test_dag_dedup")
+	defer run.Delete()
+
+	threshold, min, avg, max := dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize
+	defer func() {
+		dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize = threshold, min, avg, max
+	}()
+	dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize = 4096, 256, 1024, 4096
+
+	tmpDir := run.MakeTmpDir(t, "dag_dedup")
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	writeBody := func(edit int) {
+		buf := &bytes.Buffer{}
+		buf.WriteString("city,pop\n")
+		for i := 0; i < 2000; i++ {
+			pop := i * 7919 % 100003
+			if i == edit {
+				pop = -1
+			}
+			fmt.Fprintf(buf, "city_%d,%d\n", i, pop)
+		}
+		run.MustWriteFile(t, bodyPath, buf.String())
+	}
+
+	writeBody(-1)
+	run.MustExec(t, "qri save --body "+bodyPath+" me/dedup")
+	output := run.MustExec(t, "qri dag info me/dedup")
+	if strings.Contains(output, "Dedup Ratio") {
+		t.Errorf("expected no dedup ratio for a dataset with one version, got:\n%s", output)
+	}
+
+	writeBody(1000)
+	run.MustExec(t, "qri save --body "+bodyPath+" me/dedup")
+	output = run.MustExec(t, "qri dag info me/dedup")
+	re := regexp.MustCompile(`Dedup Ratio: ([0-9.]+)%`)
+	match := re.FindStringSubmatch(output)
+	if match == nil {
+		t.Fatalf("expected dag info to report a dedup ratio, got:\n%s", output)
+	}
+	if ratio, _ := strconv.ParseFloat(match[1], 64); ratio < 50 {
+		t.Errorf("expected most of an edited body to be shared with the previous version. ratio: %s%%", match[1])
+	}
+}
@@ -185,3 +185,8 @@ func (t TestFactory) SQLMethods() (*lib.SQLMethods, error) {
 func (t TestFactory) RenderMethods() (*lib.RenderMethods, error) {
 	return lib.NewRenderMethods(t.inst), nil
 }
+
+// TransformMethods generates a lib.TransformMethods from internal state
+func (t TestFactory) TransformMethods() (*lib.TransformMethods, error) {
+	return lib.NewTransformMethods(t.inst), nil
+}
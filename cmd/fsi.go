@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 
 	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/lib"
 	"github.com/spf13/cobra"
@@ -49,7 +50,43 @@ func NewFSICommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 		},
 	}
 
-	cmd.AddCommand(link, unlink)
+	ao := &FSIAutosaveOptions{IOStreams: ioStreams}
+	autosave := &cobra.Command{
+		Use:   "autosave [PATH]",
+		Short: "automatically save changes to a linked directory",
+		Long: `
+Autosave saves a new version of a dataset whenever its linked working
+directory changes. Once the directory has gone without changes for a short
+quiet period, qri checks the directory for errors & saves it with a commit
+message describing the changes. Saves of the same directory are rate limited.
+
+Changes are only saved while qri is watching the filesystem, which happens
+when running ` + "`qri connect`" + `. The quiet period & minimum time between saves are
+set in the autosave section of the qri config.`,
+		Example: `  # Enable autosave for the current directory:
+  $ qri workdir autosave
+
+  # Stop saving for now, keeping autosave settings:
+  $ qri workdir autosave --pause
+
+  # Start saving again:
+  $ qri workdir autosave --resume
+
+  # Turn autosave off:
+  $ qri workdir autosave --off`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ao.Complete(f, args); err != nil {
+				return err
+			}
+			return ao.Run()
+		},
+	}
+	autosave.Flags().BoolVar(&ao.Off, "off", false, "turn autosave off")
+	autosave.Flags().BoolVar(&ao.Pause, "pause", false, "pause autosave")
+	autosave.Flags().BoolVar(&ao.Resume, "resume", false, "resume a paused autosave")
+
+	cmd.AddCommand(link, unlink, autosave)
 	return cmd
 }
 
@@ -121,3 +158,52 @@ func (o *FSIOptions) Unlink() error {
 	}
 	return nil
 }
+
+// FSIAutosaveOptions encapsulates state for the workdir autosave command
+type FSIAutosaveOptions struct {
+	ioes.IOStreams
+
+	Path   string
+	Off    bool
+	Pause  bool
+	Resume bool
+
+	FSIMethods *lib.FSIMethods
+}
+
+// Complete adds any missing configuration that can only be added just before
+// calling Run
+func (o *FSIAutosaveOptions) Complete(f Factory, args []string) (err error) {
+	o.Path = "."
+	if len(args) > 0 {
+		o.Path = args[0]
+	}
+	o.FSIMethods, err = f.FSIMethods()
+	return err
+}
+
+// Run executes the workdir autosave command
+func (o *FSIAutosaveOptions) Run() error {
+	p := &lib.AutosaveParams{
+		Dir:     o.Path,
+		Disable: o.Off,
+		Pause:   o.Pause,
+		Resume:  o.Resume,
+	}
+	res := &config.AutosaveLink{}
+	if err := o.FSIMethods.Autosave(p, res); err != nil {
+		return err
+	}
+
+	switch {
+	case o.Off:
+		printSuccess(o.Out, "autosave disabled for %s", res.Path)
+	case o.Pause:
+		printSuccess(o.Out, "autosave paused for %s", res.Path)
+	case o.Resume:
+		printSuccess(o.Out, "autosave resumed for %s", res.Path)
+	default:
+		printSuccess(o.Out, "autosave enabled for %s", res.Path)
+	}
+	return nil
+}
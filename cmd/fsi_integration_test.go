@@ -1934,3 +1934,96 @@ working directory clean
 `
 	return fmt.Sprintf(template, dsref)
 }
+
+// Test that hooks in a linked directory can reject saves, and are skipped with
+// --no-verify
+func TestSaveHooks(t *testing.T) {
+	run := NewFSITestRunner(t, "test_peer_save_hooks", "qri_test_save_hooks")
+	defer run.Delete()
+
+	workDir := run.CreateAndChdirToWorkDir("hooked")
+	run.MustExec(t, "qri init --name hooked --format csv")
+
+	hooksDir := filepath.Join(workDir, ".qri", "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run.MustWriteFile(t, filepath.Join(hooksDir, "pre-save.star"), `
+def pre_save(ds):
+  if not ds.get("readme"):
+    fail("add a readme before saving")
+`)
+
+	err := run.ExecCommand("qri save")
+	if err == nil {
+		t.Fatal("expected pre-save hook to reject the save")
+	}
+	expectErr := "pre-save hook failed: add a readme before saving"
+	if err.Error() != expectErr {
+		t.Errorf("error mismatch, expect: %q, got: %q", expectErr, err.Error())
+	}
+
+	// the hooks directory isn't part of the dataset
+	output := run.MustExec(t, "qri status")
+	if strings.Contains(output, ".qri") {
+		t.Errorf("expected status to ignore the hooks directory, got:\n%s", output)
+	}
+
+	run.MustExec(t, "qri save --no-verify")
+
+	run.MustWriteFile(t, filepath.Join(workDir, "readme.md"), "# hooked\n")
+	run.MustExec(t, "qri save")
+}
+
+// Test that a body directory is saved as a partitioned body, and checked out as
+// a directory of partitions
+func TestPartitionedBody(t *testing.T) {
+	run := NewFSITestRunner(t, "test_peer_partitioned_body", "qri_test_partitioned_body")
+	defer run.Delete()
+
+	workDir := run.CreateAndChdirToWorkDir("by_month")
+	run.MustExec(t, "qri init --name by_month --format csv")
+	if err := os.Remove(filepath.Join(workDir, "body.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(workDir, "structure.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(workDir, "body"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	run.MustWriteFile(t, filepath.Join(workDir, "body", "2020-01.csv"), "month,sales\njan,10\n")
+	run.MustWriteFile(t, filepath.Join(workDir, "body", "2020-02.csv"), "month,sales\nfeb,20\n")
+
+	output := run.MustExec(t, "qri status")
+	if !strings.Contains(output, "add: body (source: body)") {
+		t.Errorf("expected status to list the body directory, got:\n%s", output)
+	}
+
+	run.MustExec(t, "qri save")
+	output = run.MustExecCombinedOutErr(t, "qri status")
+	if diff := cmpTextLines(cleanStatusMessage("test_peer_partitioned_body/by_month"), output); diff != "" {
+		t.Errorf("qri status (-want +got):\n%s", diff)
+	}
+
+	run.MustWriteFile(t, filepath.Join(workDir, "body", "2020-02.csv"), "month,sales\nfeb,25\n")
+	output = run.MustExec(t, "qri status")
+	if !strings.Contains(output, "modified: body (source: body)") {
+		t.Errorf("expected status to show a modified body, got:\n%s", output)
+	}
+	run.MustExec(t, "qri save")
+
+	output = run.MustExec(t, "qri get body --format csv me/by_month")
+	expectBody := "month,sales\njan,10\nfeb,25\n\n"
+	if diff := cmp.Diff(expectBody, output); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+
+	run.ClearFSIPath(t, "me/by_month")
+	run.CreateAndChdirToWorkDir("checked_out")
+	run.MustExec(t, "qri checkout me/by_month")
+	dirContents := listDirectory(filepath.Join(run.RootPath, "checked_out", "by_month", "body"))
+	if diff := cmp.Diff([]string{"2020-01.csv", "2020-02.csv"}, dirContents); diff != "" {
+		t.Errorf("body directory contents (-want +got):\n%s", diff)
+	}
+}
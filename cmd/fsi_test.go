@@ -61,3 +61,38 @@ func TestFSILinkingCommands(t *testing.T) {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
 }
+
+func TestFSIAutosaveCommand(t *testing.T) {
+	runner := NewFSITestRunner(t, "test_peer_fsi_autosave", "fsi_autosave")
+	defer runner.Delete()
+
+	runner.CreateAndChdirToWorkDir("autosave_me")
+	runner.MustExec(t, "qri init --name autosave_me --format csv")
+
+	output := runner.MustExec(t, "qri workdir autosave")
+	expect := "autosave enabled for /tmp/autosave_me\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("autosave output (-want +got):\n%s", diff)
+	}
+
+	output = runner.MustExec(t, "qri workdir autosave --pause")
+	expect = "autosave paused for /tmp/autosave_me\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("autosave pause output (-want +got):\n%s", diff)
+	}
+
+	if err := runner.ExecCommand("qri workdir autosave --pause --off"); err == nil {
+		t.Error("expected using multiple autosave flags to error")
+	}
+
+	output = runner.MustExec(t, "qri workdir autosave --off")
+	expect = "autosave disabled for /tmp/autosave_me\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("autosave off output (-want +got):\n%s", diff)
+	}
+
+	runner.ChdirToRoot()
+	if err := runner.ExecCommand("qri workdir autosave"); err == nil {
+		t.Error("expected enabling autosave for an unlinked directory to error")
+	}
+}
@@ -32,7 +32,16 @@ dataset and its fields.`,
   $ qri get meta me/annual_pop
 
   # Print the dataset body size to the console:
-  $ qri get structure.length me/annual_pop`,
+  $ qri get structure.length me/annual_pop
+
+  # Print the body of an old version, fetching it from a remote if it isn't
+  # stored locally, and dropping it from local storage afterwards:
+  $ qri get body me/annual_pop@/ipfs/QmHash --evict
+
+  # Read the second page of a remote dataset's body without pulling it,
+  # keeping only two columns:
+  $ qri get body b5/world_bank_population --remote registry \
+      --page 2 --page-size 100 --columns country,year`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -54,10 +63,12 @@ dataset and its fields.`,
 	cmd.Flags().IntVar(&o.PageSize, "page-size", -1, "for body, limit how many entries to get per page")
 	cmd.Flags().IntVar(&o.Page, "page", -1, "for body, page at which to get entries")
 	cmd.Flags().BoolVarP(&o.All, "all", "a", true, "for body, whether to get all entries")
+	cmd.Flags().StringSliceVar(&o.Columns, "columns", nil, "for body from a remote, only get the listed columns")
 	cmd.Flags().StringVarP(&o.Outfile, "outfile", "o", "", "file to write output to")
 
 	cmd.Flags().BoolVar(&o.Offline, "offline", false, "prevent network access")
 	cmd.Flags().StringVar(&o.Remote, "remote", "", "name to get any remote data from")
+	cmd.Flags().BoolVar(&o.Evict, "evict", false, "drop an old, published version from local storage after getting it")
 
 	return cmd
 }
@@ -73,6 +84,7 @@ type GetOptions struct {
 	Page     int
 	PageSize int
 	All      bool
+	Columns  []string
 
 	Pretty    bool
 	HasPretty bool
@@ -80,6 +92,7 @@ type GetOptions struct {
 
 	Offline bool
 	Remote  string
+	Evict   bool
 
 	DatasetMethods *lib.DatasetMethods
 }
@@ -119,6 +132,9 @@ func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 		if !o.All {
 			return fmt.Errorf("can only use --all flag when getting body")
 		}
+		if len(o.Columns) > 0 {
+			return fmt.Errorf("can only use --columns flag when getting body")
+		}
 	}
 
 	return nil
@@ -161,12 +177,14 @@ func (o *GetOptions) Run() (err error) {
 		Offset:       page.Offset(),
 		Limit:        page.Limit(),
 		All:          o.All,
+		Columns:      o.Columns,
 		Outfile:      o.Outfile,
 		// Generate a filename only if we're outputting to a terminal (not a pipe), and we're
 		// outputting a zip. lib.Get will also check that we're outputting a zip, this check is
 		// repeated here for clarity.
 		GenFilename: o.Outfile == "" && stdoutIsTerminal() && o.Format == "zip",
 		Remote:      o.Remote,
+		Evict:       o.Evict,
 	}
 	res := lib.GetResult{}
 	if err = o.DatasetMethods.Get(&p, &res); err != nil {
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/lineage"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewLineageCommand creates a new `qri lineage` cobra command
+func NewLineageCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &LineageOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "lineage DATASET",
+		Short: "show datasets that feed into or depend on a dataset",
+		Long: `lineage traces the datasets connected to a dataset by transform scripts.
+When a transform loads a dataset, the exact version it read is recorded in
+the transform component of the version being saved.
+
+By default lineage walks upstream, listing every version that fed into a
+dataset version. If the reference doesn't include a version path the latest
+version is used. Use --downstream to list datasets whose transforms read any
+version of the given dataset instead.`,
+		Example: `  # show what fed into the latest version of me/annual_pop:
+  $ qri lineage me/annual_pop
+
+  # show datasets that depend on b5/world_bank_population as a graphviz image:
+  $ qri lineage b5/world_bank_population --downstream --format dot | dot -Tpng > lineage.png`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Upstream, "upstream", false, "show datasets this dataset reads from (default)")
+	cmd.Flags().BoolVar(&o.Downstream, "downstream", false, "show datasets that read from this dataset")
+	cmd.Flags().StringVar(&o.Format, "format", "text", "output format. One of: [text|json|dot]")
+
+	return cmd
+}
+
+// LineageOptions encapsulates state for the lineage command
+type LineageOptions struct {
+	ioes.IOStreams
+
+	Refs       *RefSelect
+	Upstream   bool
+	Downstream bool
+	Format     string
+
+	LogMethods *lib.LogMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *LineageOptions) Complete(f Factory, args []string) (err error) {
+	if o.Upstream && o.Downstream {
+		return errors.New(fmt.Errorf("invalid flags"), "cannot use both --upstream and --downstream flags")
+	}
+	if o.Format != "text" && o.Format != "json" && o.Format != "dot" {
+		return fmt.Errorf(`%q is not a valid output format. Please use one of: "text", "json", "dot"`, o.Format)
+	}
+
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+
+	o.LogMethods, err = f.LogMethods()
+	return
+}
+
+// Run executes the lineage command
+func (o *LineageOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.LineageParams{
+		Ref:       o.Refs.Ref(),
+		Direction: "upstream",
+	}
+	if o.Downstream {
+		p.Direction = "downstream"
+	}
+
+	res := &lineage.Graph{}
+	if err := o.LogMethods.Lineage(p, res); err != nil {
+		return err
+	}
+
+	switch o.Format {
+	case "json":
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	case "dot":
+		return res.WriteDOT(o.Out)
+	default:
+		printLineage(o.Out, res, o.Downstream)
+	}
+	return nil
+}
+
+func printLineage(w io.Writer, g *lineage.Graph, downstream bool) {
+	if len(g.Edges) == 0 {
+		printInfo(w, "no connected datasets found")
+		return
+	}
+	for _, e := range g.Edges {
+		if downstream {
+			fmt.Fprintf(w, "%s\n  <- %s\n", e.Downstream, e.Upstream)
+		} else {
+			fmt.Fprintf(w, "%s\n  -> %s\n", e.Upstream, e.Downstream)
+		}
+	}
+}
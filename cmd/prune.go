@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewPruneCommand creates a new `qri prune` cobra command
+func NewPruneCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &PruneOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "prune [DATASET]",
+		Short: "remove versions a retention policy doesn't keep",
+		Long: `Prune removes versions of a dataset that its retention policy doesn't keep
+from history & local storage. The latest version is never pruned.
+
+Retention policies are set in the retention section of the config, with a
+default policy and per-dataset policies. A version is kept if any rule of the
+policy keeps it:
+
+  keeplast     the number of most recent versions to keep
+  keepwithin   keep versions committed within a duration, like 72h or 30d
+  keeptagged   keep all tagged versions
+  keepdaily    beyond those, keep the latest version of this many days
+  keepweekly   beyond those, keep the latest version of this many weeks
+  keepmonthly  beyond those, keep the latest version of this many months
+
+Passing any --keep flag overrides configured policies. Set
+retention.pruneonsave to prune datasets each time they're saved.
+
+Pruning is recorded in the dataset's log, so peers see versions removed when
+they next pull. Only the dataset author can prune versions.`,
+		Example: `  # Preview which versions of a dataset would be pruned:
+  $ qri prune me/hourly_readings --dry-run
+
+  # Keep the last 10 versions, plus one version a month for a year:
+  $ qri prune me/hourly_readings --keep-last 10 --keep-monthly 12
+
+  # Prune all of your datasets with configured policies:
+  $ qri prune --all`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.All, "all", false, "prune all of your datasets")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "list versions that would be pruned without removing them")
+	cmd.Flags().IntVar(&o.Policy.KeepLast, "keep-last", 0, "number of most recent versions to keep")
+	cmd.Flags().StringVar(&o.Policy.KeepWithin, "keep-within", "", "keep versions committed within a duration, like 72h or 30d")
+	cmd.Flags().BoolVar(&o.Policy.KeepTagged, "keep-tagged", false, "keep tagged versions")
+	cmd.Flags().IntVar(&o.Policy.KeepDaily, "keep-daily", 0, "number of days to keep the latest version of")
+	cmd.Flags().IntVar(&o.Policy.KeepWeekly, "keep-weekly", 0, "number of weeks to keep the latest version of")
+	cmd.Flags().IntVar(&o.Policy.KeepMonthly, "keep-monthly", 0, "number of months to keep the latest version of")
+
+	return cmd
+}
+
+// PruneOptions encapsulates state for the prune command
+type PruneOptions struct {
+	ioes.IOStreams
+
+	Refs   *RefSelect
+	All    bool
+	DryRun bool
+	Policy config.RetentionPolicy
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *PruneOptions) Complete(f Factory, args []string) (err error) {
+	if !o.All {
+		if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+			if err == repo.ErrEmptyRef {
+				return errors.New(err, "please provide a dataset reference, or --all")
+			}
+			return err
+		}
+	} else if len(args) > 0 {
+		return fmt.Errorf("can't prune a dataset reference and --all")
+	}
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the prune command
+func (o *PruneOptions) Run() error {
+	p := &lib.PruneParams{
+		All:    o.All,
+		DryRun: o.DryRun,
+	}
+	if o.Refs != nil {
+		printRefSelect(o.ErrOut, o.Refs)
+		p.Ref = o.Refs.Ref()
+	}
+	if !o.Policy.KeepsAll() {
+		p.Policy = &o.Policy
+	}
+
+	res := []lib.PruneResult{}
+	if err := o.DatasetMethods.Prune(p, &res); err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		printInfo(o.Out, "no versions to prune")
+		return nil
+	}
+
+	for _, r := range res {
+		if o.DryRun {
+			printInfo(o.Out, "would prune %d versions of %s:", len(r.Pruned), r.Ref)
+		} else {
+			printSuccess(o.Out, "pruned %d versions of %s:", len(r.Pruned), r.Ref)
+		}
+		for _, item := range r.Pruned {
+			fmt.Fprintf(o.Out, "  %s\t%s\t%s\n", shortPath(item.Path), item.CommitTime.In(StringerLocation).Format("Jan _2 2006 15:04"), item.CommitTitle)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPruneCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_prune", "qri_test_prune")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml --title=one me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --title=two me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_thirty.csv --title=three me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv --title=four me/test_movies")
+	r.MustExec(t, "qri tag me/test_movies~3 v1")
+
+	if err := r.ExecCommand("qri prune me/test_movies"); err == nil {
+		t.Error("expected pruning without a retention policy to error")
+	}
+
+	output := r.MustExec(t, "qri prune me/test_movies --keep-last 1 --keep-tagged --dry-run")
+	if !strings.Contains(output, "would prune 2 versions") {
+		t.Errorf("expected dry run to list 2 versions, got:\n%s", output)
+	}
+	if output := r.MustExec(t, "qri log me/test_movies"); strings.Count(output, "Commit:") != 4 {
+		t.Errorf("expected dry run to leave history intact, got:\n%s", output)
+	}
+
+	r.MustExec(t, "qri prune me/test_movies --keep-last 1 --keep-tagged")
+	output = r.MustExec(t, "qri log me/test_movies")
+	if strings.Count(output, "Commit:") != 2 {
+		t.Errorf("expected 2 versions after pruning, got:\n%s", output)
+	}
+	for _, title := range []string{"one", "four"} {
+		if !strings.Contains(output, title) {
+			t.Errorf("expected log to contain commit %q, got:\n%s", title, output)
+		}
+	}
+
+	output = r.MustExec(t, "qri logbook me/test_movies")
+	if !strings.Contains(output, "prune versions") {
+		t.Errorf("expected logbook to record the prune, got:\n%s", output)
+	}
+
+	output = r.MustExec(t, "qri prune --all --keep-last 1")
+	if !strings.Contains(output, "pruned 1 versions of test_peer_prune/test_movies") {
+		t.Errorf("expected --all to prune the tagged version, got:\n%s", output)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/remote"
 	reporef "github.com/qri-io/qri/repo/ref"
 	"github.com/spf13/cobra"
 )
@@ -19,12 +20,24 @@ func NewPullCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 		Short:   "fetch & store datasets from other peers",
 		Long: `Pull downloads datasets and stores them locally, fetching the dataset log and
 dataset version(s). By default pull fetches the latest version of a dataset.
+
+A sparse pull fetches a partial version, skipping the data of large components
+like the body. Partial versions can be listed & inspected, and are completed
+from the remote when a command needs the data they're missing. Pass --sparse to
+fetch only meta, structure, readme and stats, or list components to fetch with
+--components. Sparse pulls require an HTTP remote.
 `,
 		Example: `  # download a dataset log and latest version
   $ qri pull b5/world_bank_population
 
   # pull a specific version from a remote by hash
-  $ qri pull ramfox b5/world_bank_population@/ipfs/QmFoo...`,
+  $ qri pull ramfox b5/world_bank_population@/ipfs/QmFoo...
+
+  # fetch metadata of the latest version, skipping the body
+  $ qri pull --sparse b5/world_bank_population
+
+  # fetch the latest version without the transform & viz
+  $ qri pull --components meta,structure,body,readme,stats b5/world_bank_population`,
 		Annotations: map[string]string{
 			"group": "network",
 		},
@@ -40,6 +53,8 @@ dataset version(s). By default pull fetches the latest version of a dataset.
 	cmd.Flags().StringVar(&o.Remote, "remote", "", "location to pull from")
 	cmd.MarkFlagFilename("link")
 	cmd.Flags().BoolVar(&o.LogsOnly, "logs-only", false, "only fetch logs, skipping HEAD data")
+	cmd.Flags().BoolVar(&o.Sparse, "sparse", false, "only fetch meta, structure, readme & stats components")
+	cmd.Flags().StringSliceVar(&o.Components, "components", nil, "only fetch the listed components")
 
 	return cmd
 }
@@ -50,6 +65,8 @@ type PullOptions struct {
 	LinkDir        string
 	Remote         string
 	LogsOnly       bool
+	Sparse         bool
+	Components     []string
 	DatasetMethods *lib.DatasetMethods
 }
 
@@ -70,13 +87,21 @@ func (o *PullOptions) Run(args []string) error {
 	if len(args) > 1 && o.LinkDir != "" {
 		return fmt.Errorf("link flag can only be used with a single reference")
 	}
+	if o.Sparse && len(o.Components) > 0 {
+		return fmt.Errorf("sparse and components flags can't be used together")
+	}
+	components := o.Components
+	if o.Sparse {
+		components = remote.SparseComponents
+	}
 
 	for _, arg := range args {
 		p := &lib.PullParams{
-			Ref:      arg,
-			LinkDir:  o.LinkDir,
-			LogsOnly: o.LogsOnly,
-			Remote:   o.Remote,
+			Ref:        arg,
+			LinkDir:    o.LinkDir,
+			LogsOnly:   o.LogsOnly,
+			Remote:     o.Remote,
+			Components: components,
 		}
 
 		res := &dataset.Dataset{}
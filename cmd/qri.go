@@ -44,6 +44,9 @@ https://github.com/qri-io/qri/issues`,
 
 	cmd.AddCommand(
 		NewAutocompleteCommand(opt, ioStreams),
+		NewBisectCommand(opt, ioStreams),
+		NewBlameCommand(opt, ioStreams),
+		NewChangelogCommand(opt, ioStreams),
 		NewCheckoutCommand(opt, ioStreams),
 		NewConfigCommand(opt, ioStreams),
 		NewConnectCommand(opt, ioStreams),
@@ -52,24 +55,32 @@ https://github.com/qri-io/qri/issues`,
 		NewFSICommand(opt, ioStreams),
 		NewGetCommand(opt, ioStreams),
 		NewInitCommand(opt, ioStreams),
+		NewLineageCommand(opt, ioStreams),
 		NewListCommand(opt, ioStreams),
 		NewLogCommand(opt, ioStreams),
 		NewLogbookCommand(opt, ioStreams),
+		NewPruneCommand(opt, ioStreams),
 		NewPushCommand(opt, ioStreams),
 		NewPullCommand(opt, ioStreams),
 		NewPeersCommand(opt, ioStreams),
 		NewPreviewCommand(opt, ioStreams),
+		NewRebaseCommand(opt, ioStreams),
 		NewRegistryCommand(opt, ioStreams),
 		NewRemoveCommand(opt, ioStreams),
 		NewRenameCommand(opt, ioStreams),
 		NewRenderCommand(opt, ioStreams),
 		NewRestoreCommand(opt, ioStreams),
+		NewRevertCommand(opt, ioStreams),
 		NewSaveCommand(opt, ioStreams),
 		NewSearchCommand(opt, ioStreams),
 		NewSetupCommand(opt, ioStreams),
+		NewStashCommand(opt, ioStreams),
 		NewStatsCommand(opt, ioStreams),
 		NewStatusCommand(opt, ioStreams),
 		NewSQLCommand(opt, ioStreams),
+		NewSquashCommand(opt, ioStreams),
+		NewTagCommand(opt, ioStreams),
+		NewTransformCommand(opt, ioStreams),
 		NewUseCommand(opt, ioStreams),
 		NewValidateCommand(opt, ioStreams),
 		NewVersionCommand(opt, ioStreams),
@@ -313,6 +324,14 @@ func (o *QriOptions) FSIMethods() (m *lib.FSIMethods, err error) {
 	return lib.NewFSIMethods(o.inst), nil
 }
 
+// TransformMethods generates a lib.TransformMethods from internal state
+func (o *QriOptions) TransformMethods() (*lib.TransformMethods, error) {
+	if err := o.Init(); err != nil {
+		return nil, err
+	}
+	return lib.NewTransformMethods(o.inst), nil
+}
+
 // Shutdown closes the instance
 func (o *QriOptions) Shutdown() <-chan error {
 	if o.inst == nil {
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewRebaseCommand creates a new `qri rebase` cobra command
+func NewRebaseCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &RebaseOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "rebase DATASET --onto VERSION --from VERSION",
+		Short: "re-parent versions onto an older version",
+		Long: `Rebase re-parents a run of dataset versions onto an older version, dropping
+the versions in between. Every version from --from through the latest
+version is rewritten on top of --onto, keeping its commit, which gives it a
+new version path.
+
+Versions are selected as version paths, references, or revisions like ~2.
+
+Rebasing rewrites history. Like squash, the rewrite is recorded in the
+dataset's log, so peers that already pulled the dropped versions see the
+rewrite the next time they pull. Only the dataset author can rebase versions.`,
+		Example: `  # Drop the two versions before the latest version:
+  $ qri rebase me/hourly_readings --onto ~3 --from ~0
+
+  # Drop a run of bad versions from the middle of history:
+  $ qri rebase me/hourly_readings --onto ~20 --from ~10`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Onto, "onto", "", "version to re-parent onto")
+	cmd.Flags().StringVar(&o.From, "from", "", "oldest version to re-parent")
+	cmd.MarkFlagRequired("onto")
+	cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+// RebaseOptions encapsulates state for the rebase command
+type RebaseOptions struct {
+	ioes.IOStreams
+
+	Refs *RefSelect
+	Onto string
+	From string
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *RebaseOptions) Complete(f Factory, args []string) (err error) {
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the rebase command
+func (o *RebaseOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.RebaseParams{
+		Ref:  o.Refs.Ref(),
+		Onto: o.Onto,
+		From: o.From,
+	}
+	res := &dataset.Dataset{}
+	if err := o.DatasetMethods.Rebase(p, res); err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "rebased %s/%s, latest version %s", res.Peername, res.Name, res.Path)
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewRevertCommand creates a new `qri revert` cobra command
+func NewRevertCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &RevertOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "revert DATASET",
+		Short: "create a new version restoring an earlier version",
+		Long: `Revert saves a new version of a dataset that restores an earlier version.
+The reference must select the version to restore, using a revision like
+` + "`me/dataset~1`" + ` or a version path. History isn't rewritten, so anyone who
+has already pulled the dataset can keep up by pulling the revert.
+
+By default every component is restored. Use --component to restore only some
+of them, keeping the rest of the latest version. Restoring the body also
+restores the structure that describes it. Dataset history records which
+version was restored, ` + "`qri changelog`" + ` lists it alongside the revert.`,
+		Example: `  # Undo the latest save to me/annual_pop:
+  $ qri revert me/annual_pop~1
+
+  # Restore the body & meta of the version tagged v1.0:
+  $ qri revert me/annual_pop@v1.0 --component body,meta`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.Components, "component", nil, "components to restore, one or more of: meta, structure, body, readme, transform, viz")
+	cmd.Flags().StringVarP(&o.Title, "title", "t", "", "title of the revert commit")
+	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "commit message for the revert")
+
+	return cmd
+}
+
+// RevertOptions encapsulates state for the revert command
+type RevertOptions struct {
+	ioes.IOStreams
+
+	Ref        string
+	Components []string
+	Title      string
+	Message    string
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *RevertOptions) Complete(f Factory, args []string) (err error) {
+	if len(args) == 0 || args[0] == "" {
+		return errors.New(lib.ErrBadArgs, "please provide the version to restore, for example:\n    $ qri revert me/dataset~1")
+	}
+	o.Ref = args[0]
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the revert command
+func (o *RevertOptions) Run() error {
+	p := &lib.RevertParams{
+		Ref:        o.Ref,
+		Components: o.Components,
+		Title:      o.Title,
+		Message:    o.Message,
+	}
+	res := &dataset.Dataset{}
+	if err := o.DatasetMethods.Revert(p, res); err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "reverted %s/%s, new version %s", res.Peername, res.Name, res.Path)
+	return nil
+}
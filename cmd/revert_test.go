@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRevertCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_revert", "qri_test_revert")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml me/test_movies")
+	tenEntries := r.MustExec(t, "qri get structure.entries me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --file=testdata/movies/meta_override.yaml me/test_movies")
+	twentyEntries := r.MustExec(t, "qri get structure.entries me/test_movies")
+
+	if err := r.ExecCommand("qri revert me/test_movies"); err == nil {
+		t.Error("expected reverting without selecting a version to error")
+	}
+	if err := r.ExecCommand("qri revert me/test_movies~1 --component stats"); err == nil {
+		t.Error("expected reverting an unknown component to error")
+	}
+
+	// restore only the meta, keeping the twenty row body
+	r.MustExec(t, "qri revert me/test_movies~1 --component meta")
+	output := r.MustExec(t, "qri get meta.title me/test_movies")
+	if !strings.Contains(output, "example movie data") {
+		t.Errorf("expected meta title to be reverted, got: %q", output)
+	}
+	output = r.MustExec(t, "qri get structure.entries me/test_movies")
+	if output != twentyEntries {
+		t.Errorf("expected body to keep %q entries, got: %q", twentyEntries, output)
+	}
+
+	// restore everything from the first version
+	r.MustExec(t, "qri revert me/test_movies~2")
+	output = r.MustExec(t, "qri get structure.entries me/test_movies")
+	if output != tenEntries {
+		t.Errorf("expected body to be reverted to %q entries, got: %q", tenEntries, output)
+	}
+
+	output = r.MustExec(t, "qri changelog me/test_movies")
+	if !strings.Contains(output, "reverted to `/ipfs/") {
+		t.Errorf("expected changelog to list the reverted version, got:\n%s", output)
+	}
+
+	output = r.MustExec(t, "qri log me/test_movies")
+	if strings.Count(output, "Commit:") != 4 {
+		t.Errorf("expected reverts to add versions to history, got:\n%s", output)
+	}
+}
@@ -27,6 +27,13 @@ your dataset’s log (which you can see by running ` + "`qri log <dataset_refere
 
 If the dataset you're changing has defined a transform, running ` + "`qri save`" + `
 will re execute the transform. To only re-run the transform, run save with no args.
+Transforms are either starlark scripts (.star files) or SQL queries (.sql files).
+The result of an SQL query becomes the body of the saved version.
+
+Use ` + "`--append`" + ` to add rows to the end of a csv body instead of replacing it.
+Only the appended rows are validated, stats for the new rows are merged into
+the previous version's stats, and the commit message defaults to the number
+of rows appended.
 
 Every time you save, you can provide a message about what you changed and why. 
 If you don’t provide a message Qri will automatically generate one for you.
@@ -35,15 +42,32 @@ When you make an update and save a dataset that you originally added from a diff
 peer, the dataset gets renamed from ` + "`peers_name/dataset_name`" + ` to ` + "`my_name/dataset_name`" + `.
 
 The ` + "`--message`" + `" and ` + "`--title`" + ` flags allow you to add a 
-commit message and title to the save.`,
+commit message and title to the save.
+
+Datasets linked to a working directory can define hooks in the ` + "`.qri/hooks`" + `
+directory. A ` + "`pre-save`" + ` hook runs before saving & can reject the save by
+failing, a ` + "`post-save`" + ` hook runs after a new version is saved. Hooks are
+executables that read the dataset as JSON from stdin, or starlark scripts with
+a ` + "`.star`" + ` extension that define a function named for the hook
+(` + "`def pre_save(ds):`" + `) & call ` + "`fail(message)`" + ` to reject a save. Use
+` + "`--no-verify`" + ` to skip hooks.`,
 		Example: `  # Save updated data to dataset annual_pop:
   $ qri save --body /path/to/data.csv me/annual_pop
 
+  # Append rows to the body of annual_pop:
+  $ qri save --append /path/to/rows.csv me/annual_pop
+
   # Save updated dataset (no data) to annual_pop:
   $ qri save --file /path/to/dataset.yaml me/annual_pop
   
+  # Save the result of an SQL query as the body of me/long_movies:
+  $ qri save --file /path/to/query.sql me/long_movies
+
   # Re-execute a dataset that has a transform:
-  $ qri save me/tf_dataset`,
+  $ qri save me/tf_dataset
+
+  # Save a working directory without running hooks:
+  $ qri save --no-verify`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -65,6 +89,8 @@ commit message and title to the save.`,
 	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "commit message for save")
 	cmd.Flags().StringVarP(&o.BodyPath, "body", "", "", "path to file or url of data to add as dataset contents")
 	cmd.MarkFlagFilename("body")
+	cmd.Flags().StringVar(&o.AppendPath, "append", "", "path to file of rows to append to the dataset body")
+	cmd.MarkFlagFilename("append")
 	cmd.Flags().StringVarP(&o.Recall, "recall", "", "", "restore revisions from dataset history")
 	// cmd.Flags().BoolVarP(&o.ShowValidation, "show-validation", "s", false, "display a list of validation errors upon adding")
 	cmd.Flags().StringSliceVar(&o.Secrets, "secrets", nil, "transform secrets as comma separated key,value,key,value,... sequence")
@@ -76,6 +102,8 @@ commit message and title to the save.`,
 	cmd.Flags().BoolVarP(&o.NewName, "new", "n", false, "save a new dataset only, using an available name")
 	cmd.Flags().BoolVarP(&o.UseDscache, "use-dscache", "", false, "experimental: build and use dscache if none exists")
 	cmd.Flags().StringVar(&o.Drop, "drop", "", "comma-separated list of components to remove")
+	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", false, "don't run working directory save hooks")
+	cmd.Flags().BoolVar(&o.Changelog, "changelog", false, "write a changelog of dataset history into the readme")
 
 	return cmd
 }
@@ -84,11 +112,12 @@ commit message and title to the save.`,
 type SaveOptions struct {
 	ioes.IOStreams
 
-	Refs      *RefSelect
-	FilePaths []string
-	BodyPath  string
-	Recall    string
-	Drop      string
+	Refs       *RefSelect
+	FilePaths  []string
+	BodyPath   string
+	AppendPath string
+	Recall     string
+	Drop       string
 
 	Title   string
 	Message string
@@ -102,6 +131,8 @@ type SaveOptions struct {
 	Secrets        []string
 	NewName        bool
 	UseDscache     bool
+	NoVerify       bool
+	Changelog      bool
 
 	DatasetMethods *lib.DatasetMethods
 	FSIMethods     *lib.FSIMethods
@@ -132,12 +163,18 @@ func (o *SaveOptions) Complete(f Factory, args []string) (err error) {
 	if err := qfs.AbsPath(&o.BodyPath); err != nil {
 		return fmt.Errorf("body file: %s", err)
 	}
+	if err := qfs.AbsPath(&o.AppendPath); err != nil {
+		return fmt.Errorf("append file: %s", err)
+	}
 
 	return nil
 }
 
 // Validate checks that all user input is valid
 func (o *SaveOptions) Validate() error {
+	if o.AppendPath != "" && o.BodyPath != "" {
+		return fmt.Errorf("--body and --append can't be used together")
+	}
 	return nil
 }
 
@@ -166,6 +203,12 @@ func (o *SaveOptions) Run() (err error) {
 		ShouldRender:        !o.NoRender,
 		NewName:             o.NewName,
 		UseDscache:          o.UseDscache,
+		NoVerify:            o.NoVerify,
+		Changelog:           o.Changelog,
+	}
+	if o.AppendPath != "" {
+		p.BodyPath = o.AppendPath
+		p.Append = true
 	}
 
 	if o.Secrets != nil {
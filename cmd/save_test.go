@@ -496,6 +496,46 @@ func TestSaveFilenameUsedForCommitMessage(t *testing.T) {
 	}
 }
 
+func TestSaveAppend(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_append", "qri_test_save_append")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "save_append")
+	rowsPath := filepath.Join(tmpDir, "rows.csv")
+	run.MustWriteFile(t, rowsPath, "movie_title,duration\nAliens,137\nHeat,170\n")
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/append_ds")
+	run.MustExec(t, fmt.Sprintf("qri save --append %s me/append_ds", rowsPath))
+
+	output := run.MustExec(t, "qri get commit.title me/append_ds")
+	if diff := cmp.Diff("appended 2 rows\n\n", output); diff != "" {
+		t.Errorf("commit title mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri get structure.entries me/append_ds")
+	if diff := cmp.Diff("10\n\n", output); diff != "" {
+		t.Errorf("entries mismatch (-want +got):\n%s", diff)
+	}
+
+	ds := run.MustLoadDataset(t, run.GetPathForDataset(t, 0))
+	stats, ok := ds.Stats.Stats.([]interface{})
+	if !ok || len(stats) != 2 {
+		t.Fatalf("expected stats for two columns, got: %#v", ds.Stats.Stats)
+	}
+	if count := stats[1].(map[string]interface{})["count"]; count != float64(9) {
+		t.Errorf("expected merged duration count of 9, got: %v", count)
+	}
+
+	body := run.ReadBodyFromIPFS(t, ds.Path+"/body.csv")
+	if !strings.HasSuffix(body, "Tangled ,100\nAliens,137\nHeat,170\n") {
+		t.Errorf("expected appended rows at the end of the body, got:\n%s", body)
+	}
+
+	if err := run.ExecCommand("qri save --body testdata/movies/body_ten.csv --append " + rowsPath + " me/append_ds"); err == nil {
+		t.Error("expected using --body and --append together to fail")
+	}
+}
+
 func TestSaveDrop(t *testing.T) {
 	run := NewTestRunner(t, "test_peer_save_drop", "qri_test_save_drop")
 	defer run.Delete()
@@ -1177,3 +1217,35 @@ func errorMessage(err error) string {
 	}
 	return err.Error()
 }
+
+func TestSaveSQLTransform(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_sql_transform", "qri_test_save_sql_transform")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv test_peer_save_sql_transform/movies")
+
+	// Save a new dataset whose body is the result of a query
+	run.MustExec(t, "qri save --file testdata/movies/tf_first_movies.sql test_peer_save_sql_transform/first_movies")
+
+	dsPath := run.GetPathForDataset(t, 0)
+	actualBody := run.ReadBodyFromIPFS(t, dsPath+"/body.csv")
+	expectBody := "title,duration\nAvatar ,178\nPirates of the Caribbean: At World's End ,169\nSpectre ,148\n"
+	if diff := cmp.Diff(expectBody, actualBody); diff != "" {
+		t.Errorf("result mismatch (-want +got):%s\n", diff)
+	}
+
+	// The dataset the query read is recorded for reproducibility
+	output := run.MustExec(t, "qri get transform test_peer_save_sql_transform/first_movies")
+	for _, expect := range []string{"syntax: sql", "test_peer_save_sql_transform/movies@/ipfs/"} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected transform to contain %q, got:\n%s", expect, output)
+		}
+	}
+
+	// A query that matches nothing saves an empty body
+	run.MustExec(t, "qri save --file testdata/movies/tf_no_movies.sql test_peer_save_sql_transform/no_movies")
+	output = run.MustExec(t, "qri get body test_peer_save_sql_transform/no_movies")
+	if strings.Contains(output, "Avatar") {
+		t.Errorf("expected empty body, got:\n%s", output)
+	}
+}
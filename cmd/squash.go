@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewSquashCommand creates a new `qri squash` cobra command
+func NewSquashCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &SquashOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "squash DATASET --from VERSION",
+		Short: "collapse a range of versions into one",
+		Long: `Squash collapses a range of dataset versions into a single version, keeping
+the components & commit time of the newest version in the range. Versions
+after the range are re-parented onto the squashed version, keeping their
+commits, which gives them new version paths.
+
+Versions are selected with --from & --to, as version paths, references, or
+revisions like ~2. --to defaults to the latest version.
+
+Squashing rewrites history. The rewrite is recorded in the dataset's log,
+so peers that already pulled the replaced versions see the rewrite the next
+time they pull, instead of a history that silently disagrees with theirs.
+Only the dataset author can squash versions.`,
+		Example: `  # Collapse all but the first of the last 25 versions into one:
+  $ qri squash me/hourly_readings --from ~24
+
+  # Squash a range of versions in the middle of history:
+  $ qri squash me/hourly_readings --from ~30 --to ~10 -t "march readings"`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.From, "from", "", "oldest version to squash")
+	cmd.Flags().StringVar(&o.To, "to", "", "newest version to squash, default the latest version")
+	cmd.Flags().StringVarP(&o.Title, "title", "t", "", "title of the squashed commit")
+	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "commit message for the squashed commit")
+	cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+// SquashOptions encapsulates state for the squash command
+type SquashOptions struct {
+	ioes.IOStreams
+
+	Refs    *RefSelect
+	From    string
+	To      string
+	Title   string
+	Message string
+
+	DatasetMethods *lib.DatasetMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *SquashOptions) Complete(f Factory, args []string) (err error) {
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+	o.DatasetMethods, err = f.DatasetMethods()
+	return
+}
+
+// Run executes the squash command
+func (o *SquashOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.SquashParams{
+		Ref:     o.Refs.Ref(),
+		From:    o.From,
+		To:      o.To,
+		Title:   o.Title,
+		Message: o.Message,
+	}
+	res := &dataset.Dataset{}
+	if err := o.DatasetMethods.Squash(p, res); err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "squashed %s/%s, latest version %s", res.Peername, res.Name, res.Path)
+	return nil
+}
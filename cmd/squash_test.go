@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSquashCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_squash", "qri_test_squash")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml --title=one me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --title=two me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_thirty.csv --title=three me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv --title=four me/test_movies")
+	entries := r.MustExec(t, "qri get structure.entries me/test_movies")
+
+	if err := r.ExecCommand("qri squash me/test_movies --from ~1 --to ~2"); err == nil {
+		t.Error("expected --from after --to to error")
+	}
+
+	r.MustExec(t, "qri squash me/test_movies --from ~2 --to ~1 -t middle")
+
+	output := r.MustExec(t, "qri log me/test_movies")
+	if strings.Count(output, "Commit:") != 3 {
+		t.Errorf("expected 3 versions after squashing, got:\n%s", output)
+	}
+	for _, title := range []string{"one", "middle", "four"} {
+		if !strings.Contains(output, title) {
+			t.Errorf("expected log to contain commit %q, got:\n%s", title, output)
+		}
+	}
+	if output := r.MustExec(t, "qri get structure.entries me/test_movies"); output != entries {
+		t.Errorf("expected latest version to keep %q entries, got: %q", entries, output)
+	}
+
+	output = r.MustExec(t, "qri logbook me/test_movies")
+	if !strings.Contains(output, "rewrite history") {
+		t.Errorf("expected logbook to record the rewrite, got:\n%s", output)
+	}
+}
+
+func TestRebaseCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_rebase", "qri_test_rebase")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml --title=one me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv --title=two me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_thirty.csv --title=three me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv --title=four me/test_movies")
+	entries := r.MustExec(t, "qri get structure.entries me/test_movies")
+
+	if err := r.ExecCommand("qri rebase me/test_movies --onto ~1 --from ~2"); err == nil {
+		t.Error("expected --onto after --from to error")
+	}
+
+	r.MustExec(t, "qri rebase me/test_movies --onto ~3 --from ~0")
+
+	output := r.MustExec(t, "qri log me/test_movies")
+	if strings.Count(output, "Commit:") != 2 {
+		t.Errorf("expected 2 versions after rebasing, got:\n%s", output)
+	}
+	for _, title := range []string{"two", "three"} {
+		if strings.Contains(output, title) {
+			t.Errorf("expected log to drop commit %q, got:\n%s", title, output)
+		}
+	}
+	if output := r.MustExec(t, "qri get structure.entries me/test_movies"); output != entries {
+		t.Errorf("expected latest version to keep %q entries, got: %q", entries, output)
+	}
+
+	output = r.MustExec(t, "qri logbook me/test_movies")
+	if !strings.Contains(output, "rebase") {
+		t.Errorf("expected logbook to record the rebase, got:\n%s", output)
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewStashCommand creates a new `qri stash` command for setting aside changes
+// in a working directory
+func NewStashCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &StashOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "stash",
+		Short: "set aside changes in a working directory",
+		Long: `Stash saves changes to a working directory without creating a new version,
+restoring changed files to the version the directory is linked to. Stashed
+changes can be re-applied later with ` + "`qri stash pop`" + `.
+
+Popping a stash fails if stashed components have been changed in the working
+directory, or saved in a new version since stashing. Use ` + "`--force`" + ` to
+apply the stash anyway, overwriting those changes.
+
+Stashes are numbered from most recent, starting at 0. Commands that operate on
+a single stash use the most recent stash unless a number is given.`,
+		Example: `  # Set aside changes in the current directory:
+  $ qri stash push -m "halfway through cleaning up the body"
+
+  # List stashed changes:
+  $ qri stash list
+
+  # Re-apply the most recent stash:
+  $ qri stash pop
+
+  # Discard the second most recent stash:
+  $ qri stash drop 1`,
+		Annotations: map[string]string{
+			"group": "workdir",
+		},
+	}
+
+	push := &cobra.Command{
+		Use:   "push",
+		Short: "stash changes in the working directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Push()
+		},
+	}
+	push.Flags().StringVarP(&o.Message, "message", "m", "", "description of the stashed changes")
+
+	list := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "list stashed changes",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.List()
+		},
+	}
+
+	pop := &cobra.Command{
+		Use:   "pop [STASH]",
+		Short: "apply & remove stashed changes",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Pop()
+		},
+	}
+	pop.Flags().BoolVar(&o.Force, "force", false, "apply the stash even if it conflicts with changes")
+
+	drop := &cobra.Command{
+		Use:   "drop [STASH]",
+		Short: "remove stashed changes without applying them",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Drop()
+		},
+	}
+
+	cmd.AddCommand(push, list, pop, drop)
+	return cmd
+}
+
+// StashOptions encapsulates state for the stash command
+type StashOptions struct {
+	ioes.IOStreams
+
+	Dir     string
+	Index   int
+	Message string
+	Force   bool
+
+	FSIMethods *lib.FSIMethods
+}
+
+// Complete adds any missing configuration that can only be added just before
+// calling Run
+func (o *StashOptions) Complete(f Factory, args []string) (err error) {
+	o.Dir = "."
+	if len(args) > 0 {
+		if o.Index, err = strconv.Atoi(args[0]); err != nil || o.Index < 0 {
+			return fmt.Errorf("invalid stash %q, stashes are numbered from 0", args[0])
+		}
+	}
+	o.FSIMethods, err = f.FSIMethods()
+	return err
+}
+
+func (o *StashOptions) params() *lib.StashParams {
+	return &lib.StashParams{
+		Dir:     o.Dir,
+		Message: o.Message,
+		Index:   o.Index,
+		Force:   o.Force,
+	}
+}
+
+// Push executes the stash push command
+func (o *StashOptions) Push() error {
+	res := &lib.Stash{}
+	if err := o.FSIMethods.StashPush(o.params(), res); err != nil {
+		return err
+	}
+	printSuccess(o.Out, "stashed changes to %s", strings.Join(res.Components(), ", "))
+	return nil
+}
+
+// List executes the stash list command
+func (o *StashOptions) List() error {
+	res := []lib.Stash{}
+	if err := o.FSIMethods.StashList(o.params(), &res); err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		printInfo(o.Out, "no stashes")
+		return nil
+	}
+	for i, s := range res {
+		fmt.Fprintf(o.Out, "%d: %s\n", i, describeStash(&s))
+	}
+	return nil
+}
+
+// Pop executes the stash pop command
+func (o *StashOptions) Pop() error {
+	res := &lib.Stash{}
+	if err := o.FSIMethods.StashPop(o.params(), res); err != nil {
+		return err
+	}
+	printSuccess(o.Out, "applied stash %d: %s", o.Index, describeStash(res))
+	return nil
+}
+
+// Drop executes the stash drop command
+func (o *StashOptions) Drop() error {
+	res := &lib.Stash{}
+	if err := o.FSIMethods.StashDrop(o.params(), res); err != nil {
+		return err
+	}
+	printSuccess(o.Out, "dropped stash %d: %s", o.Index, describeStash(res))
+	return nil
+}
+
+func describeStash(s *lib.Stash) string {
+	changes := fmt.Sprintf("changes to %s", strings.Join(s.Components(), ", "))
+	if s.Message != "" {
+		return fmt.Sprintf("%s (%s)", s.Message, changes)
+	}
+	return changes
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStashCommands(t *testing.T) {
+	run := NewFSITestRunner(t, "test_peer_stash", "qri_test_stash")
+	defer run.Delete()
+
+	workDir := run.CreateAndChdirToWorkDir("stash_me")
+	run.MustExec(t, "qri init --name stash_me --format csv")
+	run.MustExec(t, "qri save")
+
+	run.MustWriteFile(t, filepath.Join(workDir, "readme.md"), "# stash me\n")
+
+	output := run.MustExec(t, "qri stash push -m readme_draft")
+	expect := "stashed changes to readme\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("stash push output (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri stash list")
+	expect = "0: readme_draft (changes to readme)\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("stash list output (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri status")
+	expect = "working directory clean\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("status after stash (-want +got):\n%s", diff)
+	}
+
+	if err := run.ExecCommand("qri stash pop 1"); err == nil {
+		t.Error("expected popping a missing stash to error")
+	}
+
+	output = run.MustExec(t, "qri stash pop")
+	expect = "applied stash 0: readme_draft (changes to readme)\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("stash pop output (-want +got):\n%s", diff)
+	}
+	if got := run.MustReadFile(t, filepath.Join(workDir, "readme.md")); got != "# stash me\n" {
+		t.Errorf("expected readme to be restored, got: %q", got)
+	}
+
+	output = run.MustExec(t, "qri stash list")
+	expect = "no stashes\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("stash list output (-want +got):\n%s", diff)
+	}
+}
@@ -242,9 +242,11 @@ func (s dslogItemStringer) String() string {
 	storage := "local"
 	if s.Foreign {
 		storage = faint("remote")
+	} else if s.Sparse {
+		storage = faint("partial")
 	}
 
-	msg := fmt.Sprintf("%s%s\n%s%s\n%s%s\n%s%s\n\n%s\n",
+	msg := fmt.Sprintf("%s%s\n%s%s\n%s%s\n%s%s\n",
 		faint("Commit:  "),
 		yellow(s.Path),
 		faint("Date:    "),
@@ -253,8 +255,11 @@ func (s dslogItemStringer) String() string {
 		storage,
 		faint("Size:    "),
 		humanize.Bytes(uint64(s.BodySize)),
-		s.CommitTitle,
 	)
+	if len(s.Tags) > 0 {
+		msg += fmt.Sprintf("%s%s\n", faint("Tags:    "), strings.Join(s.Tags, ", "))
+	}
+	msg += fmt.Sprintf("\n%s\n", s.CommitTitle)
 	if s.CommitMessage != "" && s.CommitMessage != s.CommitTitle {
 		msg += fmt.Sprintf("%s\n", s.CommitMessage)
 	}
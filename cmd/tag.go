@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewTagCommand creates a new `qri tag` cobra command
+func NewTagCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &TagOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "tag DATASET NAME",
+		Short: "name a dataset version",
+		Long: `Tag gives a dataset version a stable, human-readable name like "v1.0" or
+"2020-q3-release". If the dataset reference doesn't select a version the
+latest version is tagged. Tagged versions can be referred to by name
+anywhere qri accepts a reference, like ` + "`me/dataset@v1.0`" + `.
+
+Tags are recorded in the dataset's log, and are shared when the dataset is
+pushed or pulled. Only the dataset author can tag versions. A tag name can
+only name one version at a time, moving an existing tag to a different
+version requires ` + "`--force`" + `.`,
+		Example: `  # Tag the latest version of me/annual_pop:
+  $ qri tag me/annual_pop v1.0
+
+  # Tag the version before the latest:
+  $ qri tag me/annual_pop~1 v0.9
+
+  # Move an existing tag to the latest version:
+  $ qri tag me/annual_pop v1.0 --force
+
+  # List the tags of a dataset:
+  $ qri tag list me/annual_pop`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args[:1]); err != nil {
+				return err
+			}
+			o.Name = args[1]
+			return o.Run()
+		},
+	}
+	cmd.Flags().BoolVar(&o.Force, "force", false, "move the tag if it already names another version")
+
+	list := &cobra.Command{
+		Use:     "list [DATASET]",
+		Aliases: []string{"ls"},
+		Short:   "list the tags of a dataset",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.List()
+		},
+	}
+	list.Flags().StringVar(&o.Format, "format", "text", "output format. One of: [text|json]")
+
+	cmd.AddCommand(list)
+	return cmd
+}
+
+// TagOptions encapsulates state for the tag command
+type TagOptions struct {
+	ioes.IOStreams
+
+	Refs   *RefSelect
+	Name   string
+	Force  bool
+	Format string
+
+	LogMethods *lib.LogMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *TagOptions) Complete(f Factory, args []string) (err error) {
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1, nil); err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+	o.LogMethods, err = f.LogMethods()
+	return
+}
+
+// Run executes the tag command
+func (o *TagOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.TagParams{
+		Ref:   o.Refs.Ref(),
+		Name:  o.Name,
+		Force: o.Force,
+	}
+	res := &lib.VersionTag{}
+	if err := o.LogMethods.Tag(p, res); err != nil {
+		return err
+	}
+	printSuccess(o.Out, "tagged %s as %s", res.Path, res.Name)
+	return nil
+}
+
+// List prints the tags of a dataset
+func (o *TagOptions) List() error {
+	if o.Format != "text" && o.Format != "json" {
+		return fmt.Errorf(`%q is not a valid output format. Please use one of: "text", "json"`, o.Format)
+	}
+	printRefSelect(o.ErrOut, o.Refs)
+
+	res := []lib.VersionTag{}
+	if err := o.LogMethods.TagList(&lib.TagListParams{Ref: o.Refs.Ref()}, &res); err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	if len(res) == 0 {
+		printInfo(o.Out, "no tags")
+		return nil
+	}
+	for _, tag := range res {
+		fmt.Fprintf(o.Out, "%s\t%s\t%s\n", tag.Name, tag.Path, tag.Timestamp.In(StringerLocation).Format(time.RFC3339))
+	}
+	return nil
+}
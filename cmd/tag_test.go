@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagCommand(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_tag", "qri_test_tag")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_thirty.csv me/test_movies")
+
+	r.MustExec(t, "qri tag me/test_movies~1 v1")
+	r.MustExec(t, "qri tag me/test_movies latest")
+
+	if err := r.ExecCommand("qri tag me/test_movies v1"); err == nil {
+		t.Error("expected moving a tag without --force to error")
+	}
+	r.MustExec(t, "qri tag me/test_movies~1 latest --force")
+
+	output := r.MustExec(t, "qri tag list me/test_movies")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "latest\t") || !strings.HasPrefix(lines[1], "v1\t") {
+		t.Errorf("unexpected tag list output:\n%s", output)
+	}
+
+	output = r.MustExec(t, "qri log me/test_movies")
+	if !strings.Contains(output, "Tags:    latest, v1") {
+		t.Errorf("expected log to show tags on the first version, got:\n%s", output)
+	}
+}
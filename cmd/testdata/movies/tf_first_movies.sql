@@ -0,0 +1,3 @@
+SELECT m.movie_title AS title, m.duration AS duration
+FROM test_peer_save_sql_transform/movies AS m
+LIMIT 3
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/startf"
+	"github.com/spf13/cobra"
+)
+
+// NewTransformCommand creates a new `qri transform` command for working with
+// transform scripts
+func NewTransformCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &TransformOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "transform",
+		Short: "tools for developing transform scripts",
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	test := &cobra.Command{
+		Use:   "test [SCRIPT]",
+		Short: "run unit tests for a starlark transform script",
+		Long: `test runs every function whose name starts with "test_" in a transform's
+companion test script. For a script named transform.star tests are read from
+transform_test.star.
+
+Each test function is called with a single argument that exposes:
+  * run(prev=None, config=None, secrets=None, download=None):
+      execute the transform with a fake previous dataset, config & secrets.
+      The network is always disabled. Passing download skips calling the
+      script's download function & uses the given value instead.
+  * assert_body(rows), assert_schema(schema), assert_meta(key, value):
+      compare the output of the last call to run with expected values
+  * assert_eq(got, want, msg=""), fail(msg):
+      general purpose assertions`,
+		Example: `  # run tests in transform_test.star against transform.star:
+  $ qri transform test
+
+  # run tests for another script, printing results as JSON:
+  $ qri transform test scrape.star --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Test()
+		},
+	}
+
+	test.Flags().StringVar(&o.TestScriptPath, "test-file", "", "path to test script, defaults to SCRIPT_test.star")
+	test.MarkFlagFilename("test-file", "star")
+	test.Flags().BoolVarP(&o.Verbose, "verbose", "v", false, "show output of passing tests")
+	test.Flags().BoolVar(&o.JSON, "json", false, "print results as JSON")
+
+	cmd.AddCommand(test)
+	return cmd
+}
+
+// TransformOptions encapsulates state for the transform command
+type TransformOptions struct {
+	ioes.IOStreams
+
+	ScriptPath     string
+	TestScriptPath string
+	Verbose        bool
+	JSON           bool
+
+	TransformMethods *lib.TransformMethods
+}
+
+// Complete adds any missing configuration that can only be added just before
+// calling Run
+func (o *TransformOptions) Complete(f Factory, args []string) (err error) {
+	o.ScriptPath = "transform.star"
+	if len(args) > 0 {
+		o.ScriptPath = args[0]
+	}
+	o.TransformMethods, err = f.TransformMethods()
+	return
+}
+
+// Test executes the transform test command
+func (o *TransformOptions) Test() error {
+	p := &lib.TransformTestParams{
+		ScriptPath:     o.ScriptPath,
+		TestScriptPath: o.TestScriptPath,
+	}
+
+	res := []*startf.TestResult{}
+	if err := o.TransformMethods.Test(p, &res); err != nil {
+		return err
+	}
+
+	passed := true
+	for _, r := range res {
+		passed = passed && r.Passed
+	}
+
+	if o.JSON {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	} else {
+		printTransformTestResults(o.Out, res, o.Verbose)
+		if passed {
+			fmt.Fprintln(o.Out, "PASS")
+		} else {
+			fmt.Fprintln(o.Out, "FAIL")
+		}
+	}
+
+	if !passed {
+		return fmt.Errorf("transform tests failed")
+	}
+	return nil
+}
+
+// printTransformTestResults writes results in the style of `go test`
+func printTransformTestResults(w io.Writer, res []*startf.TestResult, verbose bool) {
+	for _, r := range res {
+		if verbose {
+			fmt.Fprintf(w, "=== RUN   %s\n", r.Name)
+		}
+		if r.Passed && !verbose {
+			continue
+		}
+
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "--- %s: %s (%.2fs)\n", status, r.Name, r.Duration.Seconds())
+		for _, line := range strings.Split(strings.TrimSpace(r.Output), "\n") {
+			if line != "" {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+		for _, msg := range r.Failures {
+			fmt.Fprintf(w, "    %s\n", strings.Replace(msg, "\n", "\n    ", -1))
+		}
+		if r.Error != "" {
+			fmt.Fprintf(w, "    %s\n", strings.Replace(strings.TrimSpace(r.Error), "\n", "\n    ", -1))
+		}
+	}
+}
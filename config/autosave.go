@@ -0,0 +1,118 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/qri-io/jsonschema"
+)
+
+// Autosave configures automatic saving of working directories linked to
+// datasets with FSI
+type Autosave struct {
+	// time a working directory must go without changes before saving,
+	// in milliseconds
+	QuietPeriodMs int `json:"quietperiodms"`
+	// minimum time between saves of the same working directory, in milliseconds
+	MinIntervalMs int `json:"minintervalms"`
+	// Links lists working directories with autosave enabled
+	Links []*AutosaveLink `json:"links"`
+}
+
+// AutosaveLink enables autosave for a single linked working directory
+type AutosaveLink struct {
+	// absolute path to the working directory
+	Path string `json:"path"`
+	// paused links keep their settings, but aren't saved
+	Paused bool `json:"paused"`
+}
+
+// SetArbitrary is an interface implementation of base/fill/struct in order to safely
+// consume config files that have definitions beyond those specified in the struct.
+// This simply ignores all additional fields at read time.
+func (cfg *Autosave) SetArbitrary(key string, val interface{}) error {
+	return nil
+}
+
+// DefaultAutosave creates & returns a new default autosave configuration
+func DefaultAutosave() *Autosave {
+	return &Autosave{
+		// 5 seconds
+		QuietPeriodMs: 5000,
+		// 1 minute
+		MinIntervalMs: 60000,
+		Links:         []*AutosaveLink{},
+	}
+}
+
+// Link returns the autosave settings for a working directory, nil if autosave
+// isn't enabled for the directory
+func (cfg *Autosave) Link(dir string) *AutosaveLink {
+	if cfg == nil {
+		return nil
+	}
+	dir = filepath.Clean(dir)
+	for _, l := range cfg.Links {
+		if filepath.Clean(l.Path) == dir {
+			return l
+		}
+	}
+	return nil
+}
+
+// Validate validates all fields of autosave returning all errors found.
+func (cfg Autosave) Validate() error {
+	schema := jsonschema.Must(`{
+    "$schema": "http://json-schema.org/draft-06/schema#",
+    "title": "Autosave",
+    "description": "Config for automatically saving linked working directories",
+    "type": "object",
+    "required": ["quietperiodms", "minintervalms"],
+    "properties": {
+      "quietperiodms": {
+        "description": "Time a working directory must go without changes before saving, in milliseconds",
+        "type": "integer",
+        "minimum": 0
+      },
+      "minintervalms": {
+        "description": "Minimum time between saves of the same working directory, in milliseconds",
+        "type": "integer",
+        "minimum": 0
+      },
+      "links": {
+        "description": "Working directories with autosave enabled",
+        "type": ["array", "null"],
+        "items": {
+          "type": "object",
+          "required": ["path"],
+          "properties": {
+            "path": {
+              "description": "Absolute path to the working directory",
+              "type": "string"
+            },
+            "paused": {
+              "description": "Whether autosave is paused for this directory",
+              "type": "boolean"
+            }
+          }
+        }
+      }
+    }
+  }`)
+	return validate(schema, &cfg)
+}
+
+// Copy returns a deep copy of an Autosave struct
+func (cfg *Autosave) Copy() *Autosave {
+	res := &Autosave{
+		QuietPeriodMs: cfg.QuietPeriodMs,
+		MinIntervalMs: cfg.MinIntervalMs,
+	}
+	if cfg.Links != nil {
+		res.Links = make([]*AutosaveLink, len(cfg.Links))
+		for i, l := range cfg.Links {
+			link := *l
+			res.Links[i] = &link
+		}
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutosaveValidate(t *testing.T) {
+	if err := DefaultAutosave().Validate(); err != nil {
+		t.Errorf("error validating default autosave: %s", err)
+	}
+
+	invalid := DefaultAutosave()
+	invalid.QuietPeriodMs = -1
+	if err := invalid.Validate(); err == nil {
+		t.Errorf("expected negative quiet period to error")
+	}
+}
+
+func TestAutosaveCopy(t *testing.T) {
+	a := DefaultAutosave()
+	a.Links = append(a.Links, &AutosaveLink{Path: "/path/to/dir", Paused: true})
+
+	cpy := a.Copy()
+	if !reflect.DeepEqual(cpy, a) {
+		t.Errorf("autosave copy mismatch.\ncopy: %v\noriginal: %v", cpy, a)
+	}
+	cpy.Links[0].Paused = false
+	if !a.Links[0].Paused {
+		t.Errorf("modifying a copied link changed the original")
+	}
+}
+
+func TestAutosaveLink(t *testing.T) {
+	var a *Autosave
+	if l := a.Link("/path/to/dir"); l != nil {
+		t.Errorf("expected nil config to return no link")
+	}
+
+	a = DefaultAutosave()
+	a.Links = append(a.Links, &AutosaveLink{Path: "/path/to/dir"})
+	if l := a.Link("/path/to/dir/"); l == nil {
+		t.Errorf("expected link for directory")
+	}
+	if l := a.Link("/path/to/other"); l != nil {
+		t.Errorf("expected no link for other directory")
+	}
+}
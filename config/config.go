@@ -30,6 +30,8 @@ type Config struct {
 	Filesystems []qfs.Config
 	P2P         *P2P
 	Stats       *Stats
+	Autosave    *Autosave
+	Retention   *Retention
 
 	Registry *Registry
 	Remotes  *Remotes
@@ -224,6 +226,8 @@ func (cfg Config) Validate() error {
 		cfg.API,
 		cfg.RPC,
 		cfg.Logging,
+		cfg.Autosave,
+		cfg.Retention,
 	}
 	for _, val := range validators {
 		// we need to check here because we're potentially calling methods on nil
@@ -282,6 +286,12 @@ func (cfg *Config) Copy() *Config {
 	if cfg.Stats != nil {
 		res.Stats = cfg.Stats.Copy()
 	}
+	if cfg.Autosave != nil {
+		res.Autosave = cfg.Autosave.Copy()
+	}
+	if cfg.Retention != nil {
+		res.Retention = cfg.Retention.Copy()
+	}
 	if cfg.Filesystems != nil {
 		for _, fs := range cfg.Filesystems {
 			res.Filesystems = append(res.Filesystems, fs)
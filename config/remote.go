@@ -19,6 +19,10 @@ type Remote struct {
 	RequireAllBlocks bool `json:"requireallblocks"`
 	// allow clients to request unpins for their own pushes
 	AllowRemoves bool `json:"allowremoves"`
+	// retention policy for versions stored on the remote. after each push,
+	// versions the author has removed from history that the policy doesn't
+	// keep are dropped from storage. nil keeps all versions
+	Retention *RetentionPolicy `json:"retention,omitempty"`
 }
 
 // SetArbitrary is an interface implementation of base/fill/struct in order to safely
@@ -46,7 +50,13 @@ func (cfg Remote) Validate() error {
       }
     }
   }`)
-	return validate(schema, &cfg)
+	if err := validate(schema, &cfg); err != nil {
+		return err
+	}
+	if cfg.Retention != nil {
+		return cfg.Retention.Validate()
+	}
+	return nil
 }
 
 // Copy returns a deep copy of the Remote struct
@@ -58,6 +68,9 @@ func (cfg *Remote) Copy() *Remote {
 		RequireAllBlocks: cfg.RequireAllBlocks,
 		AllowRemoves:     cfg.AllowRemoves,
 	}
+	if cfg.Retention != nil {
+		res.Retention = cfg.Retention.Copy()
+	}
 
 	return res
 }
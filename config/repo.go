@@ -8,6 +8,11 @@ import (
 type Repo struct {
 	Type string `json:"type"`
 	Path string `json:"path,omitempty"`
+	// KeepVersions is the number of most recent versions of each dataset kept
+	// in local storage. Older versions that have been pushed to a remote are
+	// evicted, remaining in dataset history & fetched from the remote when
+	// loaded. 0 keeps all versions
+	KeepVersions int `json:"keepVersions,omitempty"`
 }
 
 // SetArbitrary is an interface implementation of base/fill/struct in order to safely
@@ -40,6 +45,11 @@ func (cfg Repo) Validate() error {
           "fs",
           "mem"
         ]
+      },
+      "keepVersions": {
+        "description": "Number of recent versions of each dataset to keep locally, 0 keeps all versions",
+        "type": "integer",
+        "minimum": 0
       }
     }
   }`)
@@ -49,7 +59,8 @@ func (cfg Repo) Validate() error {
 // Copy returns a deep copy of the Repo struct
 func (cfg *Repo) Copy() *Repo {
 	res := &Repo{
-		Type: cfg.Type,
+		Type:         cfg.Type,
+		KeepVersions: cfg.KeepVersions,
 	}
 
 	return res
@@ -10,17 +10,26 @@ func TestRepoValidate(t *testing.T) {
 	if err != nil {
 		t.Errorf("error validating default repo: %s", err)
 	}
+
+	r := DefaultRepo()
+	r.KeepVersions = -1
+	if err := r.Validate(); err == nil {
+		t.Errorf("expected negative keepVersions to fail validation")
+	}
 }
 
 func TestRepoCopy(t *testing.T) {
 	// build off DefaultRepo so we can test that the repo Copy
 	// actually copies over correctly (ie, deeply)
 	r := DefaultRepo()
+	keep := DefaultRepo()
+	keep.KeepVersions = 3
 
 	cases := []struct {
 		repo *Repo
 	}{
 		{r},
+		{keep},
 	}
 	for i, c := range cases {
 		cpy := c.repo.Copy()
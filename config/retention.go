@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qri-io/jsonschema"
+)
+
+// Retention configures which versions of datasets are kept in history.
+// Versions a policy doesn't keep are pruned with "qri prune", and after each
+// save when PruneOnSave is set
+type Retention struct {
+	// prune a dataset's history after each save
+	PruneOnSave bool `json:"pruneonsave"`
+	// Default is the policy for datasets without a policy of their own. nil
+	// keeps all versions
+	Default *RetentionPolicy `json:"default"`
+	// Datasets lists per-dataset policies
+	Datasets []*RetentionPolicy `json:"datasets"`
+}
+
+// RetentionPolicy lists rules for versions to keep. A version is kept if any
+// rule keeps it, and the latest version is always kept. A policy without any
+// rules keeps all versions
+type RetentionPolicy struct {
+	// reference to the dataset the policy applies to, like "peer/dataset".
+	// unused for default policies
+	Ref string `json:"ref,omitempty"`
+	// number of most recent versions to keep
+	KeepLast int `json:"keeplast"`
+	// keep versions committed within a duration of now, like "72h", "30d", or
+	// "4w"
+	KeepWithin string `json:"keepwithin"`
+	// keep all tagged versions
+	KeepTagged bool `json:"keeptagged"`
+	// beyond versions kept by KeepLast & KeepWithin, keep the latest version of
+	// each of this many days, weeks, and months
+	KeepDaily   int `json:"keepdaily"`
+	KeepWeekly  int `json:"keepweekly"`
+	KeepMonthly int `json:"keepmonthly"`
+}
+
+// SetArbitrary is an interface implementation of base/fill/struct in order to safely
+// consume config files that have definitions beyond those specified in the struct.
+// This simply ignores all additional fields at read time.
+func (cfg *Retention) SetArbitrary(key string, val interface{}) error {
+	return nil
+}
+
+// DefaultRetention creates & returns a new default retention configuration,
+// which keeps all versions
+func DefaultRetention() *Retention {
+	return &Retention{
+		Datasets: []*RetentionPolicy{},
+	}
+}
+
+// Policy returns the retention policy for a dataset reference string, falling
+// back to the default policy. nil means all versions are kept
+func (cfg *Retention) Policy(ref string) *RetentionPolicy {
+	if cfg == nil {
+		return nil
+	}
+	for _, p := range cfg.Datasets {
+		if p.Ref == ref {
+			return p
+		}
+	}
+	return cfg.Default
+}
+
+// Validate validates all fields of retention returning all errors found.
+func (cfg Retention) Validate() error {
+	schema := jsonschema.Must(`{
+    "$schema": "http://json-schema.org/draft-06/schema#",
+    "title": "Retention",
+    "description": "Config for which versions of datasets are kept in history",
+    "type": "object",
+    "properties": {
+      "pruneonsave": {
+        "description": "Prune a dataset's history after each save",
+        "type": "boolean"
+      },
+      "default": {
+        "description": "Policy for datasets without a policy of their own",
+        "type": ["object", "null"]
+      },
+      "datasets": {
+        "description": "Per-dataset retention policies",
+        "type": ["array", "null"],
+        "items": {
+          "type": "object",
+          "required": ["ref"],
+          "properties": {
+            "ref": {
+              "description": "Reference to the dataset the policy applies to",
+              "type": "string"
+            }
+          }
+        }
+      }
+    }
+  }`)
+	if err := validate(schema, &cfg); err != nil {
+		return err
+	}
+	if cfg.Default != nil {
+		if err := cfg.Default.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, p := range cfg.Datasets {
+		if p.Ref == "" {
+			return fmt.Errorf("retention: dataset policies require a ref")
+		}
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy returns a deep copy of a Retention struct
+func (cfg *Retention) Copy() *Retention {
+	res := &Retention{
+		PruneOnSave: cfg.PruneOnSave,
+	}
+	if cfg.Default != nil {
+		res.Default = cfg.Default.Copy()
+	}
+	if cfg.Datasets != nil {
+		res.Datasets = make([]*RetentionPolicy, len(cfg.Datasets))
+		for i, p := range cfg.Datasets {
+			res.Datasets[i] = p.Copy()
+		}
+	}
+	return res
+}
+
+// Validate checks a retention policy for errors
+func (p *RetentionPolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepDaily < 0 || p.KeepWeekly < 0 || p.KeepMonthly < 0 {
+		return fmt.Errorf("retention: version counts can't be negative")
+	}
+	if _, err := p.Within(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// KeepsAll reports if a policy has no rules, keeping all versions
+func (p *RetentionPolicy) KeepsAll() bool {
+	return p == nil || (p.KeepLast == 0 && p.KeepWithin == "" && !p.KeepTagged &&
+		p.KeepDaily == 0 && p.KeepWeekly == 0 && p.KeepMonthly == 0)
+}
+
+// Within parses the KeepWithin duration. Durations accept the units of
+// time.ParseDuration, plus "d" for days and "w" for weeks
+func (p *RetentionPolicy) Within() (time.Duration, error) {
+	if p.KeepWithin == "" {
+		return 0, nil
+	}
+	s := p.KeepWithin
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("retention: invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("retention: invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// Copy returns a copy of a retention policy
+func (p *RetentionPolicy) Copy() *RetentionPolicy {
+	res := *p
+	return &res
+}
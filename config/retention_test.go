@@ -0,0 +1,86 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetentionValidate(t *testing.T) {
+	if err := DefaultRetention().Validate(); err != nil {
+		t.Errorf("error validating default retention: %s", err)
+	}
+
+	valid := DefaultRetention()
+	valid.Default = &RetentionPolicy{KeepLast: 3, KeepWithin: "30d", KeepMonthly: 12}
+	valid.Datasets = append(valid.Datasets, &RetentionPolicy{Ref: "peer/dataset", KeepTagged: true})
+	if err := valid.Validate(); err != nil {
+		t.Errorf("error validating retention: %s", err)
+	}
+
+	invalid := []*Retention{
+		{Default: &RetentionPolicy{KeepLast: -1}},
+		{Default: &RetentionPolicy{KeepWithin: "a while"}},
+		{Datasets: []*RetentionPolicy{{KeepLast: 1}}},
+	}
+	for i, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("case %d: expected error, got nil", i)
+		}
+	}
+}
+
+func TestRetentionCopy(t *testing.T) {
+	a := DefaultRetention()
+	a.Default = &RetentionPolicy{KeepLast: 3}
+	a.Datasets = append(a.Datasets, &RetentionPolicy{Ref: "peer/dataset", KeepDaily: 7})
+
+	cpy := a.Copy()
+	if !reflect.DeepEqual(cpy, a) {
+		t.Errorf("retention copy mismatch.\ncopy: %v\noriginal: %v", cpy, a)
+	}
+	cpy.Datasets[0].KeepDaily = 1
+	cpy.Default.KeepLast = 1
+	if a.Datasets[0].KeepDaily != 7 || a.Default.KeepLast != 3 {
+		t.Errorf("modifying a copied policy changed the original")
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	var r *Retention
+	if p := r.Policy("peer/dataset"); p != nil {
+		t.Errorf("expected nil config to return no policy")
+	}
+
+	r = DefaultRetention()
+	r.Default = &RetentionPolicy{KeepLast: 3}
+	r.Datasets = append(r.Datasets, &RetentionPolicy{Ref: "peer/dataset", KeepLast: 10})
+	if p := r.Policy("peer/dataset"); p.KeepLast != 10 {
+		t.Errorf("expected dataset policy, got: %v", p)
+	}
+	if p := r.Policy("peer/other"); p.KeepLast != 3 {
+		t.Errorf("expected default policy, got: %v", p)
+	}
+}
+
+func TestRetentionPolicyWithin(t *testing.T) {
+	cases := []struct {
+		within string
+		expect time.Duration
+	}{
+		{"", 0},
+		{"36h", 36 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+	}
+	for _, c := range cases {
+		got, err := (&RetentionPolicy{KeepWithin: c.within}).Within()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.within, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%q: expected %s, got %s", c.within, c.expect, got)
+		}
+	}
+}
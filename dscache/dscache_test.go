@@ -81,6 +81,52 @@ func TestDscacheAssignSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestDscacheMixedPaths(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	ctx := context.Background()
+	fs, err := localfs.NewFS(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peerInfo := testPeers.GetTestPeerInfo(0)
+	peername := "test_user"
+	profileID := profile.IDFromPeerID(peerInfo.PeerID).String()
+
+	paths := map[string]string{
+		"cidv0":   "/ipfs/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn",
+		"cidv1":   "/ipfs/bafybeibq5y3ndsmzx4srizqwssqravof5esn5k5v57w7x732dejlpihb3y",
+		"arweave": "/arweave/Some1Hash",
+	}
+	builder := NewBuilder()
+	builder.AddUser(peername, profileID)
+	for name, path := range paths {
+		builder.AddDsVersionInfo(dsref.VersionInfo{InitID: name, Username: peername, ProfileID: profileID, Name: name, Path: path})
+	}
+
+	dscacheFile := filepath.Join(tmpdir, "dscache.qfb")
+	saveable := NewDscache(ctx, fs, event.NilBus, peername, dscacheFile)
+	saveable.Assign(builder.Build())
+
+	// paths round-trip through the serialized flatbuffer unchanged
+	loadable := NewDscache(ctx, fs, event.NilBus, peername, dscacheFile)
+	for name, path := range paths {
+		ref := dsref.Ref{Username: peername, Name: name}
+		if _, err := loadable.ResolveRef(ctx, &ref); err != nil {
+			t.Errorf("resolving %s: %s", name, err)
+			continue
+		}
+		if ref.Path != path {
+			t.Errorf("%s path mismatch. want %q, got %q", name, path, ref.Path)
+		}
+	}
+}
+
 func TestResolveRef(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "")
 	if err != nil {
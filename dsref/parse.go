@@ -3,6 +3,7 @@ package dsref
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"unicode"
 )
 
@@ -17,9 +18,15 @@ import (
 //
 // The grammar is here:
 //
-//  <dsref> = <humanFriendlyPortion> [ <concreteRef> ] | <concreteRef>
+//  <dsref> = <humanFriendlyPortion> [ <concreteRef> | <revision> ] | <concreteRef>
 //  <humanFriendlyPortion> = <validName> '/' <validName>
-//  <concretePath> = '@' [ <profileID> ] '/' <network> '/' <commitHash>
+//  <concretePath> = '@' [ <profileID> ] '/' <network> '/' <commitHash> [ <ancestry> ]
+//
+// commit hashes on the "ipfs" & "mem" networks are CIDs, either base58 "Qm..."
+// CIDv0s, or CIDv1s like "bafy...". Hashes on other networks aren't checked
+//
+// revisions select versions relative to a tag, time, or another version. The
+// revision grammar is described by the Revision type
 //
 // Some examples of valid references:
 //     me/dataset
@@ -27,6 +34,10 @@ import (
 //     @/ipfs/QmSome1Commit2Hash3
 //     @QmProfile4ID5/ipfs/QmSome1Commit2Hash3
 //     username/dataset@QmProfile4ID5/ipfs/QmSome1Commit2Hash3
+//     username/dataset@/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi
+//     username/dataset@/network/Some1Hash
+//     username/dataset~2
+//     username/dataset@v1.0
 // An invalid reference:
 //     /ipfs/QmSome1Commit2Hash3
 
@@ -34,12 +45,13 @@ const (
 	alphaNumeric       = `[a-zA-Z][\w-]*`
 	alphaNumericDsname = `[a-zA-Z][\w-]{0,143}`
 	b58Id              = `Qm[0-9a-zA-Z]{0,44}`
+	hashID             = `[0-9a-zA-Z]+`
 )
 
 var (
 	validName      = regexp.MustCompile(`^` + alphaNumeric)
 	dsNameCheck    = regexp.MustCompile(`^` + alphaNumericDsname + `$`)
-	concretePath   = regexp.MustCompile(`^@(` + b58Id + `)?\/(` + alphaNumeric + `)\/(` + b58Id + `)`)
+	concretePath   = regexp.MustCompile(`^@(` + b58Id + `)?\/(` + alphaNumeric + `)\/(` + hashID + `)`)
 	b58StrictCheck = regexp.MustCompile(`^Qm[1-9A-HJ-NP-Za-km-z]*$`)
 
 	// ErrEmptyRef is an error for when a reference is empty
@@ -84,7 +96,8 @@ func Parse(text string) (Ref, error) {
 	}
 
 	remain, partial, err = parseConcretePath(text)
-	if err == nil {
+	hasPath := err == nil
+	if hasPath {
 		text = remain
 		r.ProfileID = partial.ProfileID
 		r.Path = partial.Path
@@ -92,6 +105,15 @@ func Parse(text string) (Ref, error) {
 		return r, err
 	}
 
+	if r.Name != "" || hasPath {
+		rev := Revision{}
+		if remain, err = parseRevision(text, &rev, !hasPath); err != nil {
+			return r, err
+		}
+		text = remain
+		r.Revision = rev.String()
+	}
+
 	if text != "" {
 		pos := origLength - len(text)
 		return r, NewParseError("unexpected character at position %d: '%c'", pos, text[0])
@@ -245,16 +267,17 @@ func parseConcretePath(text string) (string, Ref, error) {
 	if len(matches) != 4 {
 		return text, r, NewParseError("unexpected number of regex matches %d", len(matches))
 	}
-	if matches[2] != "mem" && matches[2] != "ipfs" {
-		return text, r, NewParseError("invalid network")
-	}
 	matchedLen := len(matches[0])
 	if matches[1] != "" && b58StrictCheck.FindString(matches[1]) == "" {
 		return text, r, NewParseError("profileID contains invalid base58 characters")
 	}
 	r.ProfileID = matches[1]
-	if matches[3] != "" && b58StrictCheck.FindString(matches[3]) == "" {
-		return text, r, NewParseError("path contains invalid base58 characters")
+	if strings.HasPrefix(matches[3], "Qm") {
+		if b58StrictCheck.FindString(matches[3]) == "" {
+			return text, r, NewParseError("path contains invalid base58 characters")
+		}
+	} else if cidNetworks[matches[2]] && !IsContentHash(matches[3]) {
+		return text, r, NewParseError("path contains an invalid content identifier")
 	}
 	r.Path = fmt.Sprintf("/%s/%s", matches[2], matches[3])
 	return text[matchedLen:], r, nil
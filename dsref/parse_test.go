@@ -15,6 +15,9 @@ func TestParseFull(t *testing.T) {
 		{"right hand side", "@QmFirst/ipfs/QmSecond", Ref{ProfileID: "QmFirst", Path: "/ipfs/QmSecond"}},
 		{"just path", "@/ipfs/QmSecond", Ref{Path: "/ipfs/QmSecond"}},
 		{"long name", "peer/some_name@/mem/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn", Ref{Username: "peer", Name: "some_name", Path: "/mem/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn"}},
+		{"cidv1 path", "peer/some_name@/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu", Ref{Username: "peer", Name: "some_name", Path: "/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu"}},
+		{"cidv1 path with profileID", "@QmFirst/mem/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu~2", Ref{ProfileID: "QmFirst", Path: "/mem/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu", Revision: "~2"}},
+		{"other network", "peer/some_name@/arweave/Some1Hash", Ref{Username: "peer", Name: "some_name", Path: "/arweave/Some1Hash"}},
 		{"name-has-dash", "abc/my-dataset", Ref{Username: "abc", Name: "my-dataset"}},
 		{"dash-in-username", "some-user/my_dataset", Ref{Username: "some-user", Name: "my_dataset"}},
 	}
@@ -36,6 +39,7 @@ func TestParseFull(t *testing.T) {
 	}{
 		{"missing at", "/ipfs/QmThis", "unexpected character at position 0: '/'"},
 		{"invalid base58", "@/ipfs/QmOne", "path contains invalid base58 characters"},
+		{"invalid cid", "@/ipfs/bafyNotACid", "path contains an invalid content identifier"},
 		{"no slash", "foo", "need username separated by '/' from dataset name"},
 		{"http url", "https://apple.com", "unexpected character at position 5: ':'"},
 		{"domain name", "apple.com", "unexpected character at position 5: '.'"},
@@ -0,0 +1,157 @@
+package dsref
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	cid "github.com/ipfs/go-cid"
+	multihash "github.com/multiformats/go-multihash"
+)
+
+// Content-addressed paths have the form /<network>/<hash>, optionally followed
+// by a path within the content, like /ipfs/QmHash/dataset.json. Hashes on
+// the "ipfs" and "mem" networks are content identifiers (CIDs), either
+// base58-encoded version 0 CIDs ("Qm...") or multibase-encoded version 1 CIDs,
+// which are usually base32 ("bafy..."). Both versions of a CID can identify
+// the same content, so repos may hold a mix of path encodings for a dataset.
+// Paths on other networks are passed along as-is
+
+var (
+	// ErrInvalidPath is returned when a string isn't a content-addressed path
+	ErrInvalidPath = fmt.Errorf("invalid path")
+	// ErrUnsupportedNetwork is returned when resolving a path on a network that
+	// has no filesystem to load content from
+	ErrUnsupportedNetwork = fmt.Errorf("unsupported network")
+)
+
+// cidNetworks are networks that address content with CIDs
+var cidNetworks = map[string]bool{
+	"ipfs": true,
+	"mem":  true,
+}
+
+// SplitPath breaks a content-addressed path into network, hash, and the
+// remaining path within the content, which may be empty
+func SplitPath(path string) (network, hash, rest string, err error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", "", "", fmt.Errorf("%w %q: must start with '/'", ErrInvalidPath, path)
+	}
+	parts := strings.SplitN(path[1:], "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("%w %q: expected /network/hash", ErrInvalidPath, path)
+	}
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	return parts[0], parts[1], rest, nil
+}
+
+// IsContentHash returns whether a string is a valid CID of either version
+func IsContentHash(hash string) bool {
+	_, err := cid.Decode(hash)
+	return err == nil
+}
+
+// CIDv1Path converts the hash of a path on a CID network to a base32 CIDv1.
+// Paths that already use CIDv1 and paths on other networks are returned
+// unchanged
+func CIDv1Path(path string) (string, error) {
+	network, hash, rest, err := SplitPath(path)
+	if err != nil {
+		return "", err
+	}
+	if !cidNetworks[network] {
+		return path, nil
+	}
+	c, err := cid.Decode(hash)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %s", ErrInvalidPath, path, err)
+	}
+	if c.Version() == 0 {
+		c = cid.NewCidV1(cid.DagProtobuf, c.Hash())
+	}
+	return joinPath(network, c.String(), rest), nil
+}
+
+// CIDv0Path converts the hash of a path on a CID network to a base58 CIDv0.
+// Only CIDv1s of protobuf-encoded content with a sha2-256 hash have a CIDv0
+// form, converting any other CIDv1 is an error. Paths on other networks are
+// returned unchanged
+func CIDv0Path(path string) (string, error) {
+	network, hash, rest, err := SplitPath(path)
+	if err != nil {
+		return "", err
+	}
+	if !cidNetworks[network] {
+		return path, nil
+	}
+	c, err := cid.Decode(hash)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %s", ErrInvalidPath, path, err)
+	}
+	if c.Version() == 1 {
+		pre := c.Prefix()
+		if pre.Codec != cid.DagProtobuf || pre.MhType != multihash.SHA2_256 {
+			return "", fmt.Errorf("%w %q: CID has no version 0 form", ErrInvalidPath, path)
+		}
+		c = cid.NewCidV0(c.Hash())
+	}
+	return joinPath(network, c.String(), rest), nil
+}
+
+// AltPath returns the other CID version of a path, for looking up content
+// that may have been stored under either version. The second return value is
+// false when the path has no other form
+func AltPath(path string) (string, bool) {
+	network, hash, _, err := SplitPath(path)
+	if err != nil || !cidNetworks[network] {
+		return "", false
+	}
+	c, err := cid.Decode(hash)
+	if err != nil {
+		return "", false
+	}
+	var alt string
+	if c.Version() == 0 {
+		alt, err = CIDv1Path(path)
+	} else {
+		alt, err = CIDv0Path(path)
+	}
+	if err != nil {
+		return "", false
+	}
+	return alt, true
+}
+
+// EqualPaths returns whether two paths address the same content on the same
+// network, regardless of the CID version or encoding of their hashes
+func EqualPaths(a, b string) bool {
+	if a == b {
+		return true
+	}
+	aNet, aHash, aRest, err := SplitPath(a)
+	if err != nil {
+		return false
+	}
+	bNet, bHash, bRest, err := SplitPath(b)
+	if err != nil || aNet != bNet || aRest != bRest || !cidNetworks[aNet] {
+		return false
+	}
+	ac, err := cid.Decode(aHash)
+	if err != nil {
+		return false
+	}
+	bc, err := cid.Decode(bHash)
+	if err != nil {
+		return false
+	}
+	return ac.Type() == bc.Type() && bytes.Equal(ac.Hash(), bc.Hash())
+}
+
+func joinPath(network, hash, rest string) string {
+	if rest == "" {
+		return fmt.Sprintf("/%s/%s", network, hash)
+	}
+	return fmt.Sprintf("/%s/%s/%s", network, hash, rest)
+}
@@ -0,0 +1,114 @@
+package dsref
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	cidV0Path = "/ipfs/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn"
+	cidV1Path = "/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu"
+)
+
+func TestSplitPath(t *testing.T) {
+	cases := []struct {
+		path, network, hash, rest string
+	}{
+		{cidV0Path, "ipfs", "QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn", ""},
+		{cidV1Path + "/dataset.json", "ipfs", "bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu", "dataset.json"},
+		{"/arweave/Some1Hash/body/data.csv", "arweave", "Some1Hash", "body/data.csv"},
+	}
+	for i, c := range cases {
+		network, hash, rest, err := SplitPath(c.path)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if network != c.network || hash != c.hash || rest != c.rest {
+			t.Errorf("case %d mismatch. want (%q, %q, %q), got (%q, %q, %q)", i, c.network, c.hash, c.rest, network, hash, rest)
+		}
+	}
+
+	for i, bad := range []string{"", "ipfs/QmFoo", "/ipfs", "/ipfs/", "//QmFoo"} {
+		if _, _, _, err := SplitPath(bad); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("bad case %d expected ErrInvalidPath, got: %v", i, err)
+		}
+	}
+}
+
+func TestCIDPathConversion(t *testing.T) {
+	got, err := CIDv1Path(cidV0Path + "/dataset.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := cidV1Path + "/dataset.json"; got != expect {
+		t.Errorf("CIDv1Path mismatch. want %q, got %q", expect, got)
+	}
+	got, err = CIDv0Path(cidV1Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != cidV0Path {
+		t.Errorf("CIDv0Path mismatch. want %q, got %q", cidV0Path, got)
+	}
+
+	// conversions are idempotent & leave other networks alone
+	for _, p := range []string{cidV0Path, "/arweave/Some1Hash"} {
+		if got, err := CIDv0Path(p); err != nil || got != p {
+			t.Errorf("expected CIDv0Path(%q) to be unchanged, got %q, %v", p, got, err)
+		}
+	}
+	for _, p := range []string{cidV1Path, "/arweave/Some1Hash"} {
+		if got, err := CIDv1Path(p); err != nil || got != p {
+			t.Errorf("expected CIDv1Path(%q) to be unchanged, got %q, %v", p, got, err)
+		}
+	}
+
+	// raw-codec CIDv1s have no CIDv0 form
+	raw := "/ipfs/bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"
+	if !IsContentHash("bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku") {
+		t.Fatal("expected raw CID to be a content hash")
+	}
+	if _, err := CIDv0Path(raw); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected converting a raw CID to version 0 to fail, got: %v", err)
+	}
+	if _, ok := AltPath(raw); ok {
+		t.Error("expected raw CID to have no alternate path")
+	}
+	if _, err := CIDv1Path("/ipfs/NotACid"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected invalid CID to fail, got: %v", err)
+	}
+
+	if alt, ok := AltPath(cidV0Path); !ok || alt != cidV1Path {
+		t.Errorf("AltPath(%q) mismatch. want %q, got %q", cidV0Path, cidV1Path, alt)
+	}
+	if alt, ok := AltPath(cidV1Path); !ok || alt != cidV0Path {
+		t.Errorf("AltPath(%q) mismatch. want %q, got %q", cidV1Path, cidV0Path, alt)
+	}
+}
+
+func TestEqualPaths(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{cidV0Path, cidV0Path, true},
+		{cidV0Path, cidV1Path, true},
+		{cidV1Path + "/dataset.json", cidV0Path + "/dataset.json", true},
+		{"/arweave/Some1Hash", "/arweave/Some1Hash", true},
+
+		{cidV0Path, cidV1Path + "/dataset.json", false},
+		{cidV0Path, "/mem/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn", false},
+		{cidV0Path, "/ipfs/QmYCvbfNbCwFR45HiNP45rwJgvatpiW38D961L5qAhUM5Y", false},
+		{"/arweave/Some1Hash", "/arweave/Other1Hash", false},
+		{"", cidV0Path, false},
+	}
+	for i, c := range cases {
+		if got := EqualPaths(c.a, c.b); got != c.equal {
+			t.Errorf("case %d EqualPaths(%q, %q) want %t, got %t", i, c.a, c.b, c.equal, got)
+		}
+		if got := EqualPaths(c.b, c.a); got != c.equal {
+			t.Errorf("case %d EqualPaths(%q, %q) want %t, got %t", i, c.b, c.a, c.equal, got)
+		}
+	}
+}
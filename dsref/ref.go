@@ -12,6 +12,10 @@ type Ref struct {
 	Name string `json:"name,omitempty"`
 	// Content-addressed path for this dataset
 	Path string `json:"path,omitempty"`
+	// Revision selects a version relative to the version the rest of the
+	// reference points to, eg: "~2" or "@{2020-06-01}". Resolvers that select
+	// the revision set Path to the selected version & clear Revision
+	Revision string `json:"revision,omitempty"`
 }
 
 // Alias returns the alias components of a Ref as a string
@@ -41,12 +45,12 @@ func (r Ref) String() (s string) {
 	if r.Path != "" {
 		s += r.Path
 	}
-	return s
+	return s + r.Revision
 }
 
 // IsEmpty returns whether the reference is empty
 func (r Ref) IsEmpty() bool {
-	return r.InitID == "" && r.Username == "" && r.ProfileID == "" && r.Name == "" && r.Path == ""
+	return r.InitID == "" && r.Username == "" && r.ProfileID == "" && r.Name == "" && r.Path == "" && r.Revision == ""
 }
 
 // Complete returns true if all fields are populated
@@ -60,7 +64,8 @@ func (r Ref) Equals(t Ref) bool {
 		r.Username == t.Username &&
 		r.ProfileID == t.ProfileID &&
 		r.Name == t.Name &&
-		r.Path == t.Path
+		r.Path == t.Path &&
+		r.Revision == t.Revision
 }
 
 // Copy duplicates a reference
@@ -71,6 +76,7 @@ func (r Ref) Copy() Ref {
 		ProfileID: r.ProfileID,
 		Name:      r.Name,
 		Path:      r.Path,
+		Revision:  r.Revision,
 	}
 }
 
@@ -0,0 +1,181 @@
+package dsref
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Revision selects a dataset version relative to another version of the same
+// history. Revisions are written after a reference, borrowing from git
+// revision syntax:
+//
+//  <revision> = [ '@' <tagName> | '@{' <time> '}' ] { '~' [ <number> ] | '^' }
+//
+// Some examples of references with revisions:
+//     me/dataset~2              two versions before the latest version
+//     me/dataset^^              also two versions before the latest version
+//     me/dataset@v2.1           the version tagged "v2.1"
+//     me/dataset@v2.1~1         the version before the version tagged "v2.1"
+//     me/dataset@{2020-06-01}   the latest version saved on or before June 1st, 2020
+//     me/dataset@/ipfs/QmHash~1 the version before /ipfs/QmHash
+//
+// Times are either dates, which select through the end of that day in UTC, or
+// RFC3339 timestamps
+type Revision struct {
+	// Tag names the version to start from
+	Tag string
+	// Time selects the latest version saved at or before a point in time
+	Time time.Time
+	// Ancestor counts versions back from the starting version
+	Ancestor int
+}
+
+const (
+	revisionDateLayout = "2006-01-02"
+	tagName            = `[a-zA-Z0-9][\w.-]*`
+)
+
+var (
+	tagNameCheck  = regexp.MustCompile(`^` + tagName + `$`)
+	profileIDLike = regexp.MustCompile(`^` + b58Id + `$`)
+	revisionStart = regexp.MustCompile(`^@(` + tagName + `|\{[^}]*\})`)
+	ancestryPart  = regexp.MustCompile(`^(~[0-9]*|\^)`)
+
+	// ErrRevisionNotFound is returned when a revision doesn't select a version
+	// in a dataset history
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// IsValidTagName returns whether a string can be used to name a version.
+// Names that look like profile IDs are reserved, "me/ds@QmProfileID" would
+// be ambiguous
+func IsValidTagName(name string) bool {
+	return tagNameCheck.MatchString(name) && !profileIDLike.MatchString(name)
+}
+
+// ParseRevision parses the revision portion of a reference, as stored in
+// Ref.Revision
+func ParseRevision(str string) (Revision, error) {
+	rev := Revision{}
+	remain, err := parseRevision(str, &rev, true)
+	if err != nil {
+		return rev, err
+	}
+	if remain != "" {
+		return rev, NewParseError("unexpected character in revision %q: '%c'", str, remain[0])
+	}
+	return rev, nil
+}
+
+// parseRevision consumes a revision from the front of a string, returning
+// the unparsed remainder. allowStart controls if a tag or time is permitted
+func parseRevision(text string, rev *Revision, allowStart bool) (string, error) {
+	if m := revisionStart.FindStringSubmatch(text); m != nil {
+		if !allowStart {
+			return text, NewParseError("revision can't name a tag or time after a path")
+		}
+		if strings.HasPrefix(m[1], "{") {
+			t, err := parseRevisionTime(strings.TrimSuffix(strings.TrimPrefix(m[1], "{"), "}"))
+			if err != nil {
+				return text, err
+			}
+			rev.Time = t
+		} else if profileIDLike.MatchString(m[1]) {
+			return text, NewParseError("%q looks like a profile ID, not a tag. a profile ID must be followed by a path", m[1])
+		} else {
+			rev.Tag = m[1]
+		}
+		text = text[len(m[0]):]
+	}
+
+	for {
+		m := ancestryPart.FindString(text)
+		if m == "" {
+			break
+		}
+		if m == "^" || m == "~" {
+			rev.Ancestor++
+		} else {
+			n, err := strconv.Atoi(m[1:])
+			if err != nil {
+				return text, NewParseError("invalid ancestor count %q", m)
+			}
+			rev.Ancestor += n
+		}
+		text = text[len(m):]
+	}
+	return text, nil
+}
+
+func parseRevisionTime(str string) (time.Time, error) {
+	if t, err := time.Parse(revisionDateLayout, str); err == nil {
+		// dates select through the end of the day
+		return t.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return t, NewParseError("invalid revision time %q, must be a date (YYYY-MM-DD) or RFC3339 timestamp", str)
+	}
+	return t, nil
+}
+
+// IsEmpty returns whether the revision selects anything
+func (r Revision) IsEmpty() bool {
+	return r.Tag == "" && r.Time.IsZero() && r.Ancestor == 0
+}
+
+// String renders a revision in reference syntax
+func (r Revision) String() string {
+	s := ""
+	if r.Tag != "" {
+		s = "@" + r.Tag
+	} else if !r.Time.IsZero() {
+		s = fmt.Sprintf("@{%s}", r.Time.Format(time.RFC3339Nano))
+	}
+	if r.Ancestor > 0 {
+		s += fmt.Sprintf("~%d", r.Ancestor)
+	}
+	return s
+}
+
+// Select picks the path of the version a revision refers to from a history
+// of versions ordered newest to oldest. from is the path ancestors are
+// counted from when the revision doesn't name a tag or time, usually the
+// latest version. tags maps tag names to version paths
+func (r Revision) Select(history []VersionInfo, from string, tags map[string]string) (string, error) {
+	start := from
+	switch {
+	case r.Tag != "":
+		path, ok := tags[r.Tag]
+		if !ok {
+			return "", fmt.Errorf("%w: unknown tag %q", ErrRevisionNotFound, r.Tag)
+		}
+		start = path
+	case !r.Time.IsZero():
+		start = ""
+		for _, vi := range history {
+			if !vi.CommitTime.After(r.Time) {
+				start = vi.Path
+				break
+			}
+		}
+		if start == "" {
+			return "", fmt.Errorf("%w: no versions saved before %s", ErrRevisionNotFound, r.Time.Format(time.RFC3339))
+		}
+	}
+
+	for i, vi := range history {
+		if vi.Path != start {
+			continue
+		}
+		if i+r.Ancestor >= len(history) {
+			return "", fmt.Errorf("%w: %s~%d goes back further than the %d versions in history", ErrRevisionNotFound, start, r.Ancestor, len(history))
+		}
+		return history[i+r.Ancestor].Path, nil
+	}
+	return "", fmt.Errorf("%w: version %s isn't in history", ErrRevisionNotFound, start)
+}
@@ -0,0 +1,141 @@
+package dsref
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRevision(t *testing.T) {
+	good := []struct {
+		text   string
+		expect Revision
+		str    string
+	}{
+		{"", Revision{}, ""},
+		{"~", Revision{Ancestor: 1}, "~1"},
+		{"~3", Revision{Ancestor: 3}, "~3"},
+		{"^^~2", Revision{Ancestor: 4}, "~4"},
+		{"@v1.0", Revision{Tag: "v1.0"}, "@v1.0"},
+		{"@release-2~1", Revision{Tag: "release-2", Ancestor: 1}, "@release-2~1"},
+		{"@{2020-06-01T12:00:00Z}^", Revision{Time: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC), Ancestor: 1}, "@{2020-06-01T12:00:00Z}~1"},
+		{"@{2020-06-01}", Revision{Time: time.Date(2020, 6, 1, 23, 59, 59, 999999999, time.UTC)}, "@{2020-06-01T23:59:59.999999999Z}"},
+	}
+	for _, c := range good {
+		got, err := ParseRevision(c.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.text, err)
+			continue
+		}
+		if got.Tag != c.expect.Tag || !got.Time.Equal(c.expect.Time) || got.Ancestor != c.expect.Ancestor {
+			t.Errorf("%q: result mismatch. want: %#v, got: %#v", c.text, c.expect, got)
+		}
+		if got.String() != c.str {
+			t.Errorf("%q: string mismatch. want: %q, got: %q", c.text, c.str, got.String())
+		}
+	}
+
+	bad := []string{"~a", "@", "@{yesterday}", "@.tag", "~1@v1", "@QmFirst"}
+	for _, text := range bad {
+		if _, err := ParseRevision(text); err == nil {
+			t.Errorf("%q: expected error", text)
+		}
+	}
+}
+
+func TestParseWithRevision(t *testing.T) {
+	good := []struct {
+		text   string
+		expect Ref
+	}{
+		{"abc/my_dataset~2", Ref{Username: "abc", Name: "my_dataset", Revision: "~2"}},
+		{"abc/my_dataset^", Ref{Username: "abc", Name: "my_dataset", Revision: "~1"}},
+		{"abc/my_dataset@v1.0", Ref{Username: "abc", Name: "my_dataset", Revision: "@v1.0"}},
+		{"abc/my_dataset@QmFirst/ipfs/QmSecond~1", Ref{Username: "abc", Name: "my_dataset", ProfileID: "QmFirst", Path: "/ipfs/QmSecond", Revision: "~1"}},
+		{"@/ipfs/QmSecond^^", Ref{Path: "/ipfs/QmSecond", Revision: "~2"}},
+		// any name after '@' that isn't a path is a tag
+		{"abc/my_dataset@abc", Ref{Username: "abc", Name: "my_dataset", Revision: "@abc"}},
+	}
+	for _, c := range good {
+		ref, err := Parse(c.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.text, err)
+			continue
+		}
+		if !ref.Equals(c.expect) {
+			t.Errorf("%q: mismatch. want: %s, got: %s", c.text, c.expect, ref)
+		}
+	}
+
+	bad := []string{
+		"abc/my_dataset@/ipfs/QmSecond@v1.0",
+		"abc/my_dataset~x",
+		"abc/my_dataset@{bad}",
+		// profile IDs must be followed by a path, they aren't tags
+		"abc/my_dataset@QmFirst",
+		"abc/my_dataset@QmYCvbfNbCwFR45HiNP45rwJgvatpiW38D961L5qAhUM5Y",
+		// tags only follow a dataset name
+		"@abc",
+	}
+	for _, text := range bad {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("%q: expected error", text)
+		}
+	}
+}
+
+func TestRevisionSelect(t *testing.T) {
+	history := []VersionInfo{
+		{Path: "/ipfs/QmThree", CommitTime: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "/ipfs/QmTwo", CommitTime: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Path: "/ipfs/QmOne", CommitTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	tags := map[string]string{"v1": "/ipfs/QmTwo"}
+
+	good := []struct {
+		revision string
+		from     string
+		expect   string
+	}{
+		{"", "/ipfs/QmThree", "/ipfs/QmThree"},
+		{"~2", "/ipfs/QmThree", "/ipfs/QmOne"},
+		{"^", "/ipfs/QmTwo", "/ipfs/QmOne"},
+		{"@v1", "/ipfs/QmThree", "/ipfs/QmTwo"},
+		{"@v1~1", "/ipfs/QmThree", "/ipfs/QmOne"},
+		{"@{2020-02-15}", "/ipfs/QmThree", "/ipfs/QmTwo"},
+		{"@{2020-03-01}~1", "/ipfs/QmThree", "/ipfs/QmTwo"},
+	}
+	for _, c := range good {
+		rev, err := ParseRevision(c.revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := rev.Select(history, c.from, tags)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.revision, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%q: result mismatch. want: %q, got: %q", c.revision, c.expect, got)
+		}
+	}
+
+	bad := []struct {
+		revision string
+		from     string
+	}{
+		{"~3", "/ipfs/QmThree"},
+		{"@v2", "/ipfs/QmThree"},
+		{"@{2019-12-31}", "/ipfs/QmThree"},
+		{"~1", "/ipfs/QmMissing"},
+	}
+	for _, c := range bad {
+		rev, err := ParseRevision(c.revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rev.Select(history, c.from, tags); !errors.Is(err, ErrRevisionNotFound) {
+			t.Errorf("%q from %q: expected error %q, got: %v", c.revision, c.from, ErrRevisionNotFound, err)
+		}
+	}
+}
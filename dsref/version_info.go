@@ -41,6 +41,9 @@ type VersionInfo struct {
 	// If true, this reference doesn't exist locally. Only makes sense if path is set, as this
 	// flag refers to specific versions, not to entire dataset histories.
 	Foreign bool `json:"foreign,omitempty"`
+	// If true, this version is stored locally, but is missing the data of some
+	// components, which are fetched from a remote when needed
+	Sparse bool `json:"sparse,omitempty"`
 	//
 	// Meta fields
 	//
@@ -28,6 +28,10 @@ const (
 	ETRenamedFolder = Type("watchfs:RenamedFolder")
 	// ETRemovedFolder is the event for removing a folder
 	ETRemovedFolder = Type("watchfs:RemovedFolder")
+	// ETWorkingDirStatusChanged is the event for a change to the status of a
+	// linked working directory, published once a burst of file events settles
+	// payload is a WorkingDirStatus
+	ETWorkingDirStatusChanged = Type("watchfs:WorkingDirStatusChanged")
 )
 
 // WatchfsChange represents events for filesystem changes
@@ -38,3 +42,41 @@ type WatchfsChange struct {
 	Destination string    `json:"destination"`
 	Time        time.Time `json:"time"`
 }
+
+// WorkingDirStatus is the recalculated status of a linked working directory
+type WorkingDirStatus struct {
+	Username string `json:"username"`
+	Dsname   string `json:"dsName"`
+	FSIPath  string `json:"fsiPath"`
+	// Status is a list of fsi.StatusItem. the event package can't depend on
+	// fsi, which publishes events
+	Status interface{} `json:"status"`
+	Time   time.Time   `json:"time"`
+}
+
+const (
+	// ETFSIAutosaved is the event for a new version of a dataset saved
+	// automatically from a linked working directory. payload is an FSIAutosave
+	ETFSIAutosaved = Type("fsi:Autosaved")
+	// ETFSIAutosaveSkipped is the event for an automatic save that didn't
+	// happen because the working directory isn't valid or has no changes.
+	// payload is an FSIAutosave
+	ETFSIAutosaveSkipped = Type("fsi:AutosaveSkipped")
+	// ETFSIAutosaveFailed is the event for an automatic save that errored.
+	// payload is an FSIAutosave
+	ETFSIAutosaveFailed = Type("fsi:AutosaveFailed")
+)
+
+// FSIAutosave describes an automatic save of a linked working directory
+type FSIAutosave struct {
+	Username string `json:"username"`
+	Dsname   string `json:"dsName"`
+	FSIPath  string `json:"fsiPath"`
+	// Path of the saved version, only set for successful saves
+	Path string `json:"path,omitempty"`
+	// Title is the generated commit title of the saved version
+	Title string `json:"title,omitempty"`
+	// Reason a save was skipped or failed
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
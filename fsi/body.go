@@ -9,6 +9,7 @@ import (
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/component"
+	"github.com/qri-io/qri/base/dsfs"
 )
 
 // GetBody is an FSI version of base.ReadBody
@@ -25,6 +26,9 @@ func GetBody(dirPath string, format dataset.DataFormat, fcfg dataset.FormatConfi
 	}
 
 	bodyComponent := components.Base().GetSubcomponent("body")
+	if dsfs.IsPartitionDir(bodyComponent.Base().SourceFile) {
+		return getPartitionedBody(components, bodyComponent.Base(), format, fcfg, offset, limit, all)
+	}
 	f, err := os.Open(bodyComponent.Base().SourceFile)
 	if err != nil {
 		return nil, err
@@ -81,3 +85,44 @@ func GetBody(dirPath string, format dataset.DataFormat, fcfg dataset.FormatConfi
 
 	return base.ConvertBodyFile(file, structure, st, limit, offset, all)
 }
+
+// getPartitionedBody reads body data from a directory of body partitions
+func getPartitionedBody(components component.Component, body *component.BaseComponent, format dataset.DataFormat, fcfg dataset.FormatConfig, offset, limit int, all bool) ([]byte, error) {
+	structure := &dataset.Structure{Format: body.Format}
+	if stComponent := components.Base().GetSubcomponent("structure"); stComponent != nil {
+		stComponent.LoadAndFill(nil)
+		if comp, ok := stComponent.(*component.StructureComponent); ok && comp.Value != nil {
+			structure = comp.Value
+		}
+	}
+	if structure.Schema == nil {
+		entries, err := component.OpenPartitionEntryReader(body.SourceFile, body.Format)
+		if err != nil {
+			return nil, err
+		}
+		detected := entries.Structure()
+		structure.Schema = detected.Schema
+		if structure.FormatConfig == nil {
+			structure.FormatConfig = detected.FormatConfig
+		}
+	}
+
+	pbf, err := dsfs.OpenPartitionDir(body.SourceFile)
+	if err != nil {
+		return nil, err
+	}
+	file := pbf.WithStructure(structure)
+	defer file.Close()
+
+	st := &dataset.Structure{}
+	assign := &dataset.Structure{
+		Format: format.String(),
+		Schema: structure.Schema,
+	}
+	if fcfg != nil {
+		assign.FormatConfig = fcfg.Map()
+	}
+	st.Assign(structure, assign)
+
+	return base.ConvertBodyFile(file, structure, st, limit, offset, all)
+}
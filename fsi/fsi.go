@@ -78,14 +78,60 @@ type FSI struct {
 	// repository for resolving dataset names
 	repo repo.Repo
 	pub  event.Publisher
+	// last calculated status of linked directories
+	statusCache *statusCache
+	// changes set aside from linked directories
+	stashes *stashStore
 }
 
-// NewFSI creates an FSI instance from a path to a links flatbuffer file
-func NewFSI(r repo.Repo, pub event.Publisher) *FSI {
-	if pub == nil {
-		pub = event.NilBus
+// NewFSI creates an FSI instance. Status results & stashes are persisted to
+// files in repoPath, an empty repoPath keeps them in memory. Watchfs events
+// published on bus invalidate cached status & keep links up to date when
+// linked directories are moved
+func NewFSI(r repo.Repo, bus event.Bus, repoPath string) *FSI {
+	if bus == nil {
+		bus = event.NilBus
 	}
-	return &FSI{repo: r, pub: pub}
+	statusCacheFilename, stashFilename := "", ""
+	if repoPath != "" {
+		statusCacheFilename = StatusCachePath(repoPath)
+		stashFilename = StashPath(repoPath)
+	}
+	fsi := &FSI{
+		repo:        r,
+		pub:         bus,
+		statusCache: newStatusCache(statusCacheFilename),
+		stashes:     newStashStore(stashFilename),
+	}
+	bus.Subscribe(fsi.handleWatchfsEvent,
+		event.ETCreatedNewFile,
+		event.ETModifiedFile,
+		event.ETDeletedFile,
+		event.ETRenamedFolder,
+	)
+	return fsi
+}
+
+func (fsi *FSI) handleWatchfsEvent(_ context.Context, t event.Type, payload interface{}) error {
+	change, ok := payload.(event.WatchfsChange)
+	if !ok {
+		return nil
+	}
+
+	switch t {
+	case event.ETCreatedNewFile, event.ETModifiedFile, event.ETDeletedFile:
+		log.Debugf("invalidating status for %s", change.Source)
+		fsi.statusCache.invalidate(change.Source)
+	case event.ETRenamedFolder:
+		ref, ok := GetLinkedFilesysRef(change.Destination)
+		if !ok {
+			return nil
+		}
+		if _, err := fsi.ModifyLinkDirectory(change.Destination, ref); err != nil {
+			log.Errorf("updating link for moved directory %q: %s", change.Destination, err)
+		}
+	}
+	return nil
 }
 
 // ResolvedPath sets the Path value of a reference to the filesystem integration
@@ -228,7 +274,6 @@ func (fsi *FSI) CreateLink(dirPath string, ref dsref.Ref) (vi *dsref.VersionInfo
 // ModifyLinkDirectory changes the FSIPath in the repo so that it is linked to the directory. Does
 // not affect the .qri-ref linkfile in the working directory. Called when the command-line
 // interface or filesystem watcher detects that a working folder has been moved.
-// TODO(dlong): Add a filesystem watcher that behaves as described
 // TODO(dlong): Perhaps add a `qri mv` command that explicitly changes a working directory location
 func (fsi *FSI) ModifyLinkDirectory(dirPath string, ref dsref.Ref) (*dsref.VersionInfo, error) {
 	vi, err := repo.GetVersionInfoShim(fsi.repo, ref)
@@ -378,7 +423,7 @@ func DeleteComponentFiles(dir string) error {
 		if comp == nil {
 			continue
 		}
-		err = os.Remove(comp.Base().SourceFile)
+		err = component.RemoveSourceFile(comp.Base().SourceFile)
 		if err != nil {
 			log.Errorf("deleting file %q, error: %s", comp.Base().SourceFile, err)
 			return err
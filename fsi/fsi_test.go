@@ -1,6 +1,7 @@
 package fsi
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
 	"github.com/qri-io/qri/repo"
 	testrepo "github.com/qri-io/qri/repo/test"
 )
@@ -74,7 +76,7 @@ func TestCreateLink(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	vi, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/movies"))
 	if err != nil {
 		t.Fatalf(err.Error())
@@ -122,7 +124,7 @@ func TestCreateLinkTwice(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/cities"))
 	if err != nil {
 		t.Fatalf(err.Error())
@@ -175,7 +177,7 @@ func TestCreateLinkAlreadyLinked(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/cities"))
 	if err != nil {
 		t.Fatalf(err.Error())
@@ -196,7 +198,7 @@ func TestCreateLinkAgainOnceQriRefRemoved(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/cities"))
 	if err != nil {
 		t.Fatalf(err.Error())
@@ -238,7 +240,7 @@ func TestModifyLinkReference(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/cities"))
 	if err != nil {
 		t.Fatal(err)
@@ -286,7 +288,7 @@ func TestModifyLinkDirectory(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/movies"))
 	if err != nil {
 		t.Fatal(err)
@@ -320,11 +322,46 @@ func TestModifyLinkDirectory(t *testing.T) {
 	}
 }
 
+func TestMovedDirectoryUpdatesLink(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	paths := NewTmpPaths()
+	defer paths.Close()
+
+	bus := event.NewBus(ctx)
+	fsi := NewFSI(paths.testRepo, bus, "")
+	if _, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/movies")); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(paths.secondDir, "moved")
+	if err := os.Rename(paths.firstDir, dest); err != nil {
+		t.Fatal(err)
+	}
+	err := bus.Publish(ctx, event.ETRenamedFolder, event.WatchfsChange{
+		Username:    "peer",
+		Dsname:      "movies",
+		Source:      paths.firstDir,
+		Destination: dest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vi, err := repo.GetVersionInfoShim(paths.testRepo, dsref.MustParse("peer/movies"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vi.FSIPath != dest {
+		t.Errorf("expected link to follow moved directory. want: %q, got: %q", dest, vi.FSIPath)
+	}
+}
+
 func TestUnlink(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, _, err := fsi.CreateLink(paths.firstDir, dsref.MustParse("peer/cities"))
 	if err != nil {
 		t.Fatalf(err.Error())
@@ -0,0 +1,147 @@
+package fsi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+const (
+	// HooksDir is the location of hook scripts, relative to a linked directory
+	HooksDir = ".qri/hooks"
+	// HookPreSave runs before saving a linked directory, and can prevent the
+	// save by failing
+	HookPreSave = "pre-save"
+	// HookPostSave runs after saving a linked directory
+	HookPostSave = "post-save"
+)
+
+// HookError is returned when a hook fails
+type HookError struct {
+	Hook    string
+	Message string
+}
+
+// Error implements the error interface
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook failed: %s", e.Hook, e.Message)
+}
+
+// RunHook runs the named hook for a linked directory, if one exists. Hooks
+// are either an executable file named for the hook, or a starlark script with
+// the same name and a ".star" extension. If both exist the executable runs
+// first.
+//
+// Executables are run from the linked directory, receive the dataset as JSON
+// on stdin, and fail by exiting with a non-zero status. Output of a failed
+// executable is the failure message. Starlark scripts define a function named
+// for the hook with underscores in place of dashes (eg: pre_save), which is
+// called with the dataset as a dictionary, and fail by calling fail(message)
+func RunHook(ctx context.Context, dir, hook string, ds *dataset.Dataset) error {
+	base := filepath.Join(dir, HooksDir, hook)
+	executable := isExecutable(base)
+	_, err := os.Stat(base + ".star")
+	script := err == nil
+	if !executable && !script {
+		return nil
+	}
+
+	data, err := json.Marshal(ds)
+	if err != nil {
+		return err
+	}
+
+	if executable {
+		log.Debugf("running %s hook %q", hook, base)
+		if err := runExecutableHook(ctx, dir, hook, base, data); err != nil {
+			return err
+		}
+	}
+	if script {
+		log.Debugf("running %s hook %q", hook, base+".star")
+		if err := runStarlarkHook(hook, base+".star", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	return fi.Mode()&0111 != 0
+}
+
+func runExecutableHook(ctx context.Context, dir, hook, path string, data []byte) error {
+	out := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("running %s hook: %w", hook, err)
+		}
+		msg := strings.TrimSpace(out.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return &HookError{Hook: hook, Message: msg}
+	}
+	return nil
+}
+
+func runStarlarkHook(hook, path string, data []byte) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	dsv, err := util.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	thread := &starlark.Thread{
+		Name: hook,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Infof("%s hook: %s", hook, msg)
+		},
+	}
+	globals, err := starlark.ExecFile(thread, path, src, nil)
+	if err != nil {
+		return &HookError{Hook: hook, Message: err.Error()}
+	}
+	funcName := strings.Replace(hook, "-", "_", -1)
+	fn, ok := globals[funcName].(*starlark.Function)
+	if !ok {
+		return &HookError{Hook: hook, Message: fmt.Sprintf("script doesn't define a %s function", funcName)}
+	}
+	if _, err := starlark.Call(thread, fn, starlark.Tuple{dsv}, nil); err != nil {
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) {
+			return &HookError{Hook: hook, Message: strings.TrimPrefix(evalErr.Msg, "fail: ")}
+		}
+		return &HookError{Hook: hook, Message: err.Error()}
+	}
+	return nil
+}
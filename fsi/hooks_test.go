@@ -0,0 +1,87 @@
+package fsi
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestRunHook(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "fsi_hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ds := &dataset.Dataset{
+		Peername: "me",
+		Name:     "hooked",
+		Meta:     &dataset.Meta{Title: "hooked"},
+	}
+
+	// no hooks is a no-op
+	if err := RunHook(ctx, dir, HookPreSave, ds); err != nil {
+		t.Fatalf("expected no hooks to succeed, got: %s", err)
+	}
+
+	hooksDir := filepath.Join(dir, HooksDir)
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	script := filepath.Join(hooksDir, HookPreSave+".star")
+	if err := ioutil.WriteFile(script, []byte(`
+def pre_save(ds):
+  if not ds.get("readme"):
+    fail("datasets need a readme")
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = RunHook(ctx, dir, HookPreSave, ds)
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("expected starlark hook to fail with a HookError, got: %v", err)
+	}
+	if hookErr.Message != "datasets need a readme" {
+		t.Errorf("unexpected message. want %q, got %q", "datasets need a readme", hookErr.Message)
+	}
+
+	ds.Readme = &dataset.Readme{Format: "md", ScriptBytes: []byte("# hooked")}
+	if err := RunHook(ctx, dir, HookPreSave, ds); err != nil {
+		t.Errorf("expected starlark hook to pass, got: %s", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	exe := filepath.Join(hooksDir, HookPostSave)
+	if err := ioutil.WriteFile(exe, []byte(`#!/bin/sh
+grep -q '"name":"hooked"' || { echo "unexpected dataset"; exit 1; }
+echo "post-save ran" > post_save_ran.txt
+`), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := RunHook(ctx, dir, HookPostSave, ds); err != nil {
+		t.Fatalf("expected executable hook to pass, got: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "post_save_ran.txt")); err != nil {
+		t.Errorf("expected executable hook to run in the linked directory: %s", err)
+	}
+
+	ds.Name = "other"
+	err = RunHook(ctx, dir, HookPostSave, ds)
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("expected executable hook to fail with a HookError, got: %v", err)
+	}
+	if hookErr.Message != "unexpected dataset" {
+		t.Errorf("unexpected message. want %q, got %q", "unexpected dataset", hookErr.Message)
+	}
+}
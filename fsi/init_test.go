@@ -11,7 +11,7 @@ func TestInitDataset(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 
 	_, err := fsi.InitDataset(InitParams{
 		Name:      "test_ds",
@@ -111,7 +111,7 @@ func DeleteDatasetFiles(dirPath string) error {
 		}
 		// ignore not found errors. multiple components can be specified in the
 		// same dataset file, creating multiple remove attempts to the same path
-		if err := os.Remove(subc.Base().SourceFile); err != nil && !os.IsNotExist(err) {
+		if err := component.RemoveSourceFile(subc.Base().SourceFile); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,439 @@
+package fsi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/component"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/repo"
+)
+
+var (
+	// ErrNoStash indicates a requested stash doesn't exist
+	ErrNoStash = fmt.Errorf("stash not found")
+	// ErrNothingToStash indicates a linked directory has no changes to stash
+	ErrNothingToStash = fmt.Errorf("no changes to stash")
+)
+
+// StashPath returns the standard path to the stash index file for a given
+// file-system repo location. The index lists stashes, stashed file contents
+// are kept in the repo filesystem
+func StashPath(repoPath string) string {
+	return filepath.Join(repoPath, "fsi_stash.json")
+}
+
+// Stash is a set of changes to a linked directory, set aside so the directory
+// can be restored to the version it's linked to
+type Stash struct {
+	// linked directory changes were made in
+	FSIPath string `json:"fsiPath"`
+	// dataset the directory is linked to
+	Ref string `json:"ref"`
+	// path of the version changes were made against
+	HeadPath string    `json:"headPath"`
+	Message  string    `json:"message,omitempty"`
+	Time     time.Time `json:"time"`
+	// stashed components
+	Changes []StashChange `json:"changes"`
+}
+
+// Components lists the names of stashed components
+func (s *Stash) Components() []string {
+	names := make([]string, len(s.Changes))
+	for i, ch := range s.Changes {
+		names[i] = ch.Component
+	}
+	return names
+}
+
+// StashChange is a stashed change to a single component
+type StashChange struct {
+	Component string `json:"component"`
+	// status of the component when stashed
+	Type string `json:"type"`
+	// name of the component file relative to the linked directory. removed
+	// components have no file
+	Filename string `json:"filename,omitempty"`
+	// path to the contents of the component file in the repo filesystem
+	Path string `json:"path,omitempty"`
+}
+
+// StashConflictError is returned when applying a stash would overwrite
+// changes
+type StashConflictError struct {
+	// components with conflicting changes
+	Components []string
+}
+
+// Error implements the error interface
+func (e *StashConflictError) Error() string {
+	return fmt.Sprintf("stash conflicts with changes to %s", strings.Join(e.Components, ", "))
+}
+
+// Stash sets aside changes in a linked directory, restoring changed
+// components to the version the directory is linked to
+func (fsi *FSI) Stash(ctx context.Context, dir, message string) (*Stash, error) {
+	ref, ok := GetLinkedFilesysRef(dir)
+	if !ok {
+		return nil, fmt.Errorf("not a linked directory")
+	}
+	vi, err := repo.GetVersionInfoShim(fsi.repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := fsi.Status(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Stash{
+		FSIPath:  dir,
+		Ref:      ref.Human(),
+		HeadPath: vi.Path,
+		Message:  message,
+		Time:     time.Now(),
+	}
+	for _, si := range status {
+		if si.Type == STUnmodified {
+			continue
+		}
+		ch := StashChange{Component: si.Component, Type: si.Type}
+		if filepath.Base(si.SourceFile) == "dataset.json" {
+			// dataset files hold many components, which can't be restored
+			// individually
+			return nil, fmt.Errorf("can't stash %s, components in dataset.json can't be stashed", si.Component)
+		}
+		if dsfs.IsPartitionDir(si.SourceFile) {
+			return nil, fmt.Errorf("can't stash %s, partitioned bodies can't be stashed", si.Component)
+		}
+		if si.SourceFile != "" {
+			if ch.Filename, err = filepath.Rel(dir, si.SourceFile); err != nil {
+				return nil, err
+			}
+		}
+		s.Changes = append(s.Changes, ch)
+	}
+	if len(s.Changes) == 0 {
+		return nil, ErrNothingToStash
+	}
+
+	for i, ch := range s.Changes {
+		if ch.Filename == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, ch.Filename))
+		if err != nil {
+			return nil, err
+		}
+		if s.Changes[i].Path, err = fsi.repo.Filesystem().DefaultWriteFS().Put(ctx, qfs.NewMemfileBytes(ch.Filename, data)); err != nil {
+			return nil, fmt.Errorf("storing stashed %s: %w", ch.Component, err)
+		}
+	}
+
+	fsi.stashes.push(s)
+
+	head, err := fsi.versionComponents(ctx, vi.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range s.Changes {
+		if ch.Filename != "" {
+			if err := component.RemoveSourceFile(filepath.Join(dir, ch.Filename)); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := WriteComponent(head, ch.Component, dir); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Stashes lists stashed changes for a linked directory, most recent first
+func (fsi *FSI) Stashes(dir string) []*Stash {
+	return fsi.stashes.list(dir)
+}
+
+// DropStash removes a stash without applying it. index 0 is the most recent
+// stash
+func (fsi *FSI) DropStash(ctx context.Context, dir string, index int) (*Stash, error) {
+	return fsi.removeStash(ctx, dir, index)
+}
+
+// removeStash drops a stash from the index, deleting stashed file contents
+// that no other stash holds
+func (fsi *FSI) removeStash(ctx context.Context, dir string, index int) (*Stash, error) {
+	s, orphaned, err := fsi.stashes.remove(dir, index)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range orphaned {
+		if err := fsi.repo.Filesystem().Delete(ctx, path); err != nil {
+			log.Debugf("deleting stashed file %q: %s", path, err)
+		}
+	}
+	return s, nil
+}
+
+// PopStash applies & removes a stash. A stash conflicts with changes in the
+// working directory to the same components, and with changes to the same
+// components committed since the stash was made. Conflicting stashes are only
+// applied if force is true
+func (fsi *FSI) PopStash(ctx context.Context, dir string, index int, force bool) (*Stash, error) {
+	s, err := fsi.stashes.get(dir, index)
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		conflicts, err := fsi.stashConflicts(ctx, dir, s)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			return nil, &StashConflictError{Components: conflicts}
+		}
+	}
+
+	working, err := component.ListDirectoryComponents(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range s.Changes {
+		existing := working.Base().GetSubcomponent(ch.Component)
+		if ch.Filename == "" {
+			if err := DeleteComponent(working, ch.Component, dir); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// component may currently be stored in a file with a different name
+		path := filepath.Join(dir, ch.Filename)
+		if existing != nil && existing.Base().SourceFile != path {
+			if err := component.RemoveSourceFile(existing.Base().SourceFile); err != nil {
+				return nil, err
+			}
+		}
+		data, err := fsi.readStashedFile(ctx, ch.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading stashed %s: %w", ch.Component, err)
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return fsi.removeStash(ctx, dir, index)
+}
+
+func (fsi *FSI) readStashedFile(ctx context.Context, path string) ([]byte, error) {
+	f, err := fsi.repo.Filesystem().Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// stashConflicts lists stashed components that have changed in the working
+// directory or the linked version since the stash was made
+func (fsi *FSI) stashConflicts(ctx context.Context, dir string, s *Stash) ([]string, error) {
+	stashed := map[string]bool{}
+	for _, name := range s.Components() {
+		stashed[name] = true
+	}
+	conflicts := map[string]bool{}
+
+	status, err := fsi.Status(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, si := range status {
+		if si.Type != STUnmodified && stashed[si.Component] {
+			conflicts[si.Component] = true
+		}
+	}
+
+	ref, ok := GetLinkedFilesysRef(dir)
+	if !ok {
+		return nil, fmt.Errorf("not a linked directory")
+	}
+	vi, err := repo.GetVersionInfoShim(fsi.repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	if vi.Path != s.HeadPath {
+		prev, err := fsi.versionComponents(ctx, s.HeadPath)
+		if err != nil {
+			return nil, err
+		}
+		next, err := fsi.versionComponents(ctx, vi.Path)
+		if err != nil {
+			return nil, err
+		}
+		changes, err := fsi.CalculateStateTransition(ctx, prev, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range changes {
+			if ch.Type != STUnmodified && stashed[ch.Component] {
+				conflicts[ch.Component] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(conflicts))
+	for name := range conflicts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// versionComponents loads the components of a dataset version. An empty path
+// is an empty dataset
+func (fsi *FSI) versionComponents(ctx context.Context, path string) (component.Component, error) {
+	fs := fsi.repo.Filesystem()
+	ds := &dataset.Dataset{}
+	if path != "" {
+		var err error
+		if ds, err = dsfs.LoadDataset(ctx, fs, path); err != nil {
+			return nil, fmt.Errorf("loading dataset: %w", err)
+		}
+	}
+	comp := component.ConvertDatasetToComponents(ds, fs)
+	comp.Base().RemoveSubcomponent("commit")
+	comp.DropDerivedValues()
+	return comp, nil
+}
+
+// stashStore persists an index of stashes, keyed by directory
+type stashStore struct {
+	lk       sync.Mutex
+	filename string
+	loaded   bool
+	dirs     map[string][]*Stash
+}
+
+func newStashStore(filename string) *stashStore {
+	return &stashStore{filename: filename, dirs: map[string][]*Stash{}}
+}
+
+// load reads the stash index file, if one exists. must be called with the lock held
+func (s *stashStore) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	if s.filename == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(s.filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("reading stashes: %s", err)
+		}
+		return
+	}
+	dirs := map[string][]*Stash{}
+	if err := json.Unmarshal(data, &dirs); err != nil {
+		log.Errorf("decoding stashes: %s", err)
+		return
+	}
+	s.dirs = dirs
+}
+
+// save writes the stash index file. must be called with the lock held
+func (s *stashStore) save() {
+	if s.filename == "" {
+		return
+	}
+	data, err := json.Marshal(s.dirs)
+	if err != nil {
+		log.Errorf("encoding stashes: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(s.filename, data, 0644); err != nil {
+		log.Errorf("writing stashes: %s", err)
+	}
+}
+
+func (s *stashStore) push(st *Stash) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.load()
+	key := cacheKey(st.FSIPath)
+	s.dirs[key] = append([]*Stash{st}, s.dirs[key]...)
+	s.save()
+}
+
+func (s *stashStore) list(dir string) []*Stash {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.load()
+	stashes := s.dirs[cacheKey(dir)]
+	res := make([]*Stash, len(stashes))
+	copy(res, stashes)
+	return res
+}
+
+func (s *stashStore) get(dir string, index int) (*Stash, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.load()
+	stashes := s.dirs[cacheKey(dir)]
+	if index < 0 || index >= len(stashes) {
+		return nil, ErrNoStash
+	}
+	return stashes[index], nil
+}
+
+// remove drops a stash, also returning the paths of stashed files no remaining
+// stash holds. identical files are stored once, so stashes can share paths
+func (s *stashStore) remove(dir string, index int) (*Stash, []string, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.load()
+	key := cacheKey(dir)
+	stashes := s.dirs[key]
+	if index < 0 || index >= len(stashes) {
+		return nil, nil, ErrNoStash
+	}
+	removed := stashes[index]
+	stashes = append(stashes[:index:index], stashes[index+1:]...)
+	if len(stashes) == 0 {
+		delete(s.dirs, key)
+	} else {
+		s.dirs[key] = stashes
+	}
+	s.save()
+
+	held := map[string]bool{}
+	for _, stashes := range s.dirs {
+		for _, st := range stashes {
+			for _, ch := range st.Changes {
+				held[ch.Path] = true
+			}
+		}
+	}
+	var orphaned []string
+	for _, ch := range removed.Changes {
+		if ch.Path != "" && !held[ch.Path] {
+			orphaned = append(orphaned, ch.Path)
+			held[ch.Path] = true
+		}
+	}
+	return removed, orphaned, nil
+}
@@ -58,7 +58,9 @@ func (si StatusItem) MarshalJSON() ([]byte, error) {
 	return json.Marshal(obj)
 }
 
-// Status compares status of the current working directory against the dataset's last version
+// Status compares status of the current working directory against the dataset's last version.
+// Results are cached, only components whose files have changed since the last call are
+// re-read
 func (fsi *FSI) Status(ctx context.Context, dir string) (changes []StatusItem, err error) {
 	fs := fsi.repo.Filesystem()
 	ref, ok := GetLinkedFilesysRef(dir)
@@ -67,23 +69,10 @@ func (fsi *FSI) Status(ctx context.Context, dir string) (changes []StatusItem, e
 		return nil, err
 	}
 
-	var stored *dataset.Dataset
 	vi, err := repo.GetVersionInfoShim(fsi.repo, ref)
 	if err != nil {
 		return nil, err
 	}
-	if vi.Path == "" {
-		// no dataset, compare to an empty ds
-		stored = &dataset.Dataset{}
-	} else {
-		if stored, err = dsfs.LoadDataset(ctx, fs, vi.Path); err != nil {
-			return nil, err
-		}
-	}
-
-	stored.DropDerivedValues()
-	stored.Commit = nil
-	stored.Peername = ""
 
 	working, err := component.ListDirectoryComponents(dir)
 	if err != nil {
@@ -96,12 +85,83 @@ func (fsi *FSI) Status(ctx context.Context, dir string) (changes []StatusItem, e
 		return nil, err
 	}
 
-	// TODO: If in the future we cache mtimes and previous status, we can more lazily read only
-	// some components.
+	// compare file state against the last calculated status to determine which
+	// components need to be recalculated. A nil stale set recalculates everything
+	var stale map[string]bool
+	hashes := fileHasher{}
+	current := componentFileStates(working)
+	cached := fsi.statusCache.get(dir)
+	if cached != nil && cached.VersionPath == vi.Path {
+		stale = staleComponents(cached, current, hashes)
+	} else {
+		cached = nil
+	}
+
+	next := &dirStatus{
+		VersionPath: vi.Path,
+		Files:       map[string]fileState{},
+		Items:       map[string]cachedItem{},
+	}
 
-	prevComps := component.ConvertDatasetToComponents(stored, fs)
-	nextComps := working
-	return fsi.CalculateStateTransition(ctx, prevComps, nextComps)
+	if cached == nil || len(stale) > 0 {
+		var stored *dataset.Dataset
+		if vi.Path == "" {
+			// no dataset, compare to an empty ds
+			stored = &dataset.Dataset{}
+		} else {
+			if stored, err = dsfs.LoadDataset(ctx, fs, vi.Path); err != nil {
+				return nil, err
+			}
+		}
+
+		stored.DropDerivedValues()
+		stored.Commit = nil
+		stored.Peername = ""
+
+		prevComps := component.ConvertDatasetToComponents(stored, fs)
+		nextComps := working
+		if cached != nil {
+			// skip components that haven't changed
+			for _, name := range statusComponentNames() {
+				if !stale[name] {
+					prevComps.Base().RemoveSubcomponent(name)
+					nextComps.Base().RemoveSubcomponent(name)
+				}
+			}
+		}
+
+		calculated, err := fsi.CalculateStateTransition(ctx, prevComps, nextComps)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range statusComponentNames() {
+			if cached == nil || stale[name] {
+				next.Items[name] = cachedItem{}
+			}
+		}
+		for _, it := range calculated {
+			next.Items[it.Component] = cachedItem(it)
+		}
+	}
+
+	for _, name := range statusComponentNames() {
+		if cached != nil && !stale[name] {
+			next.Items[name] = cached.Items[name]
+		}
+		if st, ok := current[name]; ok {
+			if cached != nil && !stale[name] {
+				st.Hash = cached.Files[name].Hash
+			} else {
+				st.Hash = hashes.hash(st.SourceFile)
+			}
+			next.Files[name] = st
+		}
+	}
+
+	if cached == nil || len(stale) > 0 || !sameFileStates(cached.Files, next.Files) {
+		fsi.statusCache.put(dir, next)
+	}
+	return next.statusItems(), nil
 }
 
 // CalculateStateTransition calculates the differences between two versions of a dataset.
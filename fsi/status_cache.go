@@ -0,0 +1,304 @@
+package fsi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qri-io/qri/base/component"
+)
+
+// StatusCachePath returns the standard path to the status cache file for a
+// given file-system repo location
+func StatusCachePath(repoPath string) string {
+	return filepath.Join(repoPath, "fsi_status.json")
+}
+
+// fileState records the state of a component's source file when status was
+// last calculated. components stored in the same file (eg: meta in
+// dataset.json) share state
+type fileState struct {
+	SourceFile string    `json:"sourceFile,omitempty"`
+	Size       int64     `json:"size,omitempty"`
+	Mtime      time.Time `json:"mtime,omitempty"`
+	Hash       string    `json:"hash,omitempty"`
+}
+
+// cachedItem mirrors StatusItem, keeping full mtime precision when persisted
+type cachedItem struct {
+	SourceFile string    `json:"sourceFile"`
+	Component  string    `json:"component"`
+	Type       string    `json:"type"`
+	Message    string    `json:"message"`
+	Mtime      time.Time `json:"mtime"`
+}
+
+// dirStatus is the cached status of a linked directory
+type dirStatus struct {
+	// path of the dataset version status was calculated against
+	VersionPath string `json:"versionPath"`
+	// source file state, keyed by component name
+	Files map[string]fileState `json:"files"`
+	// calculated status, keyed by component name. components that don't
+	// produce a status item have an item with an empty type
+	Items map[string]cachedItem `json:"items"`
+}
+
+// statusItems returns cached status in result order
+func (ds *dirStatus) statusItems() []StatusItem {
+	items := make([]StatusItem, 0, component.NumberPossibleComponents)
+	for _, name := range statusComponentNames() {
+		if it, ok := ds.Items[name]; ok && it.Type != "" {
+			si := StatusItem(it)
+			si.Mtime = si.Mtime.Local()
+			items = append(items, si)
+		}
+	}
+	return items
+}
+
+// statusCache holds the last calculated status of linked directories, so
+// status only needs to re-read components whose files have changed
+type statusCache struct {
+	lk       sync.Mutex
+	filename string
+	loaded   bool
+	dirs     map[string]*dirStatus
+}
+
+func newStatusCache(filename string) *statusCache {
+	return &statusCache{filename: filename, dirs: map[string]*dirStatus{}}
+}
+
+// load reads the cache file, if one exists. must be called with the lock held
+func (c *statusCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	if c.filename == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(c.filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debugf("reading status cache: %s", err)
+		}
+		return
+	}
+	dirs := map[string]*dirStatus{}
+	if err := json.Unmarshal(data, &dirs); err != nil {
+		log.Debugf("decoding status cache: %s", err)
+		return
+	}
+	c.dirs = dirs
+}
+
+// save writes the cache file. must be called with the lock held
+func (c *statusCache) save() {
+	if c.filename == "" {
+		return
+	}
+	data, err := json.Marshal(c.dirs)
+	if err != nil {
+		log.Debugf("encoding status cache: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(c.filename, data, 0644); err != nil {
+		log.Debugf("writing status cache: %s", err)
+	}
+}
+
+func (c *statusCache) get(dir string) *dirStatus {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	c.load()
+	return c.dirs[cacheKey(dir)]
+}
+
+func (c *statusCache) put(dir string, ds *dirStatus) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	c.load()
+	c.dirs[cacheKey(dir)] = ds
+	c.save()
+}
+
+// invalidate marks cached state for components stored in a file as unknown.
+// path may be a file inside a linked directory, or inside a directory a
+// component is stored in, like the partitions of a body. Invalidated
+// components are compared by size & contents the next time status is
+// calculated
+func (c *statusCache) invalidate(path string) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	c.load()
+	path = cacheKey(path)
+
+	// the nearest linked directory holds the file
+	dir := ""
+	for key := range c.dirs {
+		if isWithin(path, key) && len(key) > len(dir) {
+			dir = key
+		}
+	}
+	if dir == "" {
+		return
+	}
+	ds := c.dirs[dir]
+	changed := false
+	for name, fs := range ds.Files {
+		if isWithin(path, fs.SourceFile) {
+			fs.Mtime = time.Time{}
+			ds.Files[name] = fs
+			changed = true
+		}
+	}
+	if changed {
+		c.save()
+	}
+}
+
+// isWithin reports if path is dir, or a path inside dir
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// cacheKey normalizes directory paths, matching the absolute source file paths
+// used by components
+func cacheKey(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// statusComponentNames lists every name that can appear in a status result
+func statusComponentNames() []string {
+	return append([]string{"dataset"}, component.AllSubcomponentNames()...)
+}
+
+// componentFileStates stats the source file of each component. Hashes are
+// not calculated
+func componentFileStates(listed component.Component) map[string]fileState {
+	states := map[string]fileState{}
+	for _, name := range statusComponentNames() {
+		comp := listed.Base().GetSubcomponent(name)
+		if comp == nil || comp.Base().SourceFile == "" {
+			continue
+		}
+		fs := fileState{SourceFile: comp.Base().SourceFile, Mtime: comp.Base().ModTime.Local()}
+		fs.Size = fileSize(fs.SourceFile)
+		states[name] = fs
+	}
+	return states
+}
+
+// staleComponents compares current file states against cached state, returning
+// the set of component names that must be recalculated. Files with changed
+// mtimes but unchanged size & contents are considered fresh
+func staleComponents(cached *dirStatus, current map[string]fileState, hashes fileHasher) map[string]bool {
+	stale := map[string]bool{}
+	for _, name := range statusComponentNames() {
+		prev, hadPrev := cached.Files[name]
+		cur, hasCur := current[name]
+		_, hasItem := cached.Items[name]
+		switch {
+		case hadPrev && !hasItem:
+			// status is missing
+			stale[name] = true
+		case hadPrev != hasCur:
+			stale[name] = true
+		case !hasCur:
+			// component didn't exist before, & still doesn't
+		case prev.SourceFile != cur.SourceFile || prev.Size != cur.Size:
+			stale[name] = true
+		case !prev.Mtime.Equal(cur.Mtime):
+			if prev.Hash == "" || hashes.hash(cur.SourceFile) != prev.Hash {
+				stale[name] = true
+			}
+		}
+	}
+
+	// structure may be inferred from the body, and the body is read using the
+	// structure, so these components are always recalculated together
+	if stale["structure"] || stale["body"] {
+		stale["structure"] = true
+		stale["body"] = true
+	}
+	// dataset files can contain any other component
+	if stale["dataset"] {
+		for _, name := range statusComponentNames() {
+			stale[name] = true
+		}
+	}
+	return stale
+}
+
+// fileSize returns the size of a file. The size of a directory, like a body
+// stored as partitions, is the total size of the files in it
+func fileSize(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err == nil && !fi.IsDir() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// fileHasher calculates & memoizes file content hashes
+type fileHasher map[string]string
+
+// hash returns the hash of a file's contents. The hash of a directory covers
+// the names & contents of the files in it
+func (h fileHasher) hash(path string) string {
+	if sum, ok := h[path]; ok {
+		return sum
+	}
+	hasher := sha256.New()
+	err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		hasher.Write([]byte(rel))
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(hasher, f)
+		return err
+	})
+	sum := ""
+	if err == nil {
+		sum = hex.EncodeToString(hasher.Sum(nil))
+	}
+	h[path] = sum
+	return sum
+}
+
+func sameFileStates(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, st := range a {
+		other, ok := b[name]
+		if !ok || other.SourceFile != st.SourceFile || other.Size != st.Size || other.Hash != st.Hash || !other.Mtime.Equal(st.Mtime) {
+			return false
+		}
+	}
+	return true
+}
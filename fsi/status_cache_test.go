@@ -0,0 +1,162 @@
+package fsi
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/qri/event"
+)
+
+func TestStatusCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	paths := NewTmpPaths()
+	defer paths.Close()
+
+	bus := event.NewBus(ctx)
+	fsi := NewFSI(paths.testRepo, bus, paths.homeDir)
+	if _, err := fsi.InitDataset(InitParams{
+		Name:      "test_ds",
+		TargetDir: paths.firstDir,
+		Format:    "csv",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_ = copyDir("testdata/valid_mappings/all_json_components/", paths.firstDir)
+
+	expect, err := fsi.Status(ctx, paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := fsi.statusCache.get(paths.firstDir)
+	if cached == nil {
+		t.Fatal("expected status to be cached")
+	}
+	bodyPath := filepath.Join(paths.firstDir, "body.csv")
+	if cached.Files["body"].SourceFile != bodyPath || cached.Files["body"].Hash == "" {
+		t.Errorf("expected body file state to be cached, got: %#v", cached.Files["body"])
+	}
+
+	// touching a file without changing contents doesn't change status
+	mtime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(bodyPath, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	got, err := fsi.Status(ctx, paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch after touching body (-want +got):\n%s", diff)
+	}
+	if st := fsi.statusCache.get(paths.firstDir).Files["body"]; !st.Mtime.Equal(mtime) {
+		t.Errorf("expected cached body mtime to be updated. want: %s, got: %s", mtime, st.Mtime)
+	}
+
+	// removing a file only recalculates that component
+	if err := os.Remove(filepath.Join(paths.firstDir, "meta.json")); err != nil {
+		t.Fatal(err)
+	}
+	got, err = fsi.Status(ctx, paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range got {
+		if item.Component == "meta" {
+			t.Errorf("expected removed meta file to drop from status, got: %v", item)
+		}
+	}
+	if len(got) != len(expect)-1 {
+		t.Errorf("expected %d status items, got: %d", len(expect)-1, len(got))
+	}
+
+	// watchfs events invalidate cached status
+	if err := bus.Publish(ctx, event.ETModifiedFile, event.WatchfsChange{Source: bodyPath}); err != nil {
+		t.Fatal(err)
+	}
+	if st := fsi.statusCache.get(paths.firstDir).Files["body"]; !st.Mtime.IsZero() {
+		t.Errorf("expected watchfs event to invalidate cached body state, got mtime: %s", st.Mtime)
+	}
+
+	// status is persisted between instances
+	expect, err = fsi.Status(ctx, paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsi = NewFSI(paths.testRepo, nil, paths.homeDir)
+	if fsi.statusCache.get(paths.firstDir) == nil {
+		t.Fatal("expected status cache to be loaded from file")
+	}
+	got, err = fsi.Status(ctx, paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch from persisted cache (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatusCachePartitionedBody(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	paths := NewTmpPaths()
+	defer paths.Close()
+
+	bus := event.NewBus(ctx)
+	fsi := NewFSI(paths.testRepo, bus, paths.homeDir)
+	if _, err := fsi.InitDataset(InitParams{
+		Name:      "test_ds",
+		TargetDir: paths.firstDir,
+		Format:    "csv",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// replace the body file with a directory of partitions
+	if err := os.Remove(filepath.Join(paths.firstDir, "body.csv")); err != nil {
+		t.Fatal(err)
+	}
+	bodyDir := filepath.Join(paths.firstDir, "body")
+	if err := os.Mkdir(bodyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	partPath := filepath.Join(bodyDir, "part_1.csv")
+	if err := ioutil.WriteFile(partPath, []byte("one,1\ntwo,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(partPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fsi.Status(ctx, paths.firstDir); err != nil {
+		t.Fatal(err)
+	}
+	before := fsi.statusCache.get(paths.firstDir).Files["body"]
+	if before.SourceFile != bodyDir || before.Hash == "" {
+		t.Fatalf("expected body directory state to be cached, got: %#v", before)
+	}
+
+	// edit a partition without changing its size or modification time, so
+	// only the watchfs event shows the change
+	if err := ioutil.WriteFile(partPath, []byte("one,3\ntwo,4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(partPath, fi.ModTime(), fi.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(ctx, event.ETModifiedFile, event.WatchfsChange{Source: partPath}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsi.Status(ctx, paths.firstDir); err != nil {
+		t.Fatal(err)
+	}
+	after := fsi.statusCache.get(paths.firstDir).Files["body"]
+	if after.Hash == before.Hash {
+		t.Error("expected editing a partition to recalculate body status")
+	}
+}
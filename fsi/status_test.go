@@ -37,7 +37,7 @@ func TestStatusValid(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, err := fsi.InitDataset(InitParams{
 		Name:      "test_ds",
 		TargetDir: paths.firstDir,
@@ -85,7 +85,7 @@ func TestStatusInvalidDataset(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, err := fsi.InitDataset(InitParams{
 		Name:      "test_ds",
 		TargetDir: paths.firstDir,
@@ -113,7 +113,7 @@ func TestStatusInvalidMeta(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, err := fsi.InitDataset(InitParams{
 		Name:      "test_ds",
 		TargetDir: paths.firstDir,
@@ -139,7 +139,7 @@ func TestStatusNotFound(t *testing.T) {
 	paths := NewTmpPaths()
 	defer paths.Close()
 
-	fsi := NewFSI(paths.testRepo, nil)
+	fsi := NewFSI(paths.testRepo, nil, "")
 	_, err := fsi.InitDataset(InitParams{
 		Name:      "test_ds",
 		TargetDir: paths.firstDir,
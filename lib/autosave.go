@@ -0,0 +1,228 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/fsi"
+)
+
+// autosaver saves new versions of linked working directories that have
+// autosave enabled in config. A directory is saved once it's gone without
+// changes for the configured quiet period, and no more often than the
+// configured minimum interval. Changes are detected by listening for status
+// events from watchfs, so autosave only runs while a filesystem watcher does
+type autosaver struct {
+	inst *Instance
+	pub  event.Publisher
+
+	lk   sync.Mutex
+	dirs map[string]*autosaveDir
+}
+
+// autosaveDir tracks autosave state for a working directory
+type autosaveDir struct {
+	username string
+	dsname   string
+	timer    *time.Timer
+	saving   bool
+	lastSave time.Time
+}
+
+func newAutosaver(inst *Instance, bus event.Bus) *autosaver {
+	a := &autosaver{
+		inst: inst,
+		pub:  bus,
+		dirs: map[string]*autosaveDir{},
+	}
+	bus.Subscribe(a.handleEvent,
+		event.ETWorkingDirStatusChanged,
+		event.ETRenamedFolder,
+	)
+	return a
+}
+
+func (a *autosaver) handleEvent(_ context.Context, t event.Type, payload interface{}) error {
+	switch t {
+	case event.ETWorkingDirStatusChanged:
+		if st, ok := payload.(event.WorkingDirStatus); ok {
+			a.schedule(st.FSIPath, st.Username, st.Dsname)
+		}
+	case event.ETRenamedFolder:
+		if change, ok := payload.(event.WatchfsChange); ok {
+			a.moveLink(change.Source, change.Destination)
+		}
+	}
+	return nil
+}
+
+// schedule sets a working directory to be saved after the quiet period,
+// restarting the wait if a save is already scheduled
+func (a *autosaver) schedule(dir, username, dsname string) {
+	cfg := a.inst.Config().Autosave
+	if link := cfg.Link(dir); link == nil || link.Paused {
+		return
+	}
+
+	a.lk.Lock()
+	defer a.lk.Unlock()
+	d, ok := a.dirs[dir]
+	if !ok {
+		d = &autosaveDir{}
+		a.dirs[dir] = d
+	}
+	d.username = username
+	d.dsname = dsname
+
+	wait := time.Duration(cfg.QuietPeriodMs) * time.Millisecond
+	// rate limit saves, changes made within the minimum interval are
+	// collected into a single save
+	next := d.lastSave.Add(time.Duration(cfg.MinIntervalMs) * time.Millisecond)
+	if untilNext := time.Until(next); untilNext > wait {
+		wait = untilNext
+	}
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	log.Debugf("autosaving %q in %s", dir, wait)
+	d.timer = time.AfterFunc(wait, func() { a.save(dir) })
+}
+
+// cancel stops any scheduled save for a working directory
+func (a *autosaver) cancel(dir string) {
+	a.lk.Lock()
+	defer a.lk.Unlock()
+	if d, ok := a.dirs[dir]; ok && d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// save validates a working directory & saves a new version if it has changes
+func (a *autosaver) save(dir string) {
+	a.lk.Lock()
+	d, ok := a.dirs[dir]
+	if !ok {
+		a.lk.Unlock()
+		return
+	}
+	d.timer = nil
+	username, dsname := d.username, d.dsname
+	if d.saving {
+		// a save is in progress, try again once it's had time to finish
+		a.lk.Unlock()
+		a.schedule(dir, username, dsname)
+		return
+	}
+	d.saving = true
+	evt := event.FSIAutosave{
+		Username: username,
+		Dsname:   dsname,
+		FSIPath:  dir,
+	}
+	a.lk.Unlock()
+
+	defer func() {
+		a.lk.Lock()
+		d.saving = false
+		a.lk.Unlock()
+	}()
+
+	// settings may have changed while waiting
+	if link := a.inst.Config().Autosave.Link(dir); link == nil || link.Paused {
+		return
+	}
+
+	ctx := context.Background()
+	status, err := a.inst.fsi.Status(ctx, dir)
+	if err != nil {
+		a.publish(ctx, event.ETFSIAutosaveFailed, evt, err.Error())
+		return
+	}
+	if reason := autosaveSkipReason(status); reason != "" {
+		log.Debugf("skipping autosave of %q: %s", dir, reason)
+		a.publish(ctx, event.ETFSIAutosaveSkipped, evt, reason)
+		return
+	}
+
+	p := &SaveParams{
+		Ref: fmt.Sprintf("%s/%s", username, dsname),
+	}
+	res := &dataset.Dataset{}
+	if err := NewDatasetMethods(a.inst).Save(p, res); err != nil {
+		log.Debugf("autosaving %q: %s", dir, err)
+		a.publish(ctx, event.ETFSIAutosaveFailed, evt, err.Error())
+		return
+	}
+
+	a.lk.Lock()
+	d.lastSave = time.Now()
+	a.lk.Unlock()
+
+	evt.Path = res.Path
+	if res.Commit != nil {
+		evt.Title = res.Commit.Title
+	}
+	a.publish(ctx, event.ETFSIAutosaved, evt, "")
+}
+
+func (a *autosaver) publish(ctx context.Context, t event.Type, evt event.FSIAutosave, reason string) {
+	evt.Reason = reason
+	evt.Time = time.Now()
+	if err := a.pub.Publish(ctx, t, evt); err != nil {
+		log.Error(err)
+	}
+}
+
+// moveLink keeps autosave settings for a working directory that's been moved
+func (a *autosaver) moveLink(from, to string) {
+	if a.inst.Config().Autosave.Link(from) == nil {
+		return
+	}
+
+	a.lk.Lock()
+	if d, ok := a.dirs[from]; ok {
+		if d.timer != nil {
+			d.timer.Stop()
+			d.timer = nil
+		}
+		delete(a.dirs, from)
+		a.dirs[to] = d
+	}
+	a.lk.Unlock()
+
+	err := a.inst.updateConfig(func(cfg *config.Config) error {
+		if link := cfg.Autosave.Link(from); link != nil {
+			link.Path = to
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("updating autosave config for moved directory %q: %s", to, err)
+	}
+}
+
+// autosaveSkipReason returns a description of why a working directory with
+// the given status shouldn't be saved, or an empty string if it can be saved
+func autosaveSkipReason(status []fsi.StatusItem) string {
+	changed := false
+	for _, si := range status {
+		switch si.Type {
+		case fsi.STParseError, fsi.STConflictError:
+			return fmt.Sprintf("%s: %s", si.Component, si.Type)
+		case fsi.STUnmodified:
+		default:
+			changed = true
+		}
+	}
+	if !changed {
+		return "no changes"
+	}
+	return ""
+}
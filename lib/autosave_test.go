@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/event"
+)
+
+func TestAutosave(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	if _, err := run.SaveWithParams(&SaveParams{
+		Ref:      "me/autosave_ds",
+		BodyPath: "testdata/cities_2/body.csv",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(run.TmpDir, "autosave_ds")
+	if err := run.Checkout("me/autosave_ds", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewFSIMethods(run.Instance)
+	link := &config.AutosaveLink{}
+	if err := m.Autosave(&AutosaveParams{Dir: filepath.Join(run.TmpDir, "not_linked")}, link); err == nil {
+		t.Error("expected enabling autosave for an unlinked directory to error")
+	}
+	if err := m.Autosave(&AutosaveParams{Dir: dir}, link); err != nil {
+		t.Fatal(err)
+	}
+	if link.Path != dir || link.Paused {
+		t.Errorf("unexpected autosave link: %#v", link)
+	}
+	// change timing through config, the way a running instance would, so
+	// scheduled saves never see a config that's being modified
+	setTiming := func(quietPeriodMs, minIntervalMs int) {
+		cfg := run.Instance.Config().Copy()
+		cfg.Autosave.QuietPeriodMs = quietPeriodMs
+		cfg.Autosave.MinIntervalMs = minIntervalMs
+		if err := run.Instance.ChangeConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setTiming(10, 0)
+
+	events := make(chan event.Type, 10)
+	payloads := make(chan event.FSIAutosave, 10)
+	run.Instance.bus.Subscribe(func(_ context.Context, t event.Type, payload interface{}) error {
+		payloads <- payload.(event.FSIAutosave)
+		events <- t
+		return nil
+	}, event.ETFSIAutosaved, event.ETFSIAutosaveSkipped, event.ETFSIAutosaveFailed)
+
+	statusChanged := func() {
+		if err := run.Instance.bus.Publish(run.Ctx, event.ETWorkingDirStatusChanged, event.WorkingDirStatus{
+			Username: "peer",
+			Dsname:   "autosave_ds",
+			FSIPath:  dir,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectEvent := func(expect event.Type) event.FSIAutosave {
+		t.Helper()
+		select {
+		case got := <-events:
+			p := <-payloads
+			if got != expect {
+				t.Fatalf("expected event %q, got %q. reason: %s", expect, got, p.Reason)
+			}
+			return p
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for event %q", expect)
+		}
+		return event.FSIAutosave{}
+	}
+	expectNoEvent := func() {
+		t.Helper()
+		select {
+		case got := <-events:
+			t.Fatalf("expected no event, got %q", got)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	appendBody := func(row string) {
+		f, err := os.OpenFile(filepath.Join(dir, "body.csv"), os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(row)
+		f.Close()
+	}
+
+	statusChanged()
+	if p := expectEvent(event.ETFSIAutosaveSkipped); p.Reason != "no changes" {
+		t.Errorf("expected skip reason %q, got %q", "no changes", p.Reason)
+	}
+
+	appendBody("new orleans,390000,42.5,true\n")
+	statusChanged()
+	saved := expectEvent(event.ETFSIAutosaved)
+	if saved.Path == "" || saved.Title == "" {
+		t.Errorf("expected saved event to have a path & title, got: %#v", saved)
+	}
+
+	// directories that don't parse aren't saved
+	structurePath := filepath.Join(dir, "structure.json")
+	structure, err := ioutil.ReadFile(structurePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run.MustWriteFile(t, structurePath, "{ not json")
+	statusChanged()
+	expectEvent(event.ETFSIAutosaveSkipped)
+	run.MustWriteFile(t, structurePath, string(structure))
+
+	// saves are rate limited
+	setTiming(10, int(time.Hour/time.Millisecond))
+	appendBody("boston,690000,36.9,true\n")
+	statusChanged()
+	expectNoEvent()
+	setTiming(10, 0)
+
+	// paused directories aren't saved
+	if err := m.Autosave(&AutosaveParams{Dir: dir, Pause: true}, link); err != nil {
+		t.Fatal(err)
+	}
+	if !link.Paused {
+		t.Error("expected link to be paused")
+	}
+	statusChanged()
+	expectNoEvent()
+
+	// resuming saves changes made while paused
+	if err := m.Autosave(&AutosaveParams{Dir: dir, Resume: true}, link); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(event.ETFSIAutosaved)
+
+	if err := m.Autosave(&AutosaveParams{Dir: dir, Disable: true}, link); err != nil {
+		t.Fatal(err)
+	}
+	if run.Instance.Config().Autosave.Link(dir) != nil {
+		t.Error("expected disabling autosave to remove link from config")
+	}
+	appendBody("denver,715000,34.6,true\n")
+	statusChanged()
+	expectNoEvent()
+}
@@ -0,0 +1,200 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+// BisectMark records the outcome of testing a version
+type BisectMark = base.BisectMark
+
+// BisectParams defines parameters for the Bisect method
+type BisectParams struct {
+	// Reference to the dataset to bisect. If the reference selects a version,
+	// history after that version is ignored
+	Ref string
+	// CheckFilename is a starlark script defining a check(ds, body) function
+	// that returns True for good versions
+	CheckFilename string
+	// SchemaFilename is a JSON schema good version bodies must validate against
+	SchemaFilename string
+	// Good & Bad list versions already known to be good or bad, as paths or
+	// dataset references
+	Good, Bad []string
+}
+
+// BisectResult is the outcome of bisecting a dataset history
+type BisectResult struct {
+	// FirstBad is the first version that fails the check
+	FirstBad *DatasetLogItem `json:"firstBad,omitempty"`
+	// Reason FirstBad fails the check, if the check provided one
+	Reason string `json:"reason,omitempty"`
+	// LastGood is the version before FirstBad
+	LastGood *DatasetLogItem `json:"lastGood,omitempty"`
+	// Next is the version to mark next when bisecting without a check
+	Next *DatasetLogItem `json:"next,omitempty"`
+	// Remaining is the number of versions that could still be the first bad
+	// version
+	Remaining int `json:"remaining"`
+	// Steps estimates the number of versions left to mark
+	Steps int `json:"steps"`
+	// Tested lists versions tested by the check
+	Tested []BisectMark `json:"tested,omitempty"`
+}
+
+// Bisect binary searches dataset history for the first version that breaks a
+// check. Checks are either a starlark script or a JSON schema. Without a check
+// versions are marked good or bad by hand: Bisect returns the next version
+// to mark until the first bad version is found
+func (m *DatasetMethods) Bisect(p *BisectParams, res *BisectResult) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Bisect", p, res))
+	}
+	ctx := context.TODO()
+
+	if p.CheckFilename != "" && p.SchemaFilename != "" {
+		return fmt.Errorf("can't bisect with both a check script and a schema")
+	}
+	check, err := bisectCheck(p.CheckFilename, p.SchemaFilename)
+	if err != nil {
+		return err
+	}
+
+	ref, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+	if err != nil {
+		return err
+	}
+
+	// logbook items are ordered newest to oldest
+	items, err := m.inst.logbook.Items(ctx, ref, 0, -1)
+	if err != nil {
+		return err
+	}
+	versions := map[string]DatasetLogItem{}
+	var history []string
+	for i := len(items) - 1; i >= 0; i-- {
+		versions[items[i].Path] = items[i]
+		history = append(history, items[i].Path)
+		if items[i].Path == ref.Path {
+			break
+		}
+	}
+
+	marks := map[string]bool{}
+	for _, good := range []bool{true, false} {
+		refs := p.Bad
+		if good {
+			refs = p.Good
+		}
+		for _, s := range refs {
+			path, err := m.bisectMarkPath(ctx, s)
+			if err != nil {
+				return err
+			}
+			if prev, ok := marks[path]; ok && prev != good {
+				return fmt.Errorf("version %s is marked both good & bad", path)
+			}
+			marks[path] = good
+		}
+	}
+
+	br, err := base.Bisect(ctx, history, marks, m.versionLoader(ref), check)
+	if err != nil {
+		return err
+	}
+
+	item := func(path string) *DatasetLogItem {
+		if path == "" {
+			return nil
+		}
+		v := versions[path]
+		return &v
+	}
+	*res = BisectResult{
+		FirstBad:  item(br.FirstBad),
+		LastGood:  item(br.LastGood),
+		Next:      item(br.Next),
+		Remaining: br.Remaining,
+		Steps:     br.Steps,
+		Tested:    br.Tested,
+	}
+	for _, mark := range br.Tested {
+		if mark.Path == br.FirstBad {
+			res.Reason = mark.Reason
+		}
+	}
+	return nil
+}
+
+// bisectCheck loads a check from a starlark script or JSON schema file
+func bisectCheck(checkFilename, schemaFilename string) (base.BisectCheck, error) {
+	if checkFilename != "" {
+		if filepath.Ext(checkFilename) != ".star" {
+			return nil, fmt.Errorf("check script must be a starlark file with a .star extension")
+		}
+		data, err := ioutil.ReadFile(checkFilename)
+		if err != nil {
+			return nil, fmt.Errorf("error opening check script %q: %w", checkFilename, err)
+		}
+		return base.NewStarlarkCheck(checkFilename, data)
+	}
+	if schemaFilename != "" {
+		data, err := ioutil.ReadFile(schemaFilename)
+		if err != nil {
+			return nil, fmt.Errorf("error opening schema file %q: %w", schemaFilename, err)
+		}
+		schema := map[string]interface{}{}
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("error parsing schema file %q: %w", schemaFilename, err)
+		}
+		return base.NewSchemaCheck(schema), nil
+	}
+	return nil, nil
+}
+
+// bisectMarkPath resolves a version marked good or bad to a path
+func (m *DatasetMethods) bisectMarkPath(ctx context.Context, s string) (string, error) {
+	if strings.HasPrefix(s, "/") {
+		return s, nil
+	}
+	ref, _, err := m.inst.ParseAndResolveRef(ctx, s, "local")
+	if err != nil {
+		return "", err
+	}
+	return ref.Path, nil
+}
+
+// versionLoader creates a loader for versions of a dataset, fetching versions
+// that aren't stored locally
+func (m *DatasetMethods) versionLoader(ref dsref.Ref) base.VersionLoader {
+	fs := m.inst.repo.Filesystem()
+	return func(ctx context.Context, path string) (*dataset.Dataset, error) {
+		vref := dsref.Ref{Username: ref.Username, Name: ref.Name, ProfileID: ref.ProfileID, Path: path}
+		if err := m.inst.fetchMissingVersion(ctx, vref); err != nil {
+			return nil, err
+		}
+		if err := m.inst.completeVersion(ctx, vref); err != nil {
+			return nil, err
+		}
+		ds, err := dsfs.LoadDataset(ctx, fs, path)
+		if err != nil {
+			return nil, err
+		}
+		body, err := dsfs.LoadBody(ctx, fs, ds)
+		if err != nil {
+			return nil, err
+		}
+		ds.SetBodyFile(body)
+		ds.Peername = ref.Username
+		return ds, nil
+	}
+}
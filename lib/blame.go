@@ -0,0 +1,85 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+// BlameEntry attributes a row or cell to the version that last changed it
+type BlameEntry = base.BlameEntry
+
+// BlameParams defines parameters for the Blame method
+type BlameParams struct {
+	// Reference to the dataset version to blame
+	Ref string
+	// Key names the primary key column used to match rows across versions
+	Key string
+	// Columns blames individual cells of the named columns instead of rows
+	Columns []string
+	// Offset & Limit select a range of rows
+	Offset, Limit int
+}
+
+// Blame reports the version, commit title, author, & time of the commit that
+// last changed each row of a dataset body. Rows are matched across versions
+// by a primary key column
+func (m *DatasetMethods) Blame(p *BlameParams, res *[]BlameEntry) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Blame", p, res))
+	}
+	ctx := context.TODO()
+
+	ref, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+	if err != nil {
+		return err
+	}
+
+	items, err := m.inst.logbook.Items(ctx, ref, 0, -1)
+	if err != nil {
+		return err
+	}
+	var history []string
+	for _, item := range items {
+		if item.Path == ref.Path || len(history) > 0 {
+			history = append(history, item.Path)
+		}
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("version %s isn't in the history of %s", ref.Path, ref.Alias())
+	}
+
+	fs := m.inst.repo.Filesystem()
+	load := func(ctx context.Context, path string) (*dataset.Dataset, error) {
+		vref := dsref.Ref{Username: ref.Username, Name: ref.Name, ProfileID: ref.ProfileID, Path: path}
+		if err := m.inst.fetchMissingVersion(ctx, vref); err != nil {
+			return nil, err
+		}
+		if err := m.inst.completeVersion(ctx, vref); err != nil {
+			return nil, err
+		}
+		ds, err := dsfs.LoadDataset(ctx, fs, path)
+		if err != nil {
+			return nil, err
+		}
+		body, err := dsfs.LoadBody(ctx, fs, ds)
+		if err != nil {
+			return nil, err
+		}
+		ds.SetBodyFile(body)
+		ds.Peername = ref.Username
+		return ds, nil
+	}
+
+	*res, err = base.Blame(ctx, history, load, base.BlameParams{
+		Key:     p.Key,
+		Columns: p.Columns,
+		Offset:  p.Offset,
+		Limit:   p.Limit,
+	})
+	return err
+}
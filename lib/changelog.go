@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/fsi"
+)
+
+// ChangelogVersion describes the changes a dataset version made
+type ChangelogVersion = base.ChangelogVersion
+
+// ChangelogParams defines parameters for the Changelog method
+type ChangelogParams struct {
+	// Reference to the dataset version to describe history up to
+	Ref string
+	// Since limits the changelog to versions after a version, as a tag name,
+	// path, dataset reference, or revision like "~2"
+	Since string
+}
+
+// ChangelogResult is a changelog of dataset history, with a markdown
+// rendering
+type ChangelogResult struct {
+	Ref      string             `json:"ref"`
+	Versions []ChangelogVersion `json:"versions"`
+	Markdown string             `json:"markdown"`
+}
+
+// Changelog describes the changes each version of a dataset made, newest
+// first, including commit messages, components that changed, schema changes,
+// and changes in row count
+func (m *DatasetMethods) Changelog(p *ChangelogParams, res *ChangelogResult) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Changelog", p, res))
+	}
+	ctx := context.TODO()
+
+	ref, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+	if err != nil {
+		return err
+	}
+	since := ""
+	if p.Since != "" {
+		s := p.Since
+		if dsref.IsValidTagName(s) {
+			s = "@" + s
+		}
+		if since, err = m.inst.resolveVersionPath(ctx, ref, s); err != nil {
+			return err
+		}
+	}
+
+	versions, err := m.changelog(ctx, ref, since)
+	if err != nil {
+		return err
+	}
+	*res = ChangelogResult{
+		Ref:      ref.Human(),
+		Versions: versions,
+		Markdown: base.ChangelogMarkdown(fmt.Sprintf("%s changelog", ref.Human()), versions),
+	}
+	return nil
+}
+
+// changelog describes the history of a dataset up to the version ref.Path,
+// or all history if ref has no path
+func (m *DatasetMethods) changelog(ctx context.Context, ref dsref.Ref, since string) ([]ChangelogVersion, error) {
+	items, err := m.inst.logbook.Items(ctx, ref, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Path != "" {
+		for i, item := range items {
+			if item.Path == ref.Path {
+				items = items[i:]
+				break
+			}
+		}
+		if len(items) == 0 || items[0].Path != ref.Path {
+			return nil, fmt.Errorf("version %s isn't in the history of %s", ref.Path, ref.Alias())
+		}
+	}
+
+	fs := m.inst.repo.Filesystem()
+	load := func(ctx context.Context, path string) (*dataset.Dataset, error) {
+		vref := dsref.Ref{Username: ref.Username, Name: ref.Name, ProfileID: ref.ProfileID, Path: path}
+		if err := m.inst.fetchMissingVersion(ctx, vref); err != nil {
+			return nil, err
+		}
+		return dsfs.LoadDataset(ctx, fs, path)
+	}
+	return base.Changelog(ctx, items, since, load)
+}
+
+// changelogReadmeHook prepares the readme of ds, which will be saved as the
+// next version of the dataset at ref, to have a changelog written into it. The
+// returned hook writes a changelog of history up to ref, along with the
+// version being saved, once the version's body is computed. A version can't
+// refer to its own path. ds keeps the previous version's readme if it doesn't
+// have one
+func (m *DatasetMethods) changelogReadmeHook(ctx context.Context, ref dsref.Ref, ds *dataset.Dataset) (dsfs.ReadmeHook, error) {
+	if fsi.IsFSIPath(ref.Path) {
+		ref.Path = ""
+	}
+	versions, err := m.changelog(ctx, ref, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%s has no history to write a changelog of", ref.Human())
+	}
+
+	fs := m.inst.repo.Filesystem()
+	prev, err := dsfs.LoadDataset(ctx, fs, versions[0].Path)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Readme == nil {
+		ds.Readme = &dataset.Readme{}
+		if prev.Readme != nil {
+			ds.Readme.ScriptPath = prev.Readme.ScriptPath
+		}
+	}
+	if err := ds.Readme.InlineScriptFile(ctx, fs); err != nil {
+		return nil, err
+	}
+	if err := ds.Readme.OpenScriptFile(ctx, fs); err != nil {
+		return nil, err
+	}
+
+	hook := func(ctx context.Context, next *dataset.Dataset, script []byte) ([]byte, error) {
+		now := time.Now()
+		if next.Commit != nil && !next.Commit.Timestamp.IsZero() {
+			now = next.Commit.Timestamp
+		}
+		v := base.NextChangelogVersion(prev, next, ref.Username, now)
+		md := base.ChangelogMarkdown("Changelog", append([]ChangelogVersion{v}, versions...))
+		return []byte(base.WriteChangelogToReadme(string(script), md)), nil
+	}
+	return hook, nil
+}
@@ -28,8 +28,12 @@ import (
 	qrierr "github.com/qri-io/qri/errors"
 	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/fsi/linkfile"
+	"github.com/qri-io/qri/lineage"
+	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/remote"
 	"github.com/qri-io/qri/repo"
 	reporef "github.com/qri-io/qri/repo/ref"
+	"github.com/qri-io/qri/startf"
 )
 
 // DatasetMethods encapsulates business logic for working with Datasets on Qri
@@ -206,12 +210,19 @@ type GetParams struct {
 
 	Limit, Offset int
 	All           bool
+	// Columns limits the body of a remote dataset to the named columns. Only
+	// used when reading a page of body data from a remote
+	Columns []string
 
 	// outfile is a filename to save the dataset to
 	Outfile string
 	// whether to generate a filename from the dataset name instead
 	GenFilename bool
 	Remote      string
+	// Evict drops the requested version from local storage once the request is
+	// complete, unless it's one of the versions the repo keeps locally or it
+	// hasn't been pushed to a remote
+	Evict bool
 }
 
 // GetResult combines data with it's hashed path
@@ -246,10 +257,21 @@ func (m *DatasetMethods) Get(p *GetParams, res *GetResult) error {
 	if err != nil {
 		return err
 	}
+	if p.Selector == "body" && p.Remote != "" && source != "" {
+		// read body data from the remote without pulling the dataset
+		return m.getRemoteBody(ctx, p, ref, source, res)
+	}
+	if len(p.Columns) > 0 {
+		return fmt.Errorf("selecting columns is only supported when getting a body from a remote")
+	}
+
 	ds, err = m.inst.LoadDataset(ctx, ref, source)
 	if err != nil {
 		return err
 	}
+	if p.Evict && !fsi.IsFSIPath(ref.Path) {
+		defer m.inst.evictVersion(ctx, ref)
+	}
 
 	res.Ref = &ref
 	res.Dataset = ds
@@ -395,6 +417,87 @@ func (m *DatasetMethods) Get(p *GetParams, res *GetResult) error {
 	return m.maybeWriteOutfile(p, res)
 }
 
+// getRemoteBody reads body data from a remote. Remotes deliver at most
+// base.MaxBodyPageRows entries at a time, getting "all" entries reads every
+// page of the body
+func (m *DatasetMethods) getRemoteBody(ctx context.Context, p *GetParams, ref dsref.Ref, source string, res *GetResult) error {
+	if m.inst.remoteClient == nil {
+		return remote.ErrNoRemoteClient
+	}
+	if p.Format != "" && p.Format != "json" {
+		return fmt.Errorf("bodies of remote datasets can only be read as json")
+	}
+
+	var (
+		data json.RawMessage
+		err  error
+	)
+	if p.All {
+		data, err = m.getAllRemoteBody(ctx, ref, source, p.Columns)
+	} else if p.Limit < 0 || p.Offset < 0 {
+		return fmt.Errorf("invalid limit / offset settings")
+	} else {
+		data, err = m.inst.remoteClient.PreviewDatasetBody(ctx, ref, source, p.Offset, p.Limit, p.Columns)
+	}
+	if err != nil {
+		return err
+	}
+	if p.FormatConfig != nil {
+		if pretty, ok := p.FormatConfig.Map()["pretty"].(bool); ok && pretty {
+			buf := &bytes.Buffer{}
+			if err := json.Indent(buf, data, "", " "); err != nil {
+				return err
+			}
+			data = buf.Bytes()
+		}
+	}
+
+	res.Ref = &ref
+	res.Bytes = data
+	return m.maybeWriteOutfile(p, res)
+}
+
+// getAllRemoteBody reads every entry of a remote body, a page at a time.
+// Array bodies are joined in order, object bodies are merged
+func (m *DatasetMethods) getAllRemoteBody(ctx context.Context, ref dsref.Ref, source string, columns []string) (json.RawMessage, error) {
+	var (
+		rows = []json.RawMessage{}
+		obj  map[string]json.RawMessage
+	)
+	for offset, n := 0, base.MaxBodyPageRows; n == base.MaxBodyPageRows; offset += n {
+		page, err := m.inst.remoteClient.PreviewDatasetBody(ctx, ref, source, offset, base.MaxBodyPageRows, columns)
+		if err != nil {
+			return nil, err
+		}
+		if page = bytes.TrimSpace(page); len(page) > 0 && page[0] == '{' {
+			entries := map[string]json.RawMessage{}
+			if err := json.Unmarshal(page, &entries); err != nil {
+				return nil, err
+			}
+			if obj == nil {
+				obj = entries
+			} else {
+				for key, val := range entries {
+					obj[key] = val
+				}
+			}
+			n = len(entries)
+		} else {
+			entries := []json.RawMessage{}
+			if err := json.Unmarshal(page, &entries); err != nil {
+				return nil, err
+			}
+			rows = append(rows, entries...)
+			n = len(entries)
+		}
+	}
+
+	if obj != nil {
+		return json.Marshal(obj)
+	}
+	return json.Marshal(rows)
+}
+
 func (m *DatasetMethods) maybeWriteOutfile(p *GetParams, res *GetResult) error {
 	if p.Outfile != "" {
 		err := ioutil.WriteFile(p.Outfile, res.Bytes, 0644)
@@ -472,6 +575,12 @@ type SaveParams struct {
 	NewName bool
 	// whether to create a new dscache if none exists
 	UseDscache bool
+	// skip running pre-save and post-save hooks of a linked working directory
+	NoVerify bool
+	// treat BodyPath as rows to append to the previous version's body
+	Append bool
+	// write a changelog of dataset history into the readme
+	Changelog bool
 }
 
 // AbsolutizePaths converts any relative path references to their absolute
@@ -509,6 +618,14 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 	if p.Private {
 		return fmt.Errorf("option to make dataset private not yet implemented, refer to https://github.com/qri-io/qri/issues/291 for updates")
 	}
+	if p.Append {
+		if p.BodyPath == "" {
+			return fmt.Errorf("appending requires a body file of rows to append")
+		}
+		if p.Replace || p.NewName {
+			return fmt.Errorf("can't append when replacing or creating a new dataset")
+		}
+	}
 
 	// If the dscache doesn't exist yet, it will only be created if the appropriate flag enables it.
 	if p.UseDscache && !p.DryRun {
@@ -572,6 +689,17 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 		}
 	}()
 
+	if !isNew && ref.Path != "" && !fsi.IsFSIPath(ref.Path) {
+		// new versions build on the previous version, which must be stored
+		// locally in full
+		if err := m.inst.fetchMissingVersion(ctx, ref); err != nil {
+			return err
+		}
+		if err := m.inst.completeVersion(ctx, ref); err != nil {
+			return err
+		}
+	}
+
 	ds.Name = ref.Name
 	ds.Peername = ref.Username
 
@@ -586,6 +714,16 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 				return err
 			}
 			fsiDs.Assign(ds)
+			if !p.NoVerify {
+				if err := fsi.RunHook(ctx, fsiPath, fsi.HookPreSave, fsiDs); err != nil {
+					return err
+				}
+				// hooks may modify files in the working directory
+				if fsiDs, err = fsi.ReadDir(fsiPath); err != nil {
+					return err
+				}
+				fsiDs.Assign(ds)
+			}
 			ds = fsiDs
 		}
 	}
@@ -599,6 +737,16 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 		ds = recall
 	}
 
+	var readmeHook dsfs.ReadmeHook
+	if p.Changelog {
+		if isNew {
+			return fmt.Errorf("can't write a changelog for a new dataset without history")
+		}
+		if readmeHook, err = m.changelogReadmeHook(ctx, ref, ds); err != nil {
+			return err
+		}
+	}
+
 	if !p.Force && p.Drop == "" &&
 		ds.BodyPath == "" &&
 		ds.Body == nil &&
@@ -638,10 +786,18 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 		loader := NewParseResolveLoadFunc("", m.inst.defaultResolver(), m.inst)
 
 		// apply the transform
-		err := base.TransformApply(ctx, ds, r, loader, str, scriptOut, secrets)
+		err := base.TransformApply(ctx, ds, r, loader, str, scriptOut, secrets,
+			startf.AddStatsFunc(m.inst.stats.Stats),
+			startf.AddQueryFunc(sqlQueryFunc(r)),
+		)
 		if err != nil {
 			return err
 		}
+
+		// warn when the transform read a version that isn't the latest
+		for _, o := range lineage.OutdatedInputs(ctx, m.inst.defaultResolver(), ds.Transform) {
+			str.PrintErr(fmt.Sprintf("⚠️  transform read %s, but a newer version exists: %s\n", o.Consumed, o.Latest))
+		}
 	}
 
 	if p.DryRun {
@@ -671,6 +827,8 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 		ShouldRender:        p.ShouldRender,
 		NewName:             p.NewName,
 		Drop:                p.Drop,
+		Append:              p.Append,
+		ReadmeHook:          readmeHook,
 	}
 	savedDs, err := base.SaveDataset(ctx, m.inst.repo, writeDest, ref.InitID, ref.Path, ds, switches)
 	if err != nil {
@@ -696,6 +854,10 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 	}
 
 	*res = *savedDs
+	m.inst.evictVersions(ctx, dsref.ConvertDatasetToVersionInfo(savedDs).SimpleRef())
+	if !p.DryRun {
+		m.inst.pruneOnSave(ctx, dsref.ConvertDatasetToVersionInfo(savedDs).SimpleRef())
+	}
 
 	if fsiPath != "" && !p.DryRun {
 		// Need to pass filesystem here so that we can read the README component and write it
@@ -703,7 +865,124 @@ func (m *DatasetMethods) Save(p *SaveParams, res *dataset.Dataset) error {
 		if writeErr := fsi.WriteComponents(savedDs, fsiPath, m.inst.repo.Filesystem()); err != nil {
 			log.Error(writeErr)
 		}
+		if !p.NoVerify {
+			// the new version has already been saved, hook failures are warnings
+			if err := fsi.RunHook(ctx, fsiPath, fsi.HookPostSave, savedDs); err != nil {
+				m.inst.node.LocalStreams.PrintErr(fmt.Sprintf("⚠️  %s\n", err))
+			}
+		}
+	}
+	return nil
+}
+
+// RevertParams defines parameters for the Revert method
+type RevertParams struct {
+	// Reference to the version to restore, eg: me/dataset~1
+	Ref string
+	// Components to restore, restoring all components if empty
+	Components []string
+	// Title of the revert commit, generated if empty
+	Title string
+	// Message describing the revert
+	Message string
+}
+
+// Revert creates a new version of a dataset that restores components of an
+// earlier version. Unlike removing versions, reverting adds to history, which
+// keeps the dataset in sync with anyone who has already pulled it. Dataset
+// history records which version the new version restored
+func (m *DatasetMethods) Revert(p *RevertParams, res *dataset.Dataset) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Revert", p, res))
+	}
+	ctx := context.TODO()
+
+	if parsed, err := dsref.Parse(p.Ref); err == nil && parsed.Path == "" && parsed.Revision == "" {
+		return fmt.Errorf("reverting requires a version to restore, eg: %s~1", parsed.Alias())
+	}
+	target, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+	if err != nil {
+		return err
+	}
+
+	head := dsref.Ref{Username: target.Username, Name: target.Name}
+	if _, err := m.inst.ResolveReference(ctx, &head, "local"); err != nil {
+		return err
+	}
+	if head.Path == target.Path {
+		return fmt.Errorf("%s is already the latest version", target.Path)
+	}
+	if head.InitID == "" {
+		if head.InitID, err = m.inst.logbook.RefToInitID(dsref.Ref{Username: head.Username, Name: head.Name}); err != nil {
+			return err
+		}
+	}
+
+	items, err := m.inst.logbook.Items(ctx, head, 0, -1)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, item := range items {
+		if item.Path == target.Path {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("version %s isn't in the history of %s", target.Path, head.Alias())
+	}
+
+	fsiRef := head.Copy()
+	if err := m.inst.fsi.ResolvedPath(&fsiRef); err == nil {
+		return qrierr.New(fmt.Errorf("cannot revert while FSI-linked"), "can't revert a dataset linked to a working directory, use `qri restore` to restore files instead.")
+	}
+
+	for _, ref := range []dsref.Ref{head, target} {
+		if err := m.inst.fetchMissingVersion(ctx, ref); err != nil {
+			return err
+		}
+		if err := m.inst.completeVersion(ctx, ref); err != nil {
+			return err
+		}
+	}
+
+	fs := m.inst.repo.Filesystem()
+	ds, drop, err := base.PrepareRevert(ctx, fs, target, p.Components)
+	if err != nil {
+		return err
+	}
+	if ds.Transform == nil && !strings.Contains(drop, "tf") {
+		// transforms aren't carried over between versions by saving, keep the
+		// transform of the latest version unless it's being reverted
+		prev, err := dsfs.LoadDataset(ctx, fs, head.Path)
+		if err != nil {
+			return err
+		}
+		ds.Transform = prev.Transform
+	}
+	ds.Name = head.Name
+	ds.Peername = head.Username
+	ds.Commit = base.RevertCommit(target.Path, p.Components, p.Title, p.Message)
+
+	if err = base.OpenDataset(ctx, fs, ds); err != nil {
+		return err
+	}
+
+	switches := base.SaveSwitches{
+		Pin:          true,
+		ShouldRender: true,
+		Drop:         drop,
+		Reverts:      target.Path,
+	}
+	savedDs, err := base.SaveDataset(ctx, m.inst.repo, m.inst.qfs.DefaultWriteFS(), head.InitID, head.Path, ds, switches)
+	if err != nil {
+		return err
 	}
+
+	*res = *savedDs
+	m.inst.evictVersions(ctx, dsref.ConvertDatasetToVersionInfo(savedDs).SimpleRef())
+	m.inst.pruneOnSave(ctx, dsref.ConvertDatasetToVersionInfo(savedDs).SimpleRef())
 	return nil
 }
 
@@ -942,6 +1221,9 @@ type PullParams struct {
 	LinkDir  string
 	Remote   string // remote to attempt to pull from
 	LogsOnly bool   // only fetch logbook data
+	// only fetch the files of these components, leaving a partial version.
+	// definitions of all components are always fetched
+	Components []string
 }
 
 // Pull downloads and stores an existing dataset to a peer's repository via
@@ -967,13 +1249,27 @@ func (m *DatasetMethods) Pull(p *PullParams, res *dataset.Dataset) error {
 		return err
 	}
 
-	ds, err := m.inst.remoteClient.PullDataset(ctx, &ref, source)
+	var ds *dataset.Dataset
+	if p.Components != nil {
+		ds, err = m.inst.remoteClient.PullDatasetComponents(ctx, &ref, source, p.Components)
+	} else {
+		ds, err = m.inst.remoteClient.PullDataset(ctx, &ref, source)
+	}
 	if err != nil {
 		log.Debugf("pulling dataset: %s", err)
 		return err
 	}
 
+	// record where partial versions came from, so they can be completed from
+	// the same remote
+	if p.Components != nil && m.inst.isSparseVersion(ctx, ref.Path) {
+		m.inst.sparseSources.put(ref.Path, source)
+	} else {
+		m.inst.sparseSources.remove(ref.Path)
+	}
+
 	*res = *ds
+	m.inst.evictVersions(ctx, ref)
 
 	if p.LinkDir != "" {
 		checkoutp := &CheckoutParams{
@@ -1212,6 +1508,30 @@ func (m *DatasetMethods) DAGInfo(s *DAGInfoParams, i *dag.Info) error {
 	return err
 }
 
+// DAGDedup compares the blocks of a dataset version with its previous version,
+// reporting how much of the version is stored in shared blocks
+func (m *DatasetMethods) DAGDedup(s *DAGInfoParams, res *p2p.DedupInfo) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.DAGDedup", s, res))
+	}
+	ctx := context.TODO()
+
+	ref, err := repo.ParseDatasetRef(s.RefStr)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeDatasetRef(m.inst.repo, &ref); err != nil {
+		return err
+	}
+
+	info, err := m.inst.node.NewDedupInfo(ctx, ref.Path)
+	if err != nil {
+		return err
+	}
+	*res = *info
+	return nil
+}
+
 // StatsParams defines the params for a Stats request
 type StatsParams struct {
 	// string representation of a dataset reference
@@ -456,7 +456,11 @@ func TestDatasetRequestsGet(t *testing.T) {
 		expect      string
 	}{
 		{"invalid peer name",
-			&GetParams{Refstr: "peer/ABC@abc"}, `"peer/ABC@abc" is not a valid dataset reference: unexpected character at position 8: '@'`},
+			&GetParams{Refstr: "peer/ABC@abc"}, `reference not found`},
+		{"unknown tag",
+			&GetParams{Refstr: "peer/movies@abc"}, `revision not found: unknown tag "abc"`},
+		{"profile ID as tag",
+			&GetParams{Refstr: "peer/movies@QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt"}, `"peer/movies@QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt" is not a valid dataset reference: "QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt" looks like a profile ID, not a tag. a profile ID must be followed by a path`},
 
 		{"peername without path",
 			&GetParams{Refstr: "peer/movies"},
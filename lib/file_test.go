@@ -24,6 +24,18 @@ func TestReadDatasetFiles(t *testing.T) {
 			},
 		},
 
+		{".sql file to sql transform",
+			[]string{
+				"testdata/tf/transform.sql",
+			},
+			&dataset.Dataset{
+				Transform: &dataset.Transform{
+					ScriptPath: "testdata/tf/transform.sql",
+					Syntax:     "sql",
+				},
+			},
+		},
+
 		{".html file to viz script",
 			[]string{
 				"testdata/viz/visualization.html",
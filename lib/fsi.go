@@ -12,6 +12,7 @@ import (
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/component"
 	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/repo"
@@ -98,6 +99,94 @@ func (m *FSIMethods) Unlink(p *LinkParams, res *string) (err error) {
 	return nil
 }
 
+// AutosaveParams configures automatic saving of a linked working directory
+type AutosaveParams struct {
+	Dir string
+	// Disable turns autosave off for the directory
+	Disable bool
+	// Pause stops saving the directory, keeping its autosave settings
+	Pause bool
+	// Resume restarts saving a paused directory
+	Resume bool
+}
+
+// Autosave enables, disables, pauses or resumes automatic saving of a linked
+// working directory. Autosave settings are stored in config. Saves are only
+// made while qri is connected & watching the filesystem
+func (m *FSIMethods) Autosave(p *AutosaveParams, res *config.AutosaveLink) (err error) {
+	if p.Dir, err = filepath.Abs(p.Dir); err != nil {
+		return err
+	}
+
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("FSIMethods.Autosave", p, res))
+	}
+
+	flags := 0
+	for _, set := range []bool{p.Disable, p.Pause, p.Resume} {
+		if set {
+			flags++
+		}
+	}
+	if flags > 1 {
+		return fmt.Errorf("only one of disable, pause or resume can be used at a time")
+	}
+
+	ref, linked := fsi.GetLinkedFilesysRef(p.Dir)
+	if !linked && !p.Disable {
+		return fmt.Errorf("%q is not a linked working directory", p.Dir)
+	}
+
+	err = m.inst.updateConfig(func(cfg *config.Config) error {
+		if cfg.Autosave == nil {
+			cfg.Autosave = config.DefaultAutosave()
+		}
+		link := cfg.Autosave.Link(p.Dir)
+
+		switch {
+		case p.Disable:
+			if link == nil {
+				return fmt.Errorf("autosave isn't enabled for %q", p.Dir)
+			}
+			links := make([]*config.AutosaveLink, 0, len(cfg.Autosave.Links))
+			for _, l := range cfg.Autosave.Links {
+				if l != link {
+					links = append(links, l)
+				}
+			}
+			cfg.Autosave.Links = links
+			*res = config.AutosaveLink{Path: p.Dir}
+		case p.Pause, p.Resume:
+			if link == nil {
+				return fmt.Errorf("autosave isn't enabled for %q", p.Dir)
+			}
+			link.Paused = p.Pause
+			*res = *link
+		default:
+			if link == nil {
+				link = &config.AutosaveLink{Path: p.Dir}
+				cfg.Autosave.Links = append(cfg.Autosave.Links, link)
+			}
+			link.Paused = false
+			*res = *link
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if m.inst.autosave != nil {
+		if p.Disable || p.Pause {
+			m.inst.autosave.cancel(p.Dir)
+		} else if p.Resume {
+			// save any changes made while paused
+			m.inst.autosave.schedule(p.Dir, ref.Username, ref.Name)
+		}
+	}
+	return nil
+}
+
 // StatusItem is an alias for an fsi.StatusItem
 type StatusItem = fsi.StatusItem
 
@@ -340,6 +429,95 @@ func (m *FSIMethods) Restore(p *RestoreParams, out *string) (err error) {
 	return nil
 }
 
+// Stash is an alias for an fsi.Stash
+type Stash = fsi.Stash
+
+// StashParams provides parameters to stash methods
+type StashParams struct {
+	// linked working directory
+	Dir string
+	// optional description of stashed changes
+	Message string
+	// stash to pop or drop. 0 is the most recent stash
+	Index int
+	// apply a stash even if it conflicts with changes
+	Force bool
+}
+
+// StashPush sets aside changes in a linked directory, restoring changed
+// components to the version the directory is linked to
+func (m *FSIMethods) StashPush(p *StashParams, res *Stash) (err error) {
+	if p.Dir, err = filepath.Abs(p.Dir); err != nil {
+		return err
+	}
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("FSIMethods.StashPush", p, res))
+	}
+	ctx := context.TODO()
+
+	s, err := m.inst.fsi.Stash(ctx, p.Dir, p.Message)
+	if err != nil {
+		return err
+	}
+	*res = *s
+	return nil
+}
+
+// StashList lists stashed changes for a linked directory, most recent first
+func (m *FSIMethods) StashList(p *StashParams, res *[]Stash) (err error) {
+	if p.Dir, err = filepath.Abs(p.Dir); err != nil {
+		return err
+	}
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("FSIMethods.StashList", p, res))
+	}
+
+	stashes := m.inst.fsi.Stashes(p.Dir)
+	*res = make([]Stash, len(stashes))
+	for i, s := range stashes {
+		(*res)[i] = *s
+	}
+	return nil
+}
+
+// StashPop applies stashed changes to a linked directory & removes the stash.
+// Stashes that conflict with changes made since stashing are only applied
+// when forced
+func (m *FSIMethods) StashPop(p *StashParams, res *Stash) (err error) {
+	if p.Dir, err = filepath.Abs(p.Dir); err != nil {
+		return err
+	}
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("FSIMethods.StashPop", p, res))
+	}
+	ctx := context.TODO()
+
+	s, err := m.inst.fsi.PopStash(ctx, p.Dir, p.Index, p.Force)
+	if err != nil {
+		return err
+	}
+	*res = *s
+	return nil
+}
+
+// StashDrop removes stashed changes without applying them
+func (m *FSIMethods) StashDrop(p *StashParams, res *Stash) (err error) {
+	if p.Dir, err = filepath.Abs(p.Dir); err != nil {
+		return err
+	}
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("FSIMethods.StashDrop", p, res))
+	}
+	ctx := context.TODO()
+
+	s, err := m.inst.fsi.DropStash(ctx, p.Dir, p.Index)
+	if err != nil {
+		return err
+	}
+	*res = *s
+	return nil
+}
+
 // InitDatasetParams proxies parameters to initialization
 type InitDatasetParams = fsi.InitParams
 
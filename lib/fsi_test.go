@@ -2,11 +2,13 @@ package lib
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	cmp "github.com/google/go-cmp/cmp"
@@ -404,3 +406,132 @@ func listDirectory(path string) []string {
 	sort.Strings(contents)
 	return contents
 }
+
+func TestStash(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	if _, err := run.SaveWithParams(&SaveParams{
+		Ref:      "me/stash_ds",
+		BodyPath: "testdata/cities_2/body.csv",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(run.TmpDir, "stash_ds")
+	if err := run.Checkout("me/stash_ds", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewFSIMethods(run.Instance)
+	bodyPath := filepath.Join(dir, "body.csv")
+	readmePath := filepath.Join(dir, "readme.md")
+	headBody := run.MustReadFile(t, bodyPath)
+	editedBody := headBody + "new orleans,390000,42.5,true\n"
+
+	s := Stash{}
+	if err := m.StashPush(&StashParams{Dir: dir}, &s); err == nil {
+		t.Error("expected stashing a clean directory to error")
+	}
+
+	run.MustWriteFile(t, bodyPath, editedBody)
+	run.MustWriteFile(t, readmePath, "# cities\n")
+	if err := m.StashPush(&StashParams{Dir: dir, Message: "wip"}, &s); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"readme", "body"}, s.Components()); diff != "" {
+		t.Errorf("stashed components mismatch (-want +got):\n%s", diff)
+	}
+	if got := run.MustReadFile(t, bodyPath); got != headBody {
+		t.Errorf("expected stash to restore body. got:\n%s", got)
+	}
+	if _, err := os.Stat(readmePath); !os.IsNotExist(err) {
+		t.Error("expected stash to remove added readme")
+	}
+	if err := run.Instance.FSI().IsWorkingDirectoryClean(run.Ctx, dir); err != nil {
+		t.Errorf("expected working directory to be clean after stashing: %s", err)
+	}
+
+	// stashed files are kept in the repo filesystem, the index only lists paths
+	fs := run.Instance.Repo().Filesystem()
+	stashedPaths := []string{}
+	for _, ch := range s.Changes {
+		if ch.Filename == "" {
+			continue
+		}
+		if has, err := fs.Has(run.Ctx, ch.Path); err != nil || !has {
+			t.Errorf("expected stashed %s to be stored at %q", ch.Component, ch.Path)
+		}
+		stashedPaths = append(stashedPaths, ch.Path)
+	}
+	index, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(index), "new orleans") {
+		t.Errorf("expected stash index not to hold file contents, got:\n%s", index)
+	}
+
+	list := []Stash{}
+	if err := m.StashList(&StashParams{Dir: dir}, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Message != "wip" {
+		t.Fatalf("expected one stash with message 'wip', got: %v", list)
+	}
+
+	if err := m.StashPop(&StashParams{Dir: dir}, &s); err != nil {
+		t.Fatal(err)
+	}
+	if got := run.MustReadFile(t, bodyPath); got != editedBody {
+		t.Errorf("expected pop to apply stashed body. got:\n%s", got)
+	}
+	if got := run.MustReadFile(t, readmePath); got != "# cities\n" {
+		t.Errorf("expected pop to apply stashed readme. got:\n%s", got)
+	}
+	if err := m.StashList(&StashParams{Dir: dir}, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected pop to remove stash, got: %v", list)
+	}
+	for _, path := range stashedPaths {
+		if has, _ := fs.Has(run.Ctx, path); has {
+			t.Errorf("expected popped stash file %q to be deleted", path)
+		}
+	}
+
+	// committing a change to a stashed component since stashing is a conflict
+	if err := m.StashPush(&StashParams{Dir: dir}, &s); err != nil {
+		t.Fatal(err)
+	}
+	run.MustWriteFile(t, bodyPath, headBody+"boston,690000,36.9,true\n")
+	if _, err := run.SaveWithParams(&SaveParams{Ref: "me/stash_ds"}); err != nil {
+		t.Fatal(err)
+	}
+	err = m.StashPop(&StashParams{Dir: dir}, &s)
+	if err == nil {
+		t.Fatal("expected popping a stash with conflicts to error")
+	}
+	if err.Error() != "stash conflicts with changes to body" {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := m.StashPop(&StashParams{Dir: dir, Force: true}, &s); err != nil {
+		t.Fatal(err)
+	}
+	if got := run.MustReadFile(t, bodyPath); got != editedBody {
+		t.Errorf("expected forced pop to apply stashed body. got:\n%s", got)
+	}
+
+	if err := m.StashPush(&StashParams{Dir: dir}, &s); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.StashDrop(&StashParams{Dir: dir}, &s); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.StashDrop(&StashParams{Dir: dir}, &s); err == nil {
+		t.Error("expected dropping a missing stash to error")
+	}
+	if _, err := os.Stat(readmePath); !os.IsNotExist(err) {
+		t.Error("expected dropped stash not to be applied")
+	}
+}
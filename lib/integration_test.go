@@ -1,7 +1,10 @@
 package lib
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http/httptest"
 	"path/filepath"
@@ -9,6 +12,7 @@ import (
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	dsrefspec "github.com/qri-io/qri/dsref/spec"
@@ -208,6 +212,319 @@ def transform(ds, ctx):
 	}
 }
 
+func TestFetchMissingVersionIntegration(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_fetch_missing_version")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+
+	// - nasim creates & publishes two versions of a dataset
+	first := InitWorldBankDataset(t, nasim)
+	PushToRegistry(t, nasim, first.Alias())
+	ref := Commit2WorldBank(t, nasim)
+	PushToRegistry(t, nasim, ref.Alias())
+
+	// - hinshun pulls only the latest version
+	hinshun := tr.InitHinshun(t)
+	Pull(t, hinshun, ref.Alias())
+
+	if local, err := hinshun.qfs.Has(tr.Ctx, first.Path); err != nil {
+		t.Fatal(err)
+	} else if local {
+		t.Fatalf("expected hinshun not to have the first version before getting it")
+	}
+
+	// - getting the first version fetches it from the registry nasim pushed to
+	res := &GetResult{}
+	p := &GetParams{Refstr: first.String(), Selector: "body", Format: "json", All: true}
+	if err := NewDatasetMethods(hinshun).Get(p, res); err != nil {
+		t.Fatal(err)
+	}
+	expect := `[["a","b","c",true,2],["d","e",false,false,3]]`
+	if string(res.Bytes) != expect {
+		t.Errorf("first version body mismatch. want: %s, got: %s", expect, string(res.Bytes))
+	}
+
+	if local, err := hinshun.qfs.Has(tr.Ctx, first.Path); err != nil {
+		t.Fatal(err)
+	} else if !local {
+		t.Errorf("expected first version to be stored locally after getting it")
+	}
+}
+
+func TestSparsePullIntegration(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_sparse_pull")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+
+	// - nasim creates & publishes a dataset
+	ref := InitWorldBankDataset(t, nasim)
+	PushToRegistry(t, nasim, ref.Alias())
+
+	// - hinshun pulls metadata only
+	hinshun := tr.InitHinshun(t)
+	pulled := &dataset.Dataset{}
+	p := &PullParams{Ref: ref.Alias(), Components: remote.SparseComponents}
+	if err := NewDatasetMethods(hinshun).Pull(p, pulled); err != nil {
+		t.Fatal(err)
+	}
+	if pulled.Meta == nil || pulled.Meta.Title != "World Bank Population" {
+		t.Errorf("expected sparse pull to fetch meta, got: %v", pulled.Meta)
+	}
+
+	if local, err := hinshun.qfs.Has(tr.Ctx, ref.Path); err != nil {
+		t.Fatal(err)
+	} else if !local {
+		t.Fatal("expected sparse pull to store the version")
+	}
+	if !hinshun.isSparseVersion(tr.Ctx, ref.Path) {
+		t.Fatal("expected sparse pull to leave the version partially stored")
+	}
+
+	items := []DatasetLogItem{}
+	if err := NewLogMethods(hinshun).Log(&LogParams{Ref: ref.Alias()}, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !items[0].Sparse {
+		t.Errorf("expected log to show a single sparse version, got: %v", items)
+	}
+
+	// - getting the body completes the version from the registry
+	res := &GetResult{}
+	gp := &GetParams{Refstr: ref.Alias(), Selector: "body", Format: "json", All: true}
+	if err := NewDatasetMethods(hinshun).Get(gp, res); err != nil {
+		t.Fatal(err)
+	}
+	expect := `[["a","b","c",true,2],["d","e",false,false,3]]`
+	if string(res.Bytes) != expect {
+		t.Errorf("body mismatch. want: %s, got: %s", expect, string(res.Bytes))
+	}
+	if hinshun.isSparseVersion(tr.Ctx, ref.Path) {
+		t.Error("expected getting the body to complete the version")
+	}
+}
+
+func TestTagIntegration(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_tag")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+
+	// - nasim creates two versions & tags the first
+	first := InitWorldBankDataset(t, nasim)
+	Commit2WorldBank(t, nasim)
+	tag := &VersionTag{}
+	if err := NewLogMethods(nasim).Tag(&TagParams{Ref: first.Alias() + "~1", Name: "v1.0"}, tag); err != nil {
+		t.Fatal(err)
+	}
+	if tag.Path != first.Path {
+		t.Errorf("expected tag to name the first version. want: %q, got: %q", first.Path, tag.Path)
+	}
+	PushToRegistry(t, nasim, first.Alias())
+
+	// - hinshun pulls, and sees the tag
+	hinshun := tr.InitHinshun(t)
+	Pull(t, hinshun, first.Alias())
+
+	tags := []VersionTag{}
+	if err := NewLogMethods(hinshun).TagList(&TagListParams{Ref: first.Alias()}, &tags); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Name != "v1.0" || tags[0].Path != first.Path {
+		t.Errorf("expected pulled dataset to have tag v1.0 -> %s, got: %v", first.Path, tags)
+	}
+
+	ref, _, err := hinshun.ParseAndResolveRef(tr.Ctx, first.Alias()+"@v1.0", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Path != first.Path {
+		t.Errorf("expected tag reference to resolve to %q, got: %q", first.Path, ref.Path)
+	}
+
+	// - hinshun can't tag nasim's dataset
+	if err := NewLogMethods(hinshun).Tag(&TagParams{Ref: first.Alias(), Name: "mine"}, tag); err == nil {
+		t.Error("expected tagging another author's dataset to fail")
+	}
+}
+
+func TestRemoteBodyPageIntegration(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_remote_body_page")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+
+	// - nasim creates & publishes a dataset
+	ref := InitWorldBankDataset(t, nasim)
+	PushToRegistry(t, nasim, ref.Alias())
+
+	// - hinshun reads pages of the body from the registry
+	hinshun := tr.InitHinshun(t)
+	cases := []struct {
+		description   string
+		offset, limit int
+		all           bool
+		columns       []string
+		expect        string
+	}{
+		{"all", 0, 0, true, nil, `[["a","b","c",true,2],["d","e",false,false,3]]`},
+		{"second page", 1, 1, false, nil, `[["d","e",false,false,3]]`},
+		{"columns", 0, 2, false, []string{"field_5", "field_1"}, `[[2,"a"],[3,"d"]]`},
+	}
+	for _, c := range cases {
+		res := &GetResult{}
+		p := &GetParams{
+			Refstr:   ref.Alias(),
+			Selector: "body",
+			Format:   "json",
+			Remote:   "registry",
+			Offset:   c.offset,
+			Limit:    c.limit,
+			All:      c.all,
+			Columns:  c.columns,
+		}
+		if err := NewDatasetMethods(hinshun).Get(p, res); err != nil {
+			t.Errorf("case %q: unexpected error: %s", c.description, err)
+			continue
+		}
+		if string(res.Bytes) != c.expect {
+			t.Errorf("case %q: body mismatch. want: %s, got: %s", c.description, c.expect, string(res.Bytes))
+		}
+	}
+
+	// - getting all entries reads every page of a body
+	buf := &bytes.Buffer{}
+	rows := base.MaxBodyPageRows*2 + 500
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(buf, "row_%d,%d\n", i, i)
+	}
+	large := &dataset.Dataset{}
+	if err := NewDatasetMethods(nasim).Save(&SaveParams{
+		Ref:     "me/large_body",
+		Dataset: &dataset.Dataset{BodyPath: "body.csv", BodyBytes: buf.Bytes()},
+	}, large); err != nil {
+		t.Fatal(err)
+	}
+	largeRef := dsref.ConvertDatasetToVersionInfo(large).SimpleRef()
+	PushToRegistry(t, nasim, largeRef.Alias())
+
+	res := &GetResult{}
+	p := &GetParams{Refstr: largeRef.Alias(), Selector: "body", Format: "json", Remote: "registry", All: true}
+	if err := NewDatasetMethods(hinshun).Get(p, res); err != nil {
+		t.Fatal(err)
+	}
+	got := [][]interface{}{}
+	if err := json.Unmarshal(res.Bytes, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != rows {
+		t.Errorf("expected getting all entries to read %d rows, got: %d", rows, len(got))
+	} else if last := got[rows-1]; last[0] != fmt.Sprintf("row_%d", rows-1) {
+		t.Errorf("last row mismatch. got: %v", last)
+	}
+
+	// - reading a remote body doesn't store the dataset
+	if local, err := hinshun.qfs.Has(tr.Ctx, ref.Path); err != nil {
+		t.Fatal(err)
+	} else if local {
+		t.Error("expected reading a remote body page not to pull the dataset")
+	}
+}
+
+func TestEvictedVersionFetchIntegration(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_evicted_version")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+
+	// - nasim creates & publishes two versions of a dataset
+	first := InitWorldBankDataset(t, nasim)
+	PushToRegistry(t, nasim, first.Alias())
+	ref := Commit2WorldBank(t, nasim)
+	PushToRegistry(t, nasim, ref.Alias())
+
+	// - nasim evicts all but the latest version
+	evicted, err := base.EvictVersions(tr.Ctx, nasim.repo, ref, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) != 1 || evicted[0] != first.Path {
+		t.Fatalf("expected first version to be evicted, got: %v", evicted)
+	}
+	if local, err := nasim.qfs.Has(tr.Ctx, first.Path); err != nil {
+		t.Fatal(err)
+	} else if local {
+		t.Fatalf("expected evicted version to be removed from local storage")
+	}
+
+	// - getting the evicted version fetches it from the registry
+	res := &GetResult{}
+	p := &GetParams{Refstr: first.String(), Selector: "body", Format: "json", All: true}
+	if err := NewDatasetMethods(nasim).Get(p, res); err != nil {
+		t.Fatal(err)
+	}
+	expect := `[["a","b","c",true,2],["d","e",false,false,3]]`
+	if string(res.Bytes) != expect {
+		t.Errorf("evicted version body mismatch. want: %s, got: %s", expect, string(res.Bytes))
+	}
+
+	if local, err := nasim.qfs.Has(tr.Ctx, first.Path); err != nil {
+		t.Fatal(err)
+	} else if !local {
+		t.Errorf("expected evicted version to be stored locally after getting it")
+	}
+}
+
+func TestSparsePullSourceIntegration(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_sparse_pull_source")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+
+	// - nasim creates & publishes a dataset
+	ref := InitWorldBankDataset(t, nasim)
+	PushToRegistry(t, nasim, ref.Alias())
+
+	// - hinshun pulls metadata only, recording where the version came from
+	hinshun := tr.InitHinshun(t)
+	p := &PullParams{Ref: ref.Alias(), Components: remote.SparseComponents}
+	if err := NewDatasetMethods(hinshun).Pull(p, &dataset.Dataset{}); err != nil {
+		t.Fatal(err)
+	}
+	source := hinshun.sparseSources.get(ref.Path)
+	if source != tr.RegistryHTTPServer.URL {
+		t.Fatalf("expected sparse pull to record the registry as source. want: %q, got: %q", tr.RegistryHTTPServer.URL, source)
+	}
+
+	// - without a known remote, the partial version can't be completed
+	hinshun.sparseSources.remove(ref.Path)
+	unknown := dsref.Ref{Path: ref.Path}
+	if err := hinshun.completeVersion(tr.Ctx, unknown); !errors.Is(err, errNoVersionRemote) {
+		t.Errorf("expected completing a version with no known remote to fail with %q, got: %v", errNoVersionRemote, err)
+	}
+
+	// - the recorded source completes the version, even when history doesn't
+	// list it
+	hinshun.sparseSources.put(ref.Path, source)
+	if err := hinshun.completeVersion(tr.Ctx, unknown); err != nil {
+		t.Fatal(err)
+	}
+	if hinshun.isSparseVersion(tr.Ctx, ref.Path) {
+		t.Error("expected version to be complete")
+	}
+	if got := hinshun.sparseSources.get(ref.Path); got != "" {
+		t.Errorf("expected completed version source to be dropped, got: %q", got)
+	}
+
+	// - sources are persisted in the repo
+	hinshun.sparseSources.put(ref.Path, source)
+	reopened := openSparseSources(filepath.Join(hinshun.RepoPath(), sparseSourcesFilename))
+	if got := reopened.get(ref.Path); got != source {
+		t.Errorf("expected persisted source %q, got: %q", source, got)
+	}
+}
+
 type NetworkIntegrationTestRunner struct {
 	Ctx        context.Context
 	prefix     string
@@ -31,6 +31,7 @@ import (
 	"github.com/qri-io/qri/event"
 	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/fsi/hiddenfile"
+	"github.com/qri-io/qri/lineage"
 	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/registry/regclient"
@@ -453,7 +454,12 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 	if inst.repo != nil {
 		// Try to make the repo a hidden directory, but it's okay if we can't. Ignore the error.
 		_ = hiddenfile.SetFileHidden(inst.repoPath)
-		inst.fsi = fsi.NewFSI(inst.repo, inst.bus)
+		inst.fsi = fsi.NewFSI(inst.repo, inst.bus, inst.repoPath)
+		inst.autosave = newAutosaver(inst, inst.bus)
+		if inst.lineage, err = lineage.Open(ctx, inst.repo, inst.bus, lineage.IndexPath(inst.repoPath)); err != nil {
+			return nil, fmt.Errorf("opening lineage index: %w", err)
+		}
+		inst.sparseSources = openSparseSources(filepath.Join(inst.repoPath, sparseSourcesFilename))
 	}
 
 	if inst.dscache == nil {
@@ -620,7 +626,7 @@ func NewInstanceFromConfigAndNodeAndBus(ctx context.Context, cfg *config.Config,
 		panic(err)
 	}
 
-	fsint := fsi.NewFSI(r, bus)
+	fsint := fsi.NewFSI(r, bus, "")
 	dc := dscache.NewDscache(ctx, r.Filesystem(), bus, pro.Peername, "")
 
 	// TODO (b5) - lots of tests pass "DefaultConfigForTesting", which uses a different peername /
@@ -647,6 +653,10 @@ func NewInstanceFromConfigAndNodeAndBus(ctx context.Context, cfg *config.Config,
 		inst.bus = bus
 		inst.fsi = fsint
 		inst.qfs = r.Filesystem()
+		inst.sparseSources = openSparseSources("")
+		if bus != nil {
+			inst.autosave = newAutosaver(inst, bus)
+		}
 	}
 
 	inst.remoteClient, err = remote.NewClient(ctx, node, inst.bus)
@@ -671,6 +681,7 @@ func NewInstanceFromConfigAndNodeAndBus(ctx context.Context, cfg *config.Config,
 // ecosystem. Create an Instance pointer with NewInstance
 type Instance struct {
 	repoPath string
+	cfgLk    sync.RWMutex
 	cfg      *config.Config
 
 	streams         ioes.IOStreams
@@ -684,8 +695,11 @@ type Instance struct {
 	stats           *stats.Service
 	logbook         *logbook.Book
 	dscache         *dscache.Dscache
+	lineage         *lineage.Index
+	sparseSources   *sparseSources
 	bus             event.Bus
 	watcher         *watchfs.FilesysWatcher
+	autosave        *autosaver
 	remoteOptsFuncs []remote.OptionsFunc
 
 	rpc *rpc.Client
@@ -749,6 +763,8 @@ func (inst *Instance) Config() *config.Config {
 	if inst == nil {
 		return nil
 	}
+	inst.cfgLk.RLock()
+	defer inst.cfgLk.RUnlock()
 	return inst.cfg
 }
 
@@ -786,11 +802,43 @@ func (inst *Instance) FSI() *fsi.FSI {
 
 // ChangeConfig implements the ConfigSetter interface
 func (inst *Instance) ChangeConfig(cfg *config.Config) (err error) {
-	cfg = cfg.WithPrivateValues(inst.cfg)
+	inst.cfgLk.Lock()
+	defer inst.cfgLk.Unlock()
+	return inst.writeConfig(cfg.WithPrivateValues(inst.cfg))
+}
+
+// updateConfig applies update to a copy of the instance config and persists
+// the result. The config lock is held throughout, so changes made while
+// update runs can't be lost. Like ChangeConfig, private values are kept
+func (inst *Instance) updateConfig(update func(cfg *config.Config) error) error {
+	inst.cfgLk.Lock()
+	defer inst.cfgLk.Unlock()
+	cfg := inst.cfg.Copy()
+	if err := update(cfg); err != nil {
+		return err
+	}
+	return inst.writeConfig(cfg.WithPrivateValues(inst.cfg))
+}
 
+// changePrivateConfig applies update to a copy of the instance config and
+// persists the result. Unlike ChangeConfig, private values like keys can be
+// changed
+func (inst *Instance) changePrivateConfig(update func(cfg *config.Config) error) error {
+	inst.cfgLk.Lock()
+	defer inst.cfgLk.Unlock()
+	cfg := inst.cfg.Copy()
+	if err := update(cfg); err != nil {
+		return err
+	}
+	return inst.writeConfig(cfg)
+}
+
+// writeConfig persists cfg & makes it the instance config. callers must hold
+// cfgLk
+func (inst *Instance) writeConfig(cfg *config.Config) error {
 	if path := inst.cfg.Path(); path != "" {
-		if err = cfg.WriteToFile(path); err != nil {
-			return
+		if err := cfg.WriteToFile(path); err != nil {
+			return err
 		}
 	}
 
@@ -63,10 +63,16 @@ func (inst *Instance) loadLocalDataset(ctx context.Context, ref dsref.Ref) (*dat
 			return nil, err
 		}
 	} else {
+		if err = inst.fetchMissingVersion(ctx, ref); err != nil {
+			return nil, err
+		}
 		// Load from dsfs
 		if ds, err = dsfs.LoadDataset(ctx, inst.qfs.DefaultWriteFS(), ref.Path); err != nil {
 			return nil, err
 		}
+		if err = inst.deferMissingFiles(ctx, ref, ds); err != nil {
+			return nil, err
+		}
 	}
 	// Set transient info on the returned dataset
 	ds.Name = ref.Name
@@ -80,6 +86,105 @@ func (inst *Instance) loadLocalDataset(ctx context.Context, ref dsref.Ref) (*dat
 	return ds, nil
 }
 
+// errNoVersionRemote indicates no remote is known to hold a dataset version
+var errNoVersionRemote = errors.New("no remote is known to hold this version")
+
+// fetchMissingVersion pulls a dataset version that's recorded in history but
+// missing from local storage, trying each remote the logbook says holds the
+// version. It's a no-op when the version is stored locally, or when no remote
+// is known to hold it
+func (inst *Instance) fetchMissingVersion(ctx context.Context, ref dsref.Ref) error {
+	if local, err := inst.qfs.Has(ctx, ref.Path); err != nil || local {
+		return nil
+	}
+	if err := inst.pullVersionFromRemotes(ctx, ref); err != nil && !errors.Is(err, errNoVersionRemote) {
+		return err
+	}
+	return nil
+}
+
+// pullVersionFromRemotes fetches the complete data of a dataset version from
+// the first remote known to hold the version that can deliver it
+func (inst *Instance) pullVersionFromRemotes(ctx context.Context, ref dsref.Ref) error {
+	remotes := inst.versionRemotes(ctx, ref)
+	if inst.remoteClient == nil || len(remotes) == 0 {
+		return fmt.Errorf("fetching version %s: %w", ref.Path, errNoVersionRemote)
+	}
+
+	var err error
+	for _, addr := range remotes {
+		log.Debugf("fetching version %q from remote %q", ref.Path, addr)
+		if err = inst.remoteClient.PullDatasetVersion(ctx, ref, addr); err == nil {
+			inst.sparseSources.remove(ref.Path)
+			return nil
+		}
+		log.Debugf("fetching version %q from remote %q: %s", ref.Path, addr, err)
+	}
+	return fmt.Errorf("fetching version %s from remote: %w", ref.Path, err)
+}
+
+// versionRemotes lists the addresses of remotes that may hold a dataset
+// version: the remote a sparse pull fetched the version from, followed by
+// remotes the logbook says the version has been pushed to
+func (inst *Instance) versionRemotes(ctx context.Context, ref dsref.Ref) []string {
+	var addrs []string
+	source := inst.sparseSources.get(ref.Path)
+	if source != "" {
+		addrs = append(addrs, source)
+	}
+	if inst.logbook == nil {
+		return addrs
+	}
+
+	initID := ref.InitID
+	if initID == "" {
+		var err error
+		if initID, err = inst.logbook.RefToInitID(dsref.Ref{Username: ref.Username, Name: ref.Name}); err != nil {
+			return addrs
+		}
+	}
+	pushed, err := inst.logbook.VersionRemotes(ctx, initID, ref.Path)
+	if err != nil {
+		log.Debugf("listing remotes of version %q: %s", ref.Path, err)
+		return addrs
+	}
+	for _, addr := range pushed {
+		if addr != source {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// evictVersions drops older versions of a dataset from local storage when
+// the repo is configured to keep a limited number of versions. Eviction is
+// housekeeping, failures are logged instead of returned
+func (inst *Instance) evictVersions(ctx context.Context, ref dsref.Ref) {
+	if inst.cfg.Repo == nil || inst.cfg.Repo.KeepVersions < 1 {
+		return
+	}
+	evicted, err := base.EvictVersions(ctx, inst.repo, ref, inst.cfg.Repo.KeepVersions)
+	if err != nil {
+		log.Debugf("evicting versions of %q: %s", ref.Human(), err)
+		return
+	}
+	if len(evicted) > 0 {
+		log.Debugf("evicted %d versions of %q", len(evicted), ref.Human())
+	}
+}
+
+// evictVersion drops a single dataset version from local storage, keeping it
+// if it's among the versions the repo is configured to keep
+func (inst *Instance) evictVersion(ctx context.Context, ref dsref.Ref) {
+	keep := 1
+	if inst.cfg.Repo != nil && inst.cfg.Repo.KeepVersions > keep {
+		keep = inst.cfg.Repo.KeepVersions
+	}
+	if _, err := base.EvictVersion(ctx, inst.repo, ref, keep); err != nil {
+		log.Debugf("evicting version %q: %s", ref.Path, err)
+	}
+}
+
 // NewParseResolveLoadFunc generates a dsref.ParseResolveLoad function from an
 // instance
 func (inst *Instance) NewParseResolveLoadFunc(remote string) (dsref.ParseResolveLoad, error) {
@@ -6,6 +6,8 @@ import (
 
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/lineage"
 	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/repo"
 )
@@ -72,6 +74,9 @@ func (m *LogMethods) Log(params *LogParams, res *[]DatasetLogItem) error {
 	if source == "" {
 		// local resolution
 		*res, err = base.DatasetLog(ctx, m.inst.repo, ref, params.Limit, params.Offset, true)
+		for i, item := range *res {
+			(*res)[i].Sparse = !item.Foreign && m.inst.isSparseVersion(ctx, item.Path)
+		}
 		return err
 	}
 
@@ -103,6 +108,7 @@ func (m *LogMethods) Log(params *LogParams, res *[]DatasetLogItem) error {
 			continue
 		}
 		items[i].Foreign = !local
+		items[i].Sparse = local && m.inst.isSparseVersion(ctx, item.Path)
 
 		if local {
 			if ds, err := dsfs.LoadDataset(ctx, m.inst.repo.Filesystem(), item.Path); err == nil {
@@ -173,3 +179,56 @@ func (m *LogMethods) LogbookSummary(p *struct{}, res *string) (err error) {
 	*res = m.inst.repo.Logbook().SummaryString(ctx)
 	return nil
 }
+
+// LineageParams defines parameters for the Lineage method
+type LineageParams struct {
+	// Reference to the dataset or dataset version to trace
+	Ref string
+	// Direction to walk the graph, one of "upstream" or "downstream"
+	Direction string
+}
+
+// Lineage returns a graph of the datasets that feed into, or are fed by, a
+// dataset, as recorded by transform scripts
+func (m *LogMethods) Lineage(p *LineageParams, res *lineage.Graph) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("LogMethods.Lineage", p, res))
+	}
+	ctx := context.TODO()
+
+	ref, err := dsref.Parse(p.Ref)
+	if err != nil {
+		return err
+	}
+	versioned := ref.Path != "" || ref.Revision != ""
+	if _, err := m.inst.ResolveReference(ctx, &ref, "local"); err != nil {
+		return err
+	}
+	// only trace a specific version if one was asked for
+	if !versioned {
+		ref.Path = ""
+	}
+
+	idx := m.inst.lineage
+	if idx == nil {
+		if idx, err = lineage.Build(ctx, m.inst.repo); err != nil {
+			return err
+		}
+	}
+
+	var g *lineage.Graph
+	switch p.Direction {
+	case "upstream", "":
+		g, err = idx.Upstream(ref)
+	case "downstream":
+		g, err = idx.Downstream(ref)
+	default:
+		err = fmt.Errorf("invalid lineage direction %q, must be one of: upstream, downstream", p.Direction)
+	}
+	if err != nil {
+		return err
+	}
+
+	*res = *g
+	return nil
+}
@@ -2,11 +2,14 @@ package lib
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 
+	crypto "github.com/libp2p/go-libp2p-core/crypto"
+	peer "github.com/libp2p/go-libp2p-core/peer"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/registry"
@@ -158,6 +161,122 @@ func (m *ProfileMethods) SaveProfile(p *config.ProfilePod, res *config.ProfilePo
 	return m.inst.ChangeConfig(cfg)
 }
 
+// RotateKeyParams defines parameters for the RotateKey method
+type RotateKeyParams struct {
+	// PrivKey is the base64-encoded private key to rotate to
+	PrivKey string
+}
+
+// RotateKey replaces the private key of this peer's profile. The rotation is
+// signed by the current key and recorded in the author's logbook, which is
+// re-encrypted with the new key. The new key is saved to config first, then
+// a configured registry is told of it, then the logbook is rotated. When a
+// step fails the steps before it are undone. Profile ID stays the same, peers
+// verify history signed by earlier keys through the chain of rotations. The
+// repo signs with the new key right away. Keys can't be rotated while the p2p
+// node is online, it picks up the new key the next time it starts
+func (m *ProfileMethods) RotateKey(p *RotateKeyParams, res *config.ProfilePod) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("ProfileMethods.RotateKey", p, res))
+	}
+	ctx := context.TODO()
+
+	if p == nil || p.PrivKey == "" {
+		return fmt.Errorf("private key is required")
+	}
+	data, err := base64.StdEncoding.DecodeString(p.PrivKey)
+	if err != nil {
+		return fmt.Errorf("decoding private key: %w", err)
+	}
+	next, err := crypto.UnmarshalPrivateKey(data)
+	if err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+	prev := m.inst.repo.PrivateKey()
+	if prev == nil {
+		return fmt.Errorf("profile has no private key to rotate")
+	}
+	if prev.Equals(next) {
+		return fmt.Errorf("new key is the same as the current key")
+	}
+	// a running p2p node can't change the key it identifies with, and would
+	// keep using the replaced key until it restarts
+	if m.inst.node != nil && m.inst.node.Online {
+		return fmt.Errorf("can't rotate keys while connected to the network, stop qri connect first")
+	}
+
+	nextPid, err := peer.IDFromPublicKey(next.GetPublic())
+	if err != nil {
+		return err
+	}
+
+	// persist the new key before re-encrypting the logbook with it, a config
+	// that only has the previous key can't read the logbook afterward
+	var prevProfileKey, prevP2PKey, prevPeerID string
+	err = m.inst.changePrivateConfig(func(cfg *config.Config) error {
+		prevProfileKey = cfg.Profile.PrivKey
+		cfg.Profile.PrivKey = p.PrivKey
+		// the p2p node shares the profile key by default, the new peer ID is
+		// used the next time the node starts
+		if cfg.P2P != nil && cfg.P2P.PrivKey == prevProfileKey {
+			prevP2PKey, prevPeerID = cfg.P2P.PrivKey, cfg.P2P.PeerID
+			cfg.P2P.PrivKey = p.PrivKey
+			cfg.P2P.PeerID = nextPid.Pretty()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	rollback := func() {
+		err := m.inst.changePrivateConfig(func(cfg *config.Config) error {
+			cfg.Profile.PrivKey = prevProfileKey
+			if prevP2PKey != "" && cfg.P2P != nil {
+				cfg.P2P.PrivKey, cfg.P2P.PeerID = prevP2PKey, prevPeerID
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorf("restoring the previous key to config: %s", err)
+		}
+	}
+
+	cfg := m.inst.Config()
+	reg := m.inst.registry
+	regPro := &registry.Profile{Username: cfg.Profile.Peername, ProfileID: cfg.Profile.ID}
+	if reg != nil {
+		if _, err := reg.RotateProfileKey(regPro, prev, next); err == registry.ErrNoRegistry {
+			reg = nil
+		} else if err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	if err := m.inst.logbook.WriteKeyRotation(ctx, next); err != nil {
+		if reg != nil {
+			if _, err := reg.RotateProfileKey(regPro, next, prev); err != nil {
+				log.Errorf("restoring the previous key to the registry: %s", err)
+			}
+		}
+		rollback()
+		return err
+	}
+
+	cfg = m.inst.Config()
+	pro, err := profile.NewProfile(cfg.Profile)
+	if err != nil {
+		return err
+	}
+	if err := m.inst.repo.SetProfile(pro); err != nil {
+		return err
+	}
+
+	*res = *cfg.Profile
+	res.PrivKey = ""
+	return nil
+}
+
 // ProfilePhoto fetches the byte slice of a given user's profile photo
 func (m *ProfileMethods) ProfilePhoto(req *config.ProfilePod, res *[]byte) (err error) {
 	if m.inst.rpc != nil {
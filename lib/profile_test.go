@@ -2,6 +2,8 @@ package lib
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -9,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/event"
 	"github.com/qri-io/qri/p2p"
@@ -254,6 +257,109 @@ func TestProfileRequestsSetPeername(t *testing.T) {
 	})
 }
 
+func TestProfileRequestsRotateKey(t *testing.T) {
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	reg := regmock.NewMemRegistry(nil)
+	node := newTestQriNode(t)
+
+	pro, err := node.Repo.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp, err := pro.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfigForTesting()
+	cfg.Profile = pp
+	cfg.P2P.PrivKey = pp.PrivKey
+
+	// TODO (b5) - hack until tests have better instance-generation primitives
+	inst := NewInstanceFromConfigAndNode(ctx, cfg, node)
+
+	regCli, _ := regmock.NewMockServerRegistry(reg)
+	inst.registry = regCli
+	if _, err := regCli.PutProfile(&registry.Profile{Username: pro.Peername}, pro.PrivKey); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewProfileMethods(inst)
+	res := &config.ProfilePod{}
+	if err := m.RotateKey(&RotateKeyParams{}, res); err == nil {
+		t.Error("expected rotating without a key to fail")
+	}
+	if err := m.RotateKey(&RotateKeyParams{PrivKey: pp.PrivKey}, res); err == nil {
+		t.Error("expected rotating to the current key to fail")
+	}
+
+	next, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := crypto.MarshalPrivateKey(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextB64 := base64.StdEncoding.EncodeToString(data)
+
+	// an online node keeps identifying with the current key
+	node.Online = true
+	if err := m.RotateKey(&RotateKeyParams{PrivKey: nextB64}, res); err == nil {
+		t.Error("expected rotating while online to fail")
+	}
+	node.Online = false
+	if !inst.repo.PrivateKey().Equals(pro.PrivKey) {
+		t.Error("expected repo private key to remain the current key")
+	}
+
+	// a config that can't be written leaves the logbook encrypted with the
+	// current key
+	inst.cfg.SetPath(filepath.Join(os.TempDir(), "qri_missing_dir", "nested", "config.yaml"))
+	if err := m.RotateKey(&RotateKeyParams{PrivKey: nextB64}, res); err == nil {
+		t.Error("expected rotating with an unwritable config to fail")
+	}
+	if !inst.logbook.AuthorPubKey().Equals(pro.PrivKey.GetPublic()) {
+		t.Error("expected logbook author key to remain the current key")
+	}
+	if inst.cfg.Profile.PrivKey != pp.PrivKey {
+		t.Error("expected config profile key to remain the current key")
+	}
+	inst.cfg.SetPath("")
+
+	if err := m.RotateKey(&RotateKeyParams{PrivKey: nextB64}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.PrivKey != "" {
+		t.Error("expected result to not include a private key")
+	}
+	if res.ID != pp.ID {
+		t.Errorf("expected rotation to keep profile ID %q, got %q", pp.ID, res.ID)
+	}
+	if !inst.repo.PrivateKey().Equals(next) {
+		t.Error("expected repo private key to be the new key")
+	}
+	if !inst.logbook.AuthorPubKey().Equals(next.GetPublic()) {
+		t.Error("expected logbook author key to be the new key")
+	}
+	if inst.cfg.Profile.PrivKey != nextB64 || inst.cfg.P2P.PrivKey != nextB64 {
+		t.Error("expected config profile & p2p keys to be the new key")
+	}
+
+	regPro, err := reg.Profiles.Load(pro.Peername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubBytes, err := next.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if regPro.PublicKey != base64.StdEncoding.EncodeToString(pubBytes) {
+		t.Error("expected registry profile to have the new public key")
+	}
+}
+
 func TestProfileRequestsSetProfilePhoto(t *testing.T) {
 	ctx, done := context.WithCancel(context.Background())
 	defer done()
@@ -0,0 +1,135 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
+	qrierr "github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/repo"
+)
+
+// PruneParams defines parameters for the Prune method
+type PruneParams struct {
+	// Reference to the dataset to prune
+	Ref string
+	// prune all datasets in the repo the author owns instead of Ref
+	All bool
+	// Policy overrides configured retention policies
+	Policy *config.RetentionPolicy
+	// list versions that would be pruned without removing them
+	DryRun bool
+}
+
+// PruneResult lists the versions pruned from a dataset
+type PruneResult struct {
+	Ref    string           `json:"ref"`
+	Pruned []DatasetLogItem `json:"pruned"`
+}
+
+// Prune removes versions of datasets a retention policy doesn't keep from
+// dataset history & local storage. Policies come from the retention section
+// of the config unless the params provide one. Pruned versions are recorded
+// in the logbook so peers see them removed when they sync
+func (m *DatasetMethods) Prune(p *PruneParams, res *[]PruneResult) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Prune", p, res))
+	}
+	ctx := context.TODO()
+
+	if p.Policy != nil {
+		if err := p.Policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	var refs []dsref.Ref
+	if p.All {
+		if p.Ref != "" {
+			return fmt.Errorf("can't prune all datasets and a dataset reference")
+		}
+		num, err := m.inst.repo.RefCount()
+		if err != nil {
+			return err
+		}
+		infos, err := repo.ListVersionInfoShim(m.inst.repo, 0, num)
+		if err != nil {
+			return err
+		}
+		for _, vi := range infos {
+			// only the author of a dataset can change its history
+			if vi.Username == m.inst.cfg.Profile.Peername {
+				refs = append(refs, vi.SimpleRef())
+			}
+		}
+	} else {
+		ref, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+		if err != nil {
+			return err
+		}
+		if p.Policy.KeepsAll() && m.inst.retentionPolicy(ref).KeepsAll() {
+			return qrierr.New(fmt.Errorf("no retention policy for %s", ref.Human()), fmt.Sprintf("no retention policy applies to %s. configure one in the retention section of your config, or pass keep flags", ref.Human()))
+		}
+		refs = append(refs, ref)
+	}
+
+	results := []PruneResult{}
+	for _, ref := range refs {
+		policy := p.Policy
+		if policy.KeepsAll() {
+			policy = m.inst.retentionPolicy(ref)
+		}
+		if policy.KeepsAll() {
+			continue
+		}
+		pruned, err := m.inst.pruneVersions(ctx, ref, policy, p.DryRun)
+		if err != nil {
+			return fmt.Errorf("pruning %s: %w", ref.Human(), err)
+		}
+		if len(pruned) > 0 {
+			results = append(results, PruneResult{Ref: ref.Human(), Pruned: pruned})
+		}
+	}
+	*res = results
+	return nil
+}
+
+// retentionPolicy returns the configured retention policy for a dataset, nil
+// if all versions are kept
+func (inst *Instance) retentionPolicy(ref dsref.Ref) *config.RetentionPolicy {
+	return inst.cfg.Retention.Policy(ref.Human())
+}
+
+func (inst *Instance) pruneVersions(ctx context.Context, ref dsref.Ref, policy *config.RetentionPolicy, dryRun bool) ([]DatasetLogItem, error) {
+	ref = dsref.Ref{Username: ref.Username, Name: ref.Name, ProfileID: ref.ProfileID, InitID: ref.InitID}
+	if ref.InitID == "" {
+		initID, err := inst.logbook.RefToInitID(ref)
+		if err != nil {
+			return nil, err
+		}
+		ref.InitID = initID
+	}
+	return base.PruneVersions(ctx, inst.repo, ref, policy, dryRun)
+}
+
+// pruneOnSave prunes dataset history after a save when the repo is configured
+// to. Pruning is housekeeping, failures are logged instead of returned
+func (inst *Instance) pruneOnSave(ctx context.Context, ref dsref.Ref) {
+	if inst.cfg.Retention == nil || !inst.cfg.Retention.PruneOnSave {
+		return
+	}
+	policy := inst.retentionPolicy(ref)
+	if policy.KeepsAll() {
+		return
+	}
+	pruned, err := inst.pruneVersions(ctx, ref, policy, false)
+	if err != nil {
+		log.Debugf("pruning versions of %q: %s", ref.Human(), err)
+		return
+	}
+	if len(pruned) > 0 {
+		log.Debugf("pruned %d versions of %q", len(pruned), ref.Human())
+	}
+}
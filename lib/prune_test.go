@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
+)
+
+func TestPruneOnSave(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	run.Instance.cfg.Retention = &config.Retention{
+		PruneOnSave: true,
+		Datasets:    []*config.RetentionPolicy{{Ref: "peer/pruned", KeepLast: 2}},
+	}
+	bodies := []string{
+		"testdata/cities_2/body.csv",
+		"testdata/jobs_by_automation/body.csv",
+		"testdata/jobs_by_automation_2/body.csv",
+	}
+	for _, body := range bodies {
+		run.MustSaveFromBody(t, "pruned", body)
+		run.MustSaveFromBody(t, "unpruned", body)
+	}
+
+	for name, expect := range map[string]int{"pruned": 2, "unpruned": 3} {
+		items, err := run.Instance.logbook.Items(run.Ctx, dsref.Ref{Username: "peer", Name: name}, 0, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != expect {
+			t.Errorf("expected %s to have %d versions, got %d", name, expect, len(items))
+		}
+	}
+
+	m := NewDatasetMethods(run.Instance)
+	res := []PruneResult{}
+	if err := m.Prune(&PruneParams{Ref: "peer/unpruned"}, &res); err == nil {
+		t.Error("expected pruning a dataset without a retention policy to error")
+	}
+	if err := m.Prune(&PruneParams{Ref: "peer/unpruned", Policy: &config.RetentionPolicy{KeepLast: 1}, DryRun: true}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || len(res[0].Pruned) != 2 {
+		t.Errorf("expected dry run to list 2 versions of peer/unpruned, got: %v", res)
+	}
+}
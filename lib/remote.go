@@ -120,6 +120,7 @@ func (r *RemoteMethods) Push(p *PushParams, res *dsref.Ref) error {
 	if err = base.SetPublishStatus(r.inst.node.Repo, ref, true); err != nil {
 		return err
 	}
+	r.inst.evictVersions(ctx, ref)
 
 	*res = ref
 	return nil
@@ -3,9 +3,11 @@ package lib
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/fsi"
+	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/remote"
 )
 
@@ -24,6 +26,23 @@ func (inst *Instance) ParseAndResolveRef(ctx context.Context, refStr, source str
 	return ref, resolvedSource, err
 }
 
+// resolveVersionPath resolves a string selecting a version of a dataset to a
+// path. The string can be a path, a dataset reference, or a revision of the
+// dataset ds refers to, like "~2" or "@v1.0"
+func (inst *Instance) resolveVersionPath(ctx context.Context, ds dsref.Ref, s string) (string, error) {
+	if strings.HasPrefix(s, "/") {
+		return s, nil
+	}
+	if strings.HasPrefix(s, "~") || strings.HasPrefix(s, "^") || strings.HasPrefix(s, "@") {
+		s = ds.Alias() + s
+	}
+	ref, _, err := inst.ParseAndResolveRef(ctx, s, "local")
+	if err != nil {
+		return "", err
+	}
+	return ref.Path, nil
+}
+
 // ParseAndResolveRefWithWorkingDir combines reference parsing and resolution,
 // including setting default Path to a linked working directory if one exists
 func (inst *Instance) ParseAndResolveRefWithWorkingDir(ctx context.Context, refStr, source string) (dsref.Ref, string, error) {
@@ -32,7 +51,7 @@ func (inst *Instance) ParseAndResolveRefWithWorkingDir(ctx context.Context, refS
 		return ref, "", fmt.Errorf("%q is not a valid dataset reference: %w", refStr, err)
 	}
 
-	pathProvided := ref.Path != ""
+	pathProvided := ref.Path != "" || ref.Revision != ""
 	resolvedSource, err := inst.ResolveReference(ctx, &ref, source)
 	if err != nil {
 		return ref, resolvedSource, err
@@ -59,6 +78,10 @@ func (inst *Instance) ResolveReference(ctx context.Context, ref *dsref.Ref, mode
 		ref.Username = inst.cfg.Profile.Peername
 	}
 
+	if err := inst.checkPathNetwork(ref.Path); err != nil {
+		return "", err
+	}
+
 	resolver, err := inst.resolverForMode(mode)
 	if err != nil {
 		log.Debug("inst.resolverForMode error=%q", err)
@@ -68,24 +91,41 @@ func (inst *Instance) ResolveReference(ctx context.Context, ref *dsref.Ref, mode
 	return resolver.ResolveRef(ctx, ref)
 }
 
+// checkPathNetwork confirms the instance has a filesystem for the network of
+// a reference path. References parse with any network, but versions can only
+// be loaded from networks the instance is configured with
+func (inst *Instance) checkPathNetwork(path string) error {
+	if path == "" || inst.qfs == nil {
+		return nil
+	}
+	network, _, _, err := dsref.SplitPath(path)
+	if err != nil {
+		return nil
+	}
+	if inst.qfs.Filesystem(network) == nil {
+		return fmt.Errorf("%w %q", dsref.ErrUnsupportedNetwork, network)
+	}
+	return nil
+}
+
 func (inst *Instance) resolverForMode(mode string) (dsref.Resolver, error) {
 	switch mode {
 	case "":
 		return inst.defaultResolver(), nil
 	case "local":
-		return dsref.SequentialResolver(
+		return inst.revisionResolver(dsref.SequentialResolver(
 			inst.dscache,
 			inst.repo,
-		), nil
+		)), nil
 	case "network":
-		return dsref.ParallelResolver(
+		return inst.revisionResolver(dsref.ParallelResolver(
 			inst.registryResolver(),
 			inst.p2pResolver(),
-		), nil
+		)), nil
 	case "registry":
-		return inst.registryResolver(), nil
+		return inst.revisionResolver(inst.registryResolver()), nil
 	case "p2p":
-		return inst.p2pResolver(), nil
+		return inst.revisionResolver(inst.p2pResolver()), nil
 	}
 
 	// TODO (b5) - mode could be one of:
@@ -97,18 +137,64 @@ func (inst *Instance) resolverForMode(mode string) (dsref.Resolver, error) {
 	if err != nil {
 		return nil, err
 	}
-	return inst.remoteClient.NewRemoteRefResolver(addr), nil
+	return inst.revisionResolver(inst.remoteClient.NewRemoteRefResolver(addr)), nil
 }
 
 func (inst *Instance) defaultResolver() dsref.Resolver {
-	return dsref.SequentialResolver(
+	return inst.revisionResolver(dsref.SequentialResolver(
 		inst.dscache,
 		inst.repo,
 		dsref.ParallelResolver(
 			inst.registryResolver(),
 			// inst.node,
 		),
-	)
+	))
+}
+
+// revisionResolver wraps a resolver, selecting the version a reference
+// revision refers to once the dataset is found. History is read from the
+// logbook for datasets resolved locally, and from logs fetched from the
+// resolving source otherwise
+func (inst *Instance) revisionResolver(r dsref.Resolver) dsref.Resolver {
+	return revisionResolver{Resolver: r, inst: inst}
+}
+
+type revisionResolver struct {
+	dsref.Resolver
+	inst *Instance
+}
+
+func (rr revisionResolver) ResolveRef(ctx context.Context, ref *dsref.Ref) (string, error) {
+	// resolvers may replace the reference, hold on to the revision
+	revision := ref.Revision
+	source, err := rr.Resolver.ResolveRef(ctx, ref)
+	if err != nil || revision == "" {
+		return source, err
+	}
+	ref.Revision = revision
+
+	if source == "" {
+		if rr.inst.logbook == nil {
+			return source, fmt.Errorf("resolving revision %q: no logbook", ref.Revision)
+		}
+		return source, rr.inst.logbook.ResolveRevision(ctx, ref)
+	}
+
+	if rr.inst.remoteClient == nil {
+		return source, fmt.Errorf("resolving revision %q: %w", ref.Revision, remote.ErrNoRemoteClient)
+	}
+	logs, err := rr.inst.remoteClient.FetchLogs(ctx, *ref, source)
+	if err != nil {
+		return source, err
+	}
+	// fetched logs are arranged user > dataset > branch, select from the branch
+	if len(logs.Logs) > 0 {
+		logs = logs.Logs[0]
+		if len(logs.Logs) > 0 {
+			logs = logs.Logs[0]
+		}
+	}
+	return source, logbook.ResolveLogRevision(logs, ref)
 }
 
 func (inst *Instance) registryResolver() dsref.Resolver {
@@ -3,6 +3,7 @@ package lib
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/qri-io/qri/dsref"
@@ -37,4 +38,49 @@ default resolver and no configured registry.
 want: %q
 got:  %q`, dsref.ErrRefNotFound, err)
 	}
+
+	// references parse with any network, resolving checks the instance can load
+	// paths from it
+	ref = &dsref.Ref{
+		Username: "example",
+		Name:     "dataset",
+		Path:     "/map/QmSome1Commit2Hash3",
+	}
+	_, err = inst.ResolveReference(ctx, ref, "")
+	if !errors.Is(err, dsref.ErrUnsupportedNetwork) {
+		t.Errorf("expected resolving a path on an unknown network to fail with %q, got: %v", dsref.ErrUnsupportedNetwork, err)
+	}
+}
+
+func TestResolveRevision(t *testing.T) {
+	tr := newTestRunner(t)
+	defer tr.Delete()
+
+	first := tr.MustSaveFromBody(t, "test_cities", "testdata/cities_2/body.csv")
+	second := tr.MustSaveFromBody(t, "test_cities", "testdata/cities_2/body_more.csv")
+	third := tr.MustSaveFromBody(t, "test_cities", "testdata/cities_2/body_even_more.csv")
+
+	cases := []struct {
+		refStr string
+		expect string
+	}{
+		{"peer/test_cities", third.Path},
+		{"peer/test_cities~1", second.Path},
+		{"peer/test_cities^^", first.Path},
+		{fmt.Sprintf("peer/test_cities@%s~1", third.Path), second.Path},
+	}
+	for _, c := range cases {
+		ref, _, err := tr.Instance.ParseAndResolveRef(tr.Ctx, c.refStr, "local")
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.refStr, err)
+			continue
+		}
+		if ref.Path != c.expect {
+			t.Errorf("%q: path mismatch. want: %q, got: %q", c.refStr, c.expect, ref.Path)
+		}
+	}
+
+	if _, _, err := tr.Instance.ParseAndResolveRef(tr.Ctx, "peer/test_cities~3", "local"); !errors.Is(err, dsref.ErrRevisionNotFound) {
+		t.Errorf("expected resolving past the first version to error with %q, got: %v", dsref.ErrRevisionNotFound, err)
+	}
 }
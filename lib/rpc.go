@@ -42,6 +42,7 @@ func Receivers(inst *Instance) []Methods {
 		NewSQLMethods(inst),
 		NewRenderMethods(inst),
 		NewFSIMethods(inst),
+		NewTransformMethods(inst),
 	}
 }
 
@@ -0,0 +1,280 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+// missingFiles lists the paths of files in a stored dataset version that
+// aren't present in local storage. Versions fetched with a sparse pull are
+// missing the files of components that weren't pulled. Only the root of the
+// version is read, so checking never reaches out to the network
+func (inst *Instance) missingFiles(ctx context.Context, path string) (map[string]bool, error) {
+	capi, err := inst.node.IPFSCoreAPI()
+	if err != nil {
+		// only IPFS repos can hold partial versions
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/ipfs/") {
+		return nil, nil
+	}
+	id, err := cid.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	nd, err := capi.Dag().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := map[string]bool{}
+	for _, l := range nd.Links() {
+		local, err := inst.qfs.Has(ctx, "/ipfs/"+l.Cid.String())
+		if err != nil {
+			return nil, err
+		}
+		if !local {
+			// stored datasets can refer to files by path within the version or
+			// by their own path
+			missing[fmt.Sprintf("%s/%s", path, l.Name)] = true
+			missing["/ipfs/"+l.Cid.String()] = true
+		}
+	}
+	return missing, nil
+}
+
+// isSparseVersion reports if a locally stored dataset version is missing
+// files, returning false for versions that aren't stored locally at all
+func (inst *Instance) isSparseVersion(ctx context.Context, path string) bool {
+	missing, err := inst.missingFiles(ctx, path)
+	if err != nil {
+		log.Debugf("checking for missing files in %q: %s", path, err)
+		return false
+	}
+	return len(missing) > 0
+}
+
+// completeVersion fetches any files a locally stored dataset version is
+// missing from the remote it was pulled from, or remotes it's been pushed to.
+// It's an error if no remote is known to hold the version
+func (inst *Instance) completeVersion(ctx context.Context, ref dsref.Ref) error {
+	if !inst.isSparseVersion(ctx, ref.Path) {
+		return nil
+	}
+	log.Debugf("completing partial version %q", ref.Path)
+	return inst.pullVersionFromRemotes(ctx, ref)
+}
+
+// deferMissingFiles replaces files a sparsely-pulled dataset version doesn't
+// have locally with files that complete the version when first read. Loading
+// a partial version stays fast, while commands that need data get it
+func (inst *Instance) deferMissingFiles(ctx context.Context, ref dsref.Ref, ds *dataset.Dataset) error {
+	missing, err := inst.missingFiles(ctx, ref.Path)
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+
+	fs := inst.repo.Filesystem()
+	var (
+		once        sync.Once
+		completeErr error
+	)
+	complete := func() error {
+		once.Do(func() {
+			completeErr = inst.pullVersionFromRemotes(ctx, ref)
+		})
+		return completeErr
+	}
+	get := func(path string) func() (qfs.File, error) {
+		return func() (qfs.File, error) {
+			if err := complete(); err != nil {
+				return nil, err
+			}
+			return fs.Get(ctx, path)
+		}
+	}
+
+	if missing[ds.BodyPath] {
+		ds.SetBodyFile(newLazyFile(ds.BodyPath, func() (qfs.File, error) {
+			if err := complete(); err != nil {
+				return nil, err
+			}
+			return dsfs.OpenBody(ctx, fs, ds)
+		}))
+	}
+	if ds.Transform != nil && missing[ds.Transform.ScriptPath] {
+		ds.Transform.SetScriptFile(newLazyFile(ds.Transform.ScriptPath, get(ds.Transform.ScriptPath)))
+	}
+	if ds.Viz != nil && missing[ds.Viz.ScriptPath] {
+		ds.Viz.SetScriptFile(newLazyFile(ds.Viz.ScriptPath, get(ds.Viz.ScriptPath)))
+	}
+	if ds.Viz != nil && missing[ds.Viz.RenderedPath] {
+		ds.Viz.SetRenderedFile(newLazyFile(ds.Viz.RenderedPath, get(ds.Viz.RenderedPath)))
+	}
+	if ds.Readme != nil && missing[ds.Readme.ScriptPath] {
+		ds.Readme.SetScriptFile(newLazyFile(ds.Readme.ScriptPath, get(ds.Readme.ScriptPath)))
+	}
+	return nil
+}
+
+// sparseSourcesFilename is the name of the file in a repo that records the
+// remotes sparsely-pulled versions were fetched from
+const sparseSourcesFilename = "sparse_sources.json"
+
+// sparseSources records the remote each sparsely-pulled dataset version was
+// fetched from. The logbook only lists remotes a version has been pushed to,
+// which may not include the remote a partial version came from
+type sparseSources struct {
+	lk sync.Mutex
+	// file sources are persisted to, empty for in-memory sources
+	filename string
+	// remote addresses keyed by version path
+	addrs map[string]string
+}
+
+func openSparseSources(filename string) *sparseSources {
+	s := &sparseSources{filename: filename, addrs: map[string]string{}}
+	if filename == "" {
+		return s
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s.addrs); err != nil {
+		log.Debugf("reading sparse version sources: %s", err)
+		s.addrs = map[string]string{}
+	}
+	return s
+}
+
+// get returns the address of the remote a version was fetched from, the empty
+// string if the version wasn't sparsely pulled
+func (s *sparseSources) get(path string) string {
+	if s == nil {
+		return ""
+	}
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return s.addrs[path]
+}
+
+// put records the remote a version was sparsely pulled from
+func (s *sparseSources) put(path, addr string) {
+	if s == nil {
+		return
+	}
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.addrs[path] = addr
+	s.save()
+}
+
+// remove drops the record of a version's remote, once it's complete
+func (s *sparseSources) remove(path string) {
+	if s == nil {
+		return
+	}
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	if _, ok := s.addrs[path]; !ok {
+		return
+	}
+	delete(s.addrs, path)
+	s.save()
+}
+
+func (s *sparseSources) save() {
+	if s.filename == "" {
+		return
+	}
+	data, err := json.Marshal(s.addrs)
+	if err != nil {
+		log.Debugf("encoding sparse version sources: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(s.filename, data, 0644); err != nil {
+		log.Debugf("writing sparse version sources: %s", err)
+	}
+}
+
+// lazyFile is a file that isn't opened until it's first read
+type lazyFile struct {
+	path string
+	open func() (qfs.File, error)
+
+	once sync.Once
+	file qfs.File
+	err  error
+}
+
+var _ qfs.File = (*lazyFile)(nil)
+
+func newLazyFile(path string, open func() (qfs.File, error)) *lazyFile {
+	return &lazyFile{path: path, open: open}
+}
+
+func (f *lazyFile) load() error {
+	f.once.Do(func() {
+		f.file, f.err = f.open()
+	})
+	return f.err
+}
+
+// Read opens the file if necessary, then reads from it
+func (f *lazyFile) Read(p []byte) (int, error) {
+	if err := f.load(); err != nil {
+		return 0, err
+	}
+	return f.file.Read(p)
+}
+
+// Close closes the file if it has been opened
+func (f *lazyFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// FileName returns the base of the file path
+func (f *lazyFile) FileName() string { return filepath.Base(f.path) }
+
+// FullPath returns the path the file is read from
+func (f *lazyFile) FullPath() string { return f.path }
+
+// IsDirectory is always false
+func (f *lazyFile) IsDirectory() bool { return false }
+
+// NextFile is not supported, lazy files are never directories
+func (f *lazyFile) NextFile() (qfs.File, error) { return nil, qfs.ErrNotDirectory }
+
+// ModTime returns the modification time of the opened file, the zero time if
+// the file hasn't been opened
+func (f *lazyFile) ModTime() time.Time {
+	if f.file == nil {
+		return time.Time{}
+	}
+	return f.file.ModTime()
+}
+
+// MediaType returns the media type of the opened file, the empty string if
+// the file hasn't been opened
+func (f *lazyFile) MediaType() string {
+	if f.file == nil {
+		return ""
+	}
+	return f.file.MediaType()
+}
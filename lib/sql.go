@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/repo"
 	"github.com/qri-io/qri/sql"
+	skyqri "github.com/qri-io/qri/startf/qri"
 )
 
 // SQLMethods encapsulates business logic for the qri search command
@@ -57,3 +61,10 @@ func (m *SQLMethods) Exec(p *SQLQueryParams, results *[]byte) error {
 	*results = buf.Bytes()
 	return nil
 }
+
+// sqlQueryFunc adapts the sql service for use within transforms
+func sqlQueryFunc(r repo.Repo) skyqri.QueryFunc {
+	return func(ctx context.Context, loader dsref.ParseResolveLoad, w io.Writer, outFormat, query string) error {
+		return sql.New(r, loader).Exec(ctx, w, outFormat, query)
+	}
+}
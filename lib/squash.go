@@ -0,0 +1,194 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/dsref"
+	qrierr "github.com/qri-io/qri/errors"
+)
+
+// SquashParams defines parameters for the Squash method
+type SquashParams struct {
+	// Reference to the dataset to squash
+	Ref string
+	// From & To select the oldest & newest versions to squash, as paths,
+	// dataset references, or revisions like "~2". To defaults to the latest
+	// version
+	From, To string
+	// Title & Message of the squashed commit. Defaults describe the range
+	Title, Message string
+}
+
+// Squash collapses a range of dataset versions into one. Versions after the
+// range are re-parented onto the squashed version. Squashing rewrites history,
+// and is recorded in the logbook so peers that pulled the replaced versions
+// see the rewrite when they sync. Returns the new latest version
+func (m *DatasetMethods) Squash(p *SquashParams, res *dataset.Dataset) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Squash", p, res))
+	}
+	ctx := context.TODO()
+
+	if p.From == "" {
+		return fmt.Errorf("squashing requires the oldest version to squash")
+	}
+	head, history, err := m.inst.rewritableHistory(ctx, p.Ref, "squash")
+	if err != nil {
+		return err
+	}
+
+	from, err := m.inst.resolveVersionPath(ctx, head, p.From)
+	if err != nil {
+		return err
+	}
+	to := head.Path
+	if p.To != "" {
+		if to, err = m.inst.resolveVersionPath(ctx, head, p.To); err != nil {
+			return err
+		}
+	}
+	fromIdx, err := historyIndex(head, history, from)
+	if err != nil {
+		return err
+	}
+	toIdx, err := historyIndex(head, history, to)
+	if err != nil {
+		return err
+	}
+	if fromIdx > toIdx {
+		return fmt.Errorf("version %s comes after %s, --from must be the older version", from, to)
+	}
+
+	if err := m.inst.completeVersions(ctx, head, history[fromIdx:]); err != nil {
+		return err
+	}
+
+	ds, err := base.Squash(ctx, m.inst.repo, head, history, fromIdx, toIdx, p.Title, p.Message)
+	if err != nil {
+		return err
+	}
+	*res = *ds
+	return nil
+}
+
+// RebaseParams defines parameters for the Rebase method
+type RebaseParams struct {
+	// Reference to the dataset to rebase
+	Ref string
+	// Onto is the version to re-parent onto, From is the oldest version to
+	// re-parent. Both are paths, dataset references, or revisions like "~2"
+	Onto, From string
+}
+
+// Rebase re-parents the versions from p.From through the latest version onto
+// the older version p.Onto, dropping the versions in between. Re-parented
+// versions keep their commits. Like squashing, rebasing rewrites history and
+// is recorded in the logbook. Returns the new latest version
+func (m *DatasetMethods) Rebase(p *RebaseParams, res *dataset.Dataset) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("DatasetMethods.Rebase", p, res))
+	}
+	ctx := context.TODO()
+
+	if p.Onto == "" || p.From == "" {
+		return fmt.Errorf("rebasing requires the version to rebase onto, and the oldest version to rebase")
+	}
+	head, history, err := m.inst.rewritableHistory(ctx, p.Ref, "rebase")
+	if err != nil {
+		return err
+	}
+
+	onto, err := m.inst.resolveVersionPath(ctx, head, p.Onto)
+	if err != nil {
+		return err
+	}
+	from, err := m.inst.resolveVersionPath(ctx, head, p.From)
+	if err != nil {
+		return err
+	}
+	ontoIdx, err := historyIndex(head, history, onto)
+	if err != nil {
+		return err
+	}
+	fromIdx, err := historyIndex(head, history, from)
+	if err != nil {
+		return err
+	}
+	if ontoIdx >= fromIdx {
+		return fmt.Errorf("version %s doesn't come before %s, --onto must be the older version", onto, from)
+	}
+
+	if err := m.inst.completeVersions(ctx, head, history[fromIdx:]); err != nil {
+		return err
+	}
+
+	ds, err := base.Rebase(ctx, m.inst.repo, head, history, ontoIdx, fromIdx)
+	if err != nil {
+		return err
+	}
+	*res = *ds
+	return nil
+}
+
+// rewritableHistory resolves the head of a dataset that's about to have its
+// history rewritten, returning the paths of every version in the history,
+// ordered oldest to newest. verb names the rewrite in errors
+func (inst *Instance) rewritableHistory(ctx context.Context, refStr, verb string) (dsref.Ref, []string, error) {
+	head, _, err := inst.ParseAndResolveRef(ctx, refStr, "local")
+	if err != nil {
+		return head, nil, err
+	}
+	head.Path = ""
+	if _, err := inst.ResolveReference(ctx, &head, "local"); err != nil {
+		return head, nil, err
+	}
+	if head.InitID == "" {
+		if head.InitID, err = inst.logbook.RefToInitID(dsref.Ref{Username: head.Username, Name: head.Name}); err != nil {
+			return head, nil, err
+		}
+	}
+
+	fsiRef := head.Copy()
+	if err := inst.fsi.ResolvedPath(&fsiRef); err == nil {
+		return head, nil, qrierr.New(fmt.Errorf("cannot %s while FSI-linked", verb), fmt.Sprintf("can't %s a dataset linked to a working directory, unlink it first.", verb))
+	}
+
+	// logbook items are ordered newest to oldest
+	items, err := inst.logbook.Items(ctx, head, 0, -1)
+	if err != nil {
+		return head, nil, err
+	}
+	history := make([]string, len(items))
+	for i, item := range items {
+		history[len(items)-1-i] = item.Path
+	}
+	return head, history, nil
+}
+
+// historyIndex returns the position of a version path in a history
+func historyIndex(head dsref.Ref, history []string, path string) (int, error) {
+	for i, p := range history {
+		if p == path {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("version %s isn't in the history of %s", path, head.Alias())
+}
+
+// completeVersions makes sure the versions at paths are stored locally in
+// full, fetching them from remotes as needed
+func (inst *Instance) completeVersions(ctx context.Context, head dsref.Ref, paths []string) error {
+	for _, path := range paths {
+		vref := dsref.Ref{Username: head.Username, Name: head.Name, ProfileID: head.ProfileID, Path: path}
+		if err := inst.fetchMissingVersion(ctx, vref); err != nil {
+			return err
+		}
+		if err := inst.completeVersion(ctx, vref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
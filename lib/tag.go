@@ -0,0 +1,91 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
+)
+
+// VersionTag is a human-readable name for a dataset version
+type VersionTag = logbook.VersionTag
+
+// TagParams defines parameters for the Tag method
+type TagParams struct {
+	// Reference to the version to tag. The latest version is tagged if the
+	// reference doesn't select one
+	Ref string
+	// Name of the tag
+	Name string
+	// Force moves a tag that already names a different version
+	Force bool
+}
+
+// Tag names a dataset version. Tags are recorded in the dataset's logbook, so
+// they travel with the dataset when it's pushed & pulled. Only the author of a
+// dataset can tag its versions
+func (m *LogMethods) Tag(p *TagParams, res *VersionTag) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("LogMethods.Tag", p, res))
+	}
+	ctx := context.TODO()
+
+	ref, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+	if err != nil {
+		return err
+	}
+	initID, err := m.initID(ref)
+	if err != nil {
+		return err
+	}
+
+	book := m.inst.repo.Logbook()
+	if err := book.WriteTag(ctx, initID, p.Name, ref.Path, p.Force); err != nil {
+		return err
+	}
+
+	tags, err := book.Tags(ctx, initID)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if tag.Name == p.Name {
+			*res = tag
+			return nil
+		}
+	}
+	return nil
+}
+
+// TagListParams defines parameters for the TagList method
+type TagListParams struct {
+	// Reference to the dataset to list tags for
+	Ref string
+}
+
+// TagList lists the tags of a dataset
+func (m *LogMethods) TagList(p *TagListParams, res *[]VersionTag) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("LogMethods.TagList", p, res))
+	}
+	ctx := context.TODO()
+
+	ref, _, err := m.inst.ParseAndResolveRef(ctx, p.Ref, "local")
+	if err != nil {
+		return err
+	}
+	initID, err := m.initID(ref)
+	if err != nil {
+		return err
+	}
+
+	*res, err = m.inst.repo.Logbook().Tags(ctx, initID)
+	return err
+}
+
+func (m *LogMethods) initID(ref dsref.Ref) (string, error) {
+	if ref.InitID != "" {
+		return ref.InitID, nil
+	}
+	return m.inst.repo.Logbook().RefToInitID(dsref.Ref{Username: ref.Username, Name: ref.Name})
+}
@@ -0,0 +1 @@
+SELECT * FROM me/movies AS m
@@ -0,0 +1,79 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/startf"
+)
+
+// TransformMethods encapsulates business logic for working with transform
+// scripts outside of saving a dataset
+type TransformMethods struct {
+	inst *Instance
+}
+
+// NewTransformMethods creates TransformMethods from a qri Instance
+func NewTransformMethods(inst *Instance) *TransformMethods {
+	return &TransformMethods{inst: inst}
+}
+
+// CoreRequestsName implements the Requests interface
+func (TransformMethods) CoreRequestsName() string { return "transform" }
+
+// TransformTestParams defines parameters for the Test method
+type TransformTestParams struct {
+	// path to the transform script to test
+	ScriptPath string
+	// path to the test script. defaults to a companion file of ScriptPath,
+	// eg: transform.star -> transform_test.star
+	TestScriptPath string
+}
+
+// Test runs the test functions of a transform test script against a transform
+func (m *TransformMethods) Test(p *TransformTestParams, res *[]*startf.TestResult) error {
+	if m.inst.rpc != nil {
+		return checkRPCError(m.inst.rpc.Call("TransformMethods.Test", p, res))
+	}
+	ctx := context.TODO()
+
+	if p.ScriptPath == "" {
+		return fmt.Errorf("transform script path is required")
+	}
+	if p.TestScriptPath == "" {
+		p.TestScriptPath = startf.TestScriptPath(p.ScriptPath)
+	}
+
+	script, err := os.Open(p.ScriptPath)
+	if err != nil {
+		return fmt.Errorf("opening transform script: %w", err)
+	}
+	defer script.Close()
+
+	testScript, err := os.Open(p.TestScriptPath)
+	if err != nil {
+		return fmt.Errorf("opening test script: %w", err)
+	}
+	defer testScript.Close()
+
+	// transforms under test may only load datasets from the local repo
+	resolver, err := m.inst.resolverForMode("local")
+	if err != nil {
+		return err
+	}
+	loader := NewParseResolveLoadFunc(m.inst.cfg.Profile.Peername, resolver, m.inst)
+
+	results, err := startf.RunTests(ctx,
+		qfs.NewMemfileReader(p.ScriptPath, script),
+		qfs.NewMemfileReader(p.TestScriptPath, testScript),
+		startf.AddDatasetLoader(loader),
+	)
+	if err != nil {
+		return err
+	}
+
+	*res = results
+	return nil
+}
@@ -23,7 +23,7 @@ func (inst *Instance) ServeWebsocket(ctx context.Context) {
 
 	// Watch the filesystem. Events will be sent to websocket connections
 	// TODO (b5) - watchfs constrcution shouldn't happen here
-	watcher, err := watchfs.NewFilesysWatcher(ctx, inst.bus)
+	watcher, err := watchfs.NewFilesysWatcher(ctx, inst.bus, inst.fsi)
 	if err != nil {
 		log.Errorf("Watching filesystem error: %s", err)
 		return
@@ -91,6 +91,10 @@ func (inst *Instance) ServeWebsocket(ctx context.Context) {
 		event.ETDeletedFile,
 		event.ETRenamedFolder,
 		event.ETRemovedFolder,
+		event.ETWorkingDirStatusChanged,
+		event.ETFSIAutosaved,
+		event.ETFSIAutosaveSkipped,
+		event.ETFSIAutosaveFailed,
 		event.ETRemoteClientPushVersionProgress,
 		event.ETRemoteClientPushVersionCompleted,
 		event.ETRemoteClientPushDatasetCompleted,
@@ -0,0 +1,560 @@
+// Package lineage indexes the dependencies between dataset versions created by
+// transform scripts. When a transform loads a dataset, the exact version it
+// read is recorded as a resource of the transform component. lineage turns
+// those records into a graph that can be walked upstream ("what fed into this
+// version?") or downstream ("which datasets depend on this one?")
+package lineage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	logger "github.com/ipfs/go-log"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/repo"
+)
+
+var log = logger.Logger("lineage")
+
+// Edge connects an upstream dataset version to a downstream version whose
+// transform read it
+type Edge struct {
+	Upstream   dsref.Ref `json:"upstream"`
+	Downstream dsref.Ref `json:"downstream"`
+}
+
+// Graph is a set of dataset versions connected by edges
+type Graph struct {
+	Nodes []dsref.Ref `json:"nodes"`
+	Edges []Edge      `json:"edges"`
+}
+
+// IndexPath returns the standard path to the lineage index file for a given
+// file-system repo location
+func IndexPath(repoPath string) string {
+	return filepath.Join(repoPath, "lineage.json")
+}
+
+// Index holds all lineage edges known to a repo
+type Index struct {
+	lk sync.Mutex
+	// file the index is persisted to, empty for an in-memory index
+	filename string
+	// filesystem new versions are loaded from when the index is updated
+	fs qfs.Filesystem
+
+	edges []Edge
+	// edges keyed by downstream version path
+	up map[string][]Edge
+	// edges keyed by upstream version path
+	down map[string][]Edge
+	// edges keyed by upstream dataset alias
+	downByName map[string][]Edge
+	// version paths keyed by dataset alias, newest first
+	versions map[string][]string
+	// dataset aliases keyed by initID. dataset events that don't carry a
+	// name are matched to an alias with this
+	names map[string]string
+}
+
+// indexFile is the persisted form of an index
+type indexFile struct {
+	Edges    []Edge              `json:"edges"`
+	Versions map[string][]string `json:"versions"`
+	Names    map[string]string   `json:"names"`
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		up:         map[string][]Edge{},
+		down:       map[string][]Edge{},
+		downByName: map[string][]Edge{},
+		versions:   map[string][]string{},
+		names:      map[string]string{},
+	}
+}
+
+// Open loads a persisted index, building one by scanning the repo if the
+// index file doesn't exist yet. The index subscribes to dataset events on
+// bus, updating itself as versions are saved, removed, and renamed. If
+// filename is empty the index is kept in memory
+func Open(ctx context.Context, r repo.Repo, bus event.Bus, filename string) (*Index, error) {
+	idx, err := load(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debugf("reading lineage index, rebuilding: %s", err)
+		}
+		if idx, err = Build(ctx, r); err != nil {
+			return nil, err
+		}
+		idx.filename = filename
+		idx.save()
+	}
+	idx.fs = r.Filesystem()
+
+	if bus != nil {
+		bus.Subscribe(idx.handler,
+			event.ETDatasetNameInit,
+			event.ETDatasetCommitChange,
+			event.ETDatasetDeleteAll,
+			event.ETDatasetRename,
+		)
+	}
+	return idx, nil
+}
+
+func load(filename string) (*Index, error) {
+	if filename == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	f := indexFile{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	idx := NewIndex()
+	idx.filename = filename
+	if f.Versions != nil {
+		idx.versions = f.Versions
+	}
+	if f.Names != nil {
+		idx.names = f.Names
+	}
+	idx.reindex(f.Edges)
+	return idx, nil
+}
+
+// save writes the index file. must be called with the lock held
+func (idx *Index) save() {
+	if idx.filename == "" {
+		return
+	}
+	data, err := json.Marshal(indexFile{Edges: idx.edges, Versions: idx.versions, Names: idx.names})
+	if err != nil {
+		log.Debugf("encoding lineage index: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(idx.filename, data, 0644); err != nil {
+		log.Debugf("writing lineage index: %s", err)
+	}
+}
+
+// Build scans the history of every dataset in a repo, indexing the resources
+// recorded by each version's transform. Versions that aren't stored locally
+// are skipped
+func Build(ctx context.Context, r repo.Repo) (*Index, error) {
+	idx := NewIndex()
+	book := r.Logbook()
+	if book == nil {
+		return nil, fmt.Errorf("lineage: a logbook is required")
+	}
+
+	count, err := r.RefCount()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return idx, nil
+	}
+	refs, err := r.References(0, count)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vref := range refs {
+		ref := dsref.Ref{Username: vref.Peername, Name: vref.Name}
+		if _, err := r.ResolveRef(ctx, &ref); err != nil {
+			log.Debugf("resolving %s: %s", ref.Alias(), err)
+			continue
+		}
+		if ref.InitID != "" {
+			idx.names[ref.InitID] = ref.Alias()
+		}
+
+		items, err := book.Items(ctx, ref, 0, -1)
+		if err != nil {
+			log.Debugf("reading history of %s: %s", ref.Alias(), err)
+			continue
+		}
+
+		for _, item := range items {
+			if item.Path == "" {
+				continue
+			}
+			idx.versions[ref.Alias()] = append(idx.versions[ref.Alias()], item.Path)
+			idx.addVersionInputs(ctx, r.Filesystem(), dsref.Ref{Username: ref.Username, Name: ref.Name, ProfileID: ref.ProfileID, InitID: ref.InitID, Path: item.Path})
+		}
+	}
+
+	return idx, nil
+}
+
+// addVersionInputs loads a version, adding an edge for each input its
+// transform read. Versions that aren't stored locally are skipped
+func (idx *Index) addVersionInputs(ctx context.Context, fs qfs.Filesystem, downstream dsref.Ref) {
+	if local, err := fs.Has(ctx, downstream.Path); err != nil || !local {
+		return
+	}
+	ds, err := dsfs.LoadDataset(ctx, fs, downstream.Path)
+	if err != nil {
+		log.Debugf("loading %s: %s", downstream.Path, err)
+		return
+	}
+	for _, upstream := range Inputs(ds.Transform) {
+		idx.add(upstream, downstream)
+	}
+}
+
+// handler keeps the index in step with dataset events
+func (idx *Index) handler(ctx context.Context, t event.Type, payload interface{}) error {
+	act, ok := payload.(event.DsChange)
+	if !ok {
+		return nil
+	}
+
+	idx.lk.Lock()
+	defer idx.lk.Unlock()
+
+	switch t {
+	case event.ETDatasetNameInit:
+		idx.names[act.InitID] = fmt.Sprintf("%s/%s", act.Username, act.PrettyName)
+	case event.ETDatasetCommitChange:
+		alias, ok := idx.names[act.InitID]
+		if !ok {
+			if act.Info == nil || act.Info.Name == "" {
+				return nil
+			}
+			alias = act.Info.Alias()
+			idx.names[act.InitID] = alias
+		}
+		idx.setHead(ctx, alias, act)
+	case event.ETDatasetDeleteAll:
+		alias, ok := idx.names[act.InitID]
+		if !ok {
+			return nil
+		}
+		idx.dropVersions(alias, idx.versions[alias])
+		delete(idx.versions, alias)
+		delete(idx.names, act.InitID)
+	case event.ETDatasetRename:
+		prev, ok := idx.names[act.InitID]
+		if !ok {
+			return nil
+		}
+		idx.rename(act.InitID, prev, act.PrettyName)
+	default:
+		return nil
+	}
+
+	idx.save()
+	return nil
+}
+
+// setHead records a change to the head of a dataset's history. A head that's
+// already indexed means newer versions were removed, otherwise the head is a
+// new version. must be called with the lock held
+func (idx *Index) setHead(ctx context.Context, alias string, act event.DsChange) {
+	versions := idx.versions[alias]
+	if act.HeadRef == "" {
+		idx.dropVersions(alias, versions)
+		delete(idx.versions, alias)
+		return
+	}
+	for i, p := range versions {
+		if p == act.HeadRef {
+			idx.dropVersions(alias, versions[:i])
+			idx.versions[alias] = versions[i:]
+			return
+		}
+	}
+
+	idx.versions[alias] = append([]string{act.HeadRef}, versions...)
+	if idx.fs == nil {
+		return
+	}
+	username, name := splitAlias(alias)
+	downstream := dsref.Ref{Username: username, Name: name, InitID: act.InitID, Path: act.HeadRef}
+	if act.Info != nil {
+		downstream.ProfileID = act.Info.ProfileID
+	}
+	idx.addVersionInputs(ctx, idx.fs, downstream)
+}
+
+// dropVersions removes edges from versions of a dataset that no longer exist.
+// must be called with the lock held
+func (idx *Index) dropVersions(alias string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	drop := map[string]bool{}
+	for _, p := range paths {
+		drop[p] = true
+	}
+	edges := make([]Edge, 0, len(idx.edges))
+	for _, e := range idx.edges {
+		if e.Downstream.Alias() == alias && drop[e.Downstream.Path] {
+			continue
+		}
+		edges = append(edges, e)
+	}
+	idx.reindex(edges)
+}
+
+// rename moves a dataset's versions & edges to a new name. must be called
+// with the lock held
+func (idx *Index) rename(initID, prev, newName string) {
+	username, _ := splitAlias(prev)
+	next := fmt.Sprintf("%s/%s", username, newName)
+	idx.names[initID] = next
+	if versions, ok := idx.versions[prev]; ok {
+		idx.versions[next] = versions
+		delete(idx.versions, prev)
+	}
+
+	edges := make([]Edge, len(idx.edges))
+	for i, e := range idx.edges {
+		if e.Upstream.Alias() == prev {
+			e.Upstream.Name = newName
+		}
+		if e.Downstream.Alias() == prev {
+			e.Downstream.Name = newName
+		}
+		edges[i] = e
+	}
+	idx.reindex(edges)
+}
+
+// reindex replaces the edges of the index, rebuilding edge lookups. must be
+// called with the lock held
+func (idx *Index) reindex(edges []Edge) {
+	idx.edges = nil
+	idx.up = map[string][]Edge{}
+	idx.down = map[string][]Edge{}
+	idx.downByName = map[string][]Edge{}
+	for _, e := range edges {
+		idx.add(e.Upstream, e.Downstream)
+	}
+}
+
+func splitAlias(alias string) (username, name string) {
+	parts := strings.SplitN(alias, "/", 2)
+	if len(parts) < 2 {
+		return "", alias
+	}
+	return parts[0], parts[1]
+}
+
+// Inputs lists the dataset versions recorded as read by a transform
+func Inputs(tf *dataset.Transform) []dsref.Ref {
+	if tf == nil {
+		return nil
+	}
+	refs := make([]dsref.Ref, 0, len(tf.Resources))
+	for path, res := range tf.Resources {
+		ref, err := dsref.Parse(res.Path)
+		if err != nil {
+			// fall back to the resource key, which is the version path
+			ref = dsref.Ref{Path: path}
+		}
+		if ref.Path == "" {
+			ref.Path = path
+		}
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+	return refs
+}
+
+// Add records that the downstream version read the upstream version
+func (idx *Index) Add(upstream, downstream dsref.Ref) {
+	idx.lk.Lock()
+	defer idx.lk.Unlock()
+	idx.add(upstream, downstream)
+}
+
+func (idx *Index) add(upstream, downstream dsref.Ref) {
+	e := Edge{Upstream: upstream, Downstream: downstream}
+	idx.edges = append(idx.edges, e)
+	idx.up[downstream.Path] = append(idx.up[downstream.Path], e)
+	idx.down[upstream.Path] = append(idx.down[upstream.Path], e)
+	if alias := upstream.Alias(); alias != "" {
+		idx.downByName[alias] = append(idx.downByName[alias], e)
+	}
+}
+
+// Upstream returns the graph of dataset versions that fed into a version.
+// If ref has no path, the latest known version of the dataset is used
+func (idx *Index) Upstream(ref dsref.Ref) (*Graph, error) {
+	idx.lk.Lock()
+	defer idx.lk.Unlock()
+
+	path := ref.Path
+	if path == "" {
+		versions := idx.versions[ref.Alias()]
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("lineage: %w", dsref.ErrNoHistory)
+		}
+		path = versions[0]
+	}
+
+	g := &graphBuilder{seen: map[string]bool{}}
+	g.addNode(dsref.Ref{Username: ref.Username, Name: ref.Name, Path: path})
+	queue := []string{path}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, e := range idx.up[p] {
+			if g.addEdge(e) {
+				queue = append(queue, e.Upstream.Path)
+			}
+		}
+	}
+	return g.graph(), nil
+}
+
+// Downstream returns the graph of dataset versions that read from a dataset.
+// If ref has a path, only consumers of that exact version are considered
+// directly, otherwise consumers of any version of the dataset are
+func (idx *Index) Downstream(ref dsref.Ref) (*Graph, error) {
+	idx.lk.Lock()
+	defer idx.lk.Unlock()
+
+	g := &graphBuilder{seen: map[string]bool{}}
+	g.addNode(ref)
+
+	var start []Edge
+	if ref.Path != "" {
+		start = idx.down[ref.Path]
+	} else {
+		start = idx.downByName[ref.Alias()]
+	}
+
+	queue := start
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		if !g.addEdge(e) {
+			continue
+		}
+		// anything that depends on the downstream dataset transitively
+		// depends on the upstream
+		alias := e.Downstream.Alias()
+		if visited[alias] {
+			continue
+		}
+		visited[alias] = true
+		queue = append(queue, idx.downByName[alias]...)
+	}
+	return g.graph(), nil
+}
+
+type graphBuilder struct {
+	seen  map[string]bool
+	nodes []dsref.Ref
+	edges []Edge
+}
+
+func (g *graphBuilder) addNode(ref dsref.Ref) {
+	key := nodeID(ref)
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	g.nodes = append(g.nodes, ref)
+}
+
+// addEdge adds an edge & it's nodes to the graph, returning false if the edge
+// already exists
+func (g *graphBuilder) addEdge(e Edge) bool {
+	key := nodeID(e.Upstream) + "->" + nodeID(e.Downstream)
+	if g.seen[key] {
+		return false
+	}
+	g.seen[key] = true
+	g.addNode(e.Upstream)
+	g.addNode(e.Downstream)
+	g.edges = append(g.edges, e)
+	return true
+}
+
+func (g *graphBuilder) graph() *Graph {
+	return &Graph{Nodes: g.nodes, Edges: g.edges}
+}
+
+func nodeID(ref dsref.Ref) string {
+	if ref.Path != "" {
+		return ref.Path
+	}
+	return ref.Alias()
+}
+
+// nodeLabel is a human readable description of a node
+func nodeLabel(ref dsref.Ref) string {
+	if ref.Username == "" && ref.Name == "" {
+		return ref.Path
+	}
+	return ref.String()
+}
+
+// WriteDOT writes a graph in the graphviz DOT language
+func (g *Graph) WriteDOT(w io.Writer) error {
+	buf := &strings.Builder{}
+	buf.WriteString("digraph lineage {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(buf, "  %q [label=%q];\n", nodeID(n), nodeLabel(n))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(buf, "  %q -> %q;\n", nodeID(e.Upstream), nodeID(e.Downstream))
+	}
+	buf.WriteString("}\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// Outdated describes a transform input that has a newer version than the one
+// that was read
+type Outdated struct {
+	Consumed dsref.Ref `json:"consumed"`
+	Latest   dsref.Ref `json:"latest"`
+}
+
+// OutdatedInputs checks each input recorded by a transform against the head
+// of its dataset, returning inputs with newer versions. Inputs that can't be
+// resolved are ignored
+func OutdatedInputs(ctx context.Context, resolver dsref.Resolver, tf *dataset.Transform) []Outdated {
+	var res []Outdated
+	for _, consumed := range Inputs(tf) {
+		if consumed.Username == "" || consumed.Name == "" {
+			continue
+		}
+		head := dsref.Ref{Username: consumed.Username, Name: consumed.Name}
+		if _, err := resolver.ResolveRef(ctx, &head); err != nil {
+			if !errors.Is(err, dsref.ErrRefNotFound) {
+				log.Debugf("resolving %s: %s", head.Alias(), err)
+			}
+			continue
+		}
+		if head.Path != "" && head.Path != consumed.Path {
+			res = append(res, Outdated{Consumed: consumed, Latest: head})
+		}
+	}
+	return res
+}
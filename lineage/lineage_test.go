@@ -0,0 +1,236 @@
+package lineage
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
+	testrepo "github.com/qri-io/qri/repo/test"
+)
+
+func TestInputs(t *testing.T) {
+	if got := Inputs(nil); got != nil {
+		t.Errorf("expected nil transform to have no inputs, got: %v", got)
+	}
+
+	tf := &dataset.Transform{
+		Resources: map[string]*dataset.TransformResource{
+			"/ipfs/QmB": {Path: "peer/b@/ipfs/QmB"},
+			"/ipfs/QmA": {Path: "peer/a@/ipfs/QmA"},
+			"/ipfs/QmC": {Path: "not a ref"},
+		},
+	}
+	expect := []dsref.Ref{
+		{Username: "peer", Name: "a", Path: "/ipfs/QmA"},
+		{Username: "peer", Name: "b", Path: "/ipfs/QmB"},
+		{Path: "/ipfs/QmC"},
+	}
+	if diff := cmp.Diff(expect, Inputs(tf)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func newTestIndex() *Index {
+	var (
+		a1 = dsref.Ref{Username: "peer", Name: "a", Path: "/ipfs/QmA1"}
+		a2 = dsref.Ref{Username: "peer", Name: "a", Path: "/ipfs/QmA2"}
+		b1 = dsref.Ref{Username: "peer", Name: "b", Path: "/ipfs/QmB1"}
+		c1 = dsref.Ref{Username: "peer", Name: "c", Path: "/ipfs/QmC1"}
+	)
+
+	idx := NewIndex()
+	idx.versions["peer/a"] = []string{a2.Path, a1.Path}
+	idx.versions["peer/b"] = []string{b1.Path}
+	idx.versions["peer/c"] = []string{c1.Path}
+
+	// b reads an old version of a, c reads b
+	idx.Add(a1, b1)
+	idx.Add(b1, c1)
+	return idx
+}
+
+func TestUpstream(t *testing.T) {
+	idx := newTestIndex()
+
+	g, err := idx.Upstream(dsref.Ref{Username: "peer", Name: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes) != 3 {
+		t.Errorf("node count mismatch. expected: 3, got: %d", len(g.Nodes))
+	}
+	if len(g.Edges) != 2 {
+		t.Errorf("edge count mismatch. expected: 2, got: %d", len(g.Edges))
+	}
+
+	g, err = idx.Upstream(dsref.Ref{Username: "peer", Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Edges) != 0 {
+		t.Errorf("expected dataset with no inputs to have no edges, got: %d", len(g.Edges))
+	}
+
+	if _, err = idx.Upstream(dsref.Ref{Username: "peer", Name: "unknown"}); err == nil {
+		t.Error("expected unknown dataset to error")
+	}
+}
+
+func TestDownstream(t *testing.T) {
+	idx := newTestIndex()
+
+	g, err := idx.Downstream(dsref.Ref{Username: "peer", Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Edges) != 2 {
+		t.Errorf("edge count mismatch. expected: 2, got: %d", len(g.Edges))
+	}
+
+	// the latest version of a hasn't been read by anything
+	g, err = idx.Downstream(dsref.Ref{Username: "peer", Name: "a", Path: "/ipfs/QmA2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Edges) != 0 {
+		t.Errorf("edge count mismatch. expected: 0, got: %d", len(g.Edges))
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g, err := newTestIndex().Upstream(dsref.Ref{Username: "peer", Name: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteDOT(buf); err != nil {
+		t.Fatal(err)
+	}
+	expect := `digraph lineage {
+  "/ipfs/QmB1" [label="peer/b@/ipfs/QmB1"];
+  "/ipfs/QmA1" [label="peer/a@/ipfs/QmA1"];
+  "/ipfs/QmA1" -> "/ipfs/QmB1";
+}
+`
+	if diff := cmp.Diff(expect, buf.String()); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "lineage_open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := IndexPath(dir)
+
+	r, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	built, err := Open(ctx, r, event.NilBus, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(built.versions) == 0 {
+		t.Fatal("expected building an index to record versions")
+	}
+	if _, err := os.Stat(filename); err != nil {
+		t.Fatalf("expected index to be persisted: %s", err)
+	}
+
+	// reopening reads the persisted index instead of rebuilding
+	loaded, err := Open(ctx, r, event.NilBus, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(built.versions, loaded.versions); diff != "" {
+		t.Errorf("versions mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(built.names, loaded.names); diff != "" {
+		t.Errorf("names mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIndexUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "lineage_updates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	idx := newTestIndex()
+	idx.filename = filepath.Join(dir, "lineage.json")
+	idx.names["a_init"] = "peer/a"
+	idx.names["b_init"] = "peer/b"
+	idx.names["c_init"] = "peer/c"
+
+	publish := func(t event.Type, c event.DsChange) {
+		if err := idx.handler(ctx, t, c); err != nil {
+			panic(err)
+		}
+	}
+
+	// a new version of c becomes the head
+	publish(event.ETDatasetCommitChange, event.DsChange{InitID: "c_init", HeadRef: "/ipfs/QmC2"})
+	if diff := cmp.Diff([]string{"/ipfs/QmC2", "/ipfs/QmC1"}, idx.versions["peer/c"]); diff != "" {
+		t.Errorf("versions mismatch (-want +got):\n%s", diff)
+	}
+
+	// removing the new version moves the head back
+	publish(event.ETDatasetCommitChange, event.DsChange{InitID: "c_init", HeadRef: "/ipfs/QmC1"})
+	if diff := cmp.Diff([]string{"/ipfs/QmC1"}, idx.versions["peer/c"]); diff != "" {
+		t.Errorf("versions mismatch (-want +got):\n%s", diff)
+	}
+
+	// renaming b moves its versions & edges
+	publish(event.ETDatasetRename, event.DsChange{InitID: "b_init", PrettyName: "b2"})
+	g, err := idx.Upstream(dsref.Ref{Username: "peer", Name: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Edges) != 2 || g.Edges[0].Upstream.Name != "b2" {
+		t.Errorf("expected renamed upstream edge, got: %v", g.Edges)
+	}
+	if _, err := idx.Upstream(dsref.Ref{Username: "peer", Name: "b2"}); err != nil {
+		t.Errorf("expected renamed dataset to have versions: %s", err)
+	}
+
+	// deleting c drops the edges it read
+	publish(event.ETDatasetDeleteAll, event.DsChange{InitID: "c_init"})
+	g, err = idx.Downstream(dsref.Ref{Username: "peer", Name: "b2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Edges) != 0 {
+		t.Errorf("expected deleted dataset edges to be dropped, got: %v", g.Edges)
+	}
+
+	// updates are persisted
+	loaded, err := load(idx.filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(idx.versions, loaded.versions); diff != "" {
+		t.Errorf("versions mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(idx.edges, loaded.edges); diff != "" {
+		t.Errorf("edges mismatch (-want +got):\n%s", diff)
+	}
+}
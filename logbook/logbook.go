@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -37,6 +38,8 @@ var (
 	// ErrAccessDenied indicates insufficent privileges to perform a logbook
 	// operation
 	ErrAccessDenied = fmt.Errorf("access denied")
+	// ErrTagExists indicates a tag name is already in use by another version
+	ErrTagExists = fmt.Errorf("logbook: tag already exists")
 
 	// NewTimestamp generates the current unix nanosecond time.
 	// This is mainly here for tests to override
@@ -58,6 +61,8 @@ const (
 	PushModel
 	// ACLModel is the enum for a acl model
 	ACLModel
+	// TagModel is the enum for a tag model
+	TagModel
 )
 
 // DefaultBranchName is the default name all branch-level logbook data is read
@@ -80,6 +85,8 @@ func ModelString(m uint32) string {
 		return "push"
 	case ACLModel:
 		return "acl"
+	case TagModel:
+		return "tag"
 	default:
 		return ""
 	}
@@ -283,6 +290,40 @@ func (book *Book) WriteAuthorRename(ctx context.Context, newName string) error {
 	return nil
 }
 
+// WriteKeyRotation replaces the private key the author signs with. The
+// rotation is recorded in the author's log with an operation signed by the
+// current key, and the logbook is re-encrypted with the new key. Peers that
+// receive the author's log accept logs signed by the new key
+func (book *Book) WriteKeyRotation(ctx context.Context, next crypto.PrivKey) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	if next == nil {
+		return fmt.Errorf("logbook: private key is required")
+	}
+	if next.Equals(book.pk) {
+		return fmt.Errorf("logbook: new key must differ from the current key")
+	}
+
+	authorLog, err := book.authorLog(ctx)
+	if err != nil {
+		return err
+	}
+	op, err := oplog.NewKeyRotationOp(AuthorModel, authorLog.l.Name(), book.pk, next.GetPublic(), NewTimestamp())
+	if err != nil {
+		return err
+	}
+
+	prevOps, prevPk := authorLog.l.Ops, book.pk
+	authorLog.Append(op)
+	book.pk = next
+	if err := book.save(ctx); err != nil {
+		authorLog.l.Ops, book.pk = prevOps, prevPk
+		return err
+	}
+	return nil
+}
+
 // WriteDatasetInit initializes a new dataset name within the author's namespace
 func (book *Book) WriteDatasetInit(ctx context.Context, dsName string) (string, error) {
 	if book == nil {
@@ -489,6 +530,29 @@ func (book *Book) WriteVersionSave(ctx context.Context, initID string, ds *datas
 	}
 
 	log.Debugf("WriteVersionSave: %s", initID)
+	return book.writeVersionSave(ctx, initID, ds, versionSaveOp(ds))
+}
+
+// RevertOpName names the save operation of a version that restores an
+// earlier version, distinguishing reverts from other saves
+const RevertOpName = "revert"
+
+// WriteVersionRevert adds an operation to a log marking the creation of a
+// dataset version that restores an earlier version. The save operation is
+// named RevertOpName and lists the path of the restored version as its only
+// relation
+func (book *Book) WriteVersionRevert(ctx context.Context, initID string, ds *dataset.Dataset, revertedPath string) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	log.Debugf("WriteVersionRevert: %s, reverted: %s", initID, revertedPath)
+	op := versionSaveOp(ds)
+	op.Name = RevertOpName
+	op.Relations = []string{revertedPath}
+	return book.writeVersionSave(ctx, initID, ds, op)
+}
+
+func (book *Book) writeVersionSave(ctx context.Context, initID string, ds *dataset.Dataset, op oplog.Op) error {
 	branchLog, err := book.branchLog(ctx, initID)
 	if err != nil {
 		return err
@@ -498,7 +562,8 @@ func (book *Book) WriteVersionSave(ctx context.Context, initID string, ds *datas
 		return err
 	}
 
-	topIndex := book.appendVersionSave(branchLog, ds)
+	branchLog.Append(op)
+	topIndex := branchLog.Size() - 1
 	// TODO(dlong): Think about how to handle a failure exactly here, what needs to be rolled back?
 	err = book.save(ctx)
 	if err != nil {
@@ -521,6 +586,12 @@ func (book *Book) WriteVersionSave(ctx context.Context, initID string, ds *datas
 }
 
 func (book *Book) appendVersionSave(blog *BranchLog, ds *dataset.Dataset) int {
+	blog.Append(versionSaveOp(ds))
+	return blog.Size() - 1
+}
+
+// versionSaveOp creates the operation recording the creation of a version
+func versionSaveOp(ds *dataset.Dataset) oplog.Op {
 	op := oplog.Op{
 		Type:  oplog.OpTypeInit,
 		Model: CommitModel,
@@ -534,10 +605,7 @@ func (book *Book) appendVersionSave(blog *BranchLog, ds *dataset.Dataset) int {
 	if ds.Structure != nil {
 		op.Size = int64(ds.Structure.Length)
 	}
-
-	blog.Append(op)
-
-	return blog.Size() - 1
+	return op
 }
 
 // WriteVersionAmend adds an operation to a log when a dataset amends a commit
@@ -612,6 +680,234 @@ func (book *Book) WriteVersionDelete(ctx context.Context, initID string, revisio
 	return book.save(ctx)
 }
 
+// RewriteOpName names the remove operation that starts a history rewrite,
+// distinguishing rewrites from deleted versions
+const RewriteOpName = "rewrite"
+
+// RewrittenVersion is a version written by a history rewrite, listing the
+// paths of versions it replaces
+type RewrittenVersion struct {
+	Dataset  *dataset.Dataset
+	Replaces []string
+}
+
+// WriteVersionRewrite adds operations to a log replacing the newest versions
+// of a dataset with rewritten ones, like when squashing a range of versions.
+// The rewrite is recorded as a remove operation named RewriteOpName that
+// lists the paths of removed versions, followed by a save operation for each
+// rewritten version that lists the paths it replaces. Peers that already have
+// the removed versions get an explicit record of the rewrite when they sync
+func (book *Book) WriteVersionRewrite(ctx context.Context, initID string, removed int, versions []RewrittenVersion, note string) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	log.Debugf("WriteVersionRewrite: %s, removed: %d, versions: %d", initID, removed, len(versions))
+
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return err
+	}
+	if err := book.hasWriteAccess(branchLog.l); err != nil {
+		return err
+	}
+
+	items := branchToLogItems(branchLog, dsref.Ref{}, 0, -1, true)
+	if removed < 1 || removed > len(items) {
+		return fmt.Errorf("can't rewrite %d versions of a history with %d versions", removed, len(items))
+	}
+	removedPaths := make([]string, 0, removed)
+	for i := removed - 1; i >= 0; i-- {
+		removedPaths = append(removedPaths, items[i].Path)
+	}
+
+	branchLog.Append(oplog.Op{
+		Type:      oplog.OpTypeRemove,
+		Model:     CommitModel,
+		Name:      RewriteOpName,
+		Relations: removedPaths,
+		Size:      int64(removed),
+		Timestamp: NewTimestamp(),
+		Note:      note,
+	})
+	for _, v := range versions {
+		op := versionSaveOp(v.Dataset)
+		op.Relations = v.Replaces
+		branchLog.Append(op)
+	}
+
+	if err := book.save(ctx); err != nil {
+		return err
+	}
+
+	if len(versions) > 0 {
+		info := dsref.ConvertDatasetToVersionInfo(versions[len(versions)-1].Dataset)
+		err = book.publisher.Publish(ctx, event.ETDatasetCommitChange, event.DsChange{
+			InitID:   initID,
+			TopIndex: branchLog.Size() - 1,
+			HeadRef:  info.Path,
+			Info:     &info,
+		})
+		if err != nil {
+			log.Error(err)
+		}
+	}
+	return nil
+}
+
+// rewrittenPaths maps the paths of versions replaced by history rewrites to
+// the paths of the versions that replaced them
+func rewrittenPaths(blog *BranchLog) map[string]string {
+	paths := map[string]string{}
+	for _, op := range blog.Ops() {
+		if op.Model == CommitModel && op.Type == oplog.OpTypeInit && op.Name != RevertOpName {
+			for _, replaced := range op.Relations {
+				paths[replaced] = op.Ref
+			}
+		}
+	}
+	return paths
+}
+
+// PruneOpName names the remove operation that drops versions from anywhere in
+// history, distinguishing pruned versions from deleted ones
+const PruneOpName = "prune"
+
+// WriteVersionPrune adds operations to a log removing versions from dataset
+// history. Unlike WriteVersionDelete, pruned versions don't need to be the
+// most recent. Prunes use the same semantics as deletes so peers that don't
+// know about pruning read the same history: the prune is recorded as a remove
+// operation named PruneOpName that lists the paths of pruned versions and
+// removes every version from the oldest pruned version to HEAD, followed by a
+// save operation for each of those versions that is kept. The latest version
+// can't be pruned
+func (book *Book) WriteVersionPrune(ctx context.Context, initID string, paths []string, note string) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	log.Debugf("WriteVersionPrune: %s, versions: %d", initID, len(paths))
+	if len(paths) == 0 {
+		return nil
+	}
+
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return err
+	}
+	if err := book.hasWriteAccess(branchLog.l); err != nil {
+		return err
+	}
+
+	items := branchToLogItems(branchLog, dsref.Ref{}, 0, -1, true)
+	pruned := map[string]bool{}
+	for _, p := range paths {
+		pruned[p] = true
+	}
+	removed, found := 0, 0
+	for i, item := range items {
+		if pruned[item.Path] {
+			removed = i + 1
+			found++
+		}
+	}
+	if found < len(pruned) {
+		return fmt.Errorf("can't prune versions that aren't in history: %w", ErrNotFound)
+	}
+	if pruned[items[0].Path] {
+		return fmt.Errorf("can't prune the latest version of a dataset")
+	}
+
+	saves := map[string]oplog.Op{}
+	for _, op := range branchLog.Ops() {
+		if op.Model == CommitModel && (op.Type == oplog.OpTypeInit || op.Type == oplog.OpTypeAmend) {
+			saves[op.Ref] = op
+		}
+	}
+
+	branchLog.Append(oplog.Op{
+		Type:      oplog.OpTypeRemove,
+		Model:     CommitModel,
+		Name:      PruneOpName,
+		Relations: paths,
+		Size:      int64(removed),
+		Timestamp: NewTimestamp(),
+		Note:      note,
+	})
+	// re-add kept versions oldest first
+	for i := removed - 1; i >= 0; i-- {
+		if pruned[items[i].Path] {
+			continue
+		}
+		op := saves[items[i].Path]
+		op.Type = oplog.OpTypeInit
+		if op.Name != RevertOpName {
+			// kept versions don't replace rewritten versions a second time
+			op.Relations = nil
+		}
+		branchLog.Append(op)
+	}
+
+	if err := book.save(ctx); err != nil {
+		return err
+	}
+
+	head := items[0]
+	err = book.publisher.Publish(ctx, event.ETDatasetCommitChange, event.DsChange{
+		InitID:   initID,
+		TopIndex: len(items) - len(pruned),
+		HeadRef:  head.Path,
+		Info:     &head.VersionInfo,
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return nil
+}
+
+// DroppedVersions lists versions that have been removed from dataset history
+// by deletes, prunes & rewrites, newest first
+func (book *Book) DroppedVersions(ctx context.Context, initID string) ([]DatasetLogItem, error) {
+	if book == nil {
+		return nil, ErrNoLogbook
+	}
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return nil, err
+	}
+	inHistory := map[string]bool{}
+	for _, item := range branchToLogItems(branchLog, dsref.Ref{}, 0, -1, true) {
+		inHistory[item.Path] = true
+	}
+
+	var dropped []DatasetLogItem
+	for _, op := range branchLog.Ops() {
+		if op.Model != CommitModel || (op.Type != oplog.OpTypeInit && op.Type != oplog.OpTypeAmend) {
+			continue
+		}
+		if !inHistory[op.Ref] {
+			inHistory[op.Ref] = true
+			dropped = append(dropped, itemFromOp(dsref.Ref{}, op))
+		}
+	}
+	sort.SliceStable(dropped, func(i, j int) bool {
+		return dropped[i].CommitTime.After(dropped[j].CommitTime)
+	})
+	return dropped, nil
+}
+
+// isPruneOp reports if an operation prunes versions from dataset history
+func isPruneOp(op oplog.Op) bool {
+	return op.Model == CommitModel && op.Type == oplog.OpTypeRemove && op.Name == PruneOpName
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
 // WriteRemotePush adds an operation to a log marking the publication of a
 // number of versions to a remote address. It returns a rollback function that
 // removes the operation when called
@@ -729,6 +1025,125 @@ func (book *Book) WriteRemoteDelete(ctx context.Context, initID string, revision
 	return sparseLog, rollback, nil
 }
 
+// VersionTag is a human-readable name for a dataset version
+type VersionTag struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WriteTag adds an operation to a log naming a version of a dataset. Tag
+// names are unique within a dataset. Moving a tag that already names a
+// different version requires force
+func (book *Book) WriteTag(ctx context.Context, initID, name, path string, force bool) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	log.Debugf("WriteTag: %s, name: %q, path: %q, force: %t", initID, name, path, force)
+
+	if !dsref.IsValidTagName(name) {
+		return fmt.Errorf("invalid tag name %q. tags must start with a letter or number, only contain letters, numbers, dashes, underscores, and dots, and can't look like a profile ID", name)
+	}
+
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return err
+	}
+	if err := book.hasWriteAccess(branchLog.l); err != nil {
+		return err
+	}
+
+	found := false
+	for _, item := range branchToLogItems(branchLog, dsref.Ref{}, 0, -1, true) {
+		if item.Path == path {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: version %s isn't in dataset history", ErrNotFound, path)
+	}
+
+	opType := oplog.OpTypeInit
+	if existing, ok := branchTags(branchLog)[name]; ok {
+		if existing.Path == path {
+			return nil
+		}
+		if !force {
+			return fmt.Errorf("%w: %q names version %s", ErrTagExists, name, existing.Path)
+		}
+		opType = oplog.OpTypeAmend
+	}
+
+	branchLog.Append(oplog.Op{
+		Type:      opType,
+		Model:     TagModel,
+		Name:      name,
+		Ref:       path,
+		Timestamp: NewTimestamp(),
+	})
+
+	return book.save(ctx)
+}
+
+// Tags lists the tags of a dataset, ordered by name. Tags that name versions
+// which have since been removed are left out
+func (book Book) Tags(ctx context.Context, initID string) ([]VersionTag, error) {
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]bool{}
+	for _, item := range branchToLogItems(branchLog, dsref.Ref{}, 0, -1, true) {
+		paths[item.Path] = true
+	}
+
+	tags := []VersionTag{}
+	for _, tag := range branchTags(branchLog) {
+		if paths[tag.Path] {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// branchTags collects the current tags of a branch, keyed by name
+func branchTags(blog *BranchLog) map[string]VersionTag {
+	tags := map[string]VersionTag{}
+	for _, op := range blog.Ops() {
+		if op.Model != TagModel {
+			continue
+		}
+		switch op.Type {
+		case oplog.OpTypeInit, oplog.OpTypeAmend:
+			tags[op.Name] = VersionTag{
+				Name:      op.Name,
+				Path:      op.Ref,
+				Timestamp: time.Unix(0, op.Timestamp),
+			}
+		case oplog.OpTypeRemove:
+			delete(tags, op.Name)
+		}
+	}
+
+	// tags follow versions through history rewrites
+	if rewrites := rewrittenPaths(blog); len(rewrites) > 0 {
+		for name, tag := range tags {
+			for i := 0; i < len(rewrites); i++ {
+				path, ok := rewrites[tag.Path]
+				if !ok {
+					break
+				}
+				tag.Path = path
+			}
+			tags[name] = tag
+		}
+	}
+	return tags
+}
+
 // ListAllLogs lists all of the logs in the logbook
 func (book Book) ListAllLogs(ctx context.Context) ([]*oplog.Log, error) {
 	return book.store.Logs(ctx, 0, -1)
@@ -749,20 +1164,7 @@ func (book *Book) AllReferencedDatasetPaths(ctx context.Context) (map[string]str
 }
 
 func addReferencedPaths(log *oplog.Log, paths map[string]struct{}) {
-	ps := []string{}
-	for _, op := range log.Ops {
-		if op.Model == CommitModel {
-			switch op.Type {
-			case oplog.OpTypeInit:
-				ps = append(ps, op.Ref)
-			case oplog.OpTypeRemove:
-				ps = ps[:len(ps)-int(op.Size)]
-			case oplog.OpTypeAmend:
-				ps[len(ps)-1] = op.Ref
-			}
-		}
-	}
-	for _, p := range ps {
+	for _, p := range commitPaths(log.Ops) {
 		paths[p] = struct{}{}
 	}
 
@@ -838,6 +1240,25 @@ func (book *Book) latestSavePath(branchLog *oplog.Log) string {
 	return ""
 }
 
+// commitPaths collapses the commit operations of a log into the paths of
+// versions that remain in history, oldest first
+func commitPaths(ops []oplog.Op) []string {
+	ps := []string{}
+	for _, op := range ops {
+		if op.Model == CommitModel {
+			switch op.Type {
+			case oplog.OpTypeInit:
+				ps = append(ps, op.Ref)
+			case oplog.OpTypeRemove:
+				ps = ps[:len(ps)-int(op.Size)]
+			case oplog.OpTypeAmend:
+				ps[len(ps)-1] = op.Ref
+			}
+		}
+	}
+	return ps
+}
+
 // UserDatasetBranchesLog gets a user's log and a dataset reference.
 // the returned log will be a user log with only one dataset log containing all
 // known branches:
@@ -1007,7 +1428,7 @@ func (book *Book) ConstructDatasetLog(ctx context.Context, ref dsref.Ref, histor
 }
 
 func itemFromOp(ref dsref.Ref, op oplog.Op) DatasetLogItem {
-	return DatasetLogItem{
+	item := DatasetLogItem{
 		VersionInfo: dsref.VersionInfo{
 			Username:   ref.Username,
 			ProfileID:  ref.ProfileID,
@@ -1018,6 +1439,10 @@ func itemFromOp(ref dsref.Ref, op oplog.Op) DatasetLogItem {
 		},
 		CommitTitle: op.Note,
 	}
+	if op.Name == RevertOpName && len(op.Relations) == 1 {
+		item.Reverted = op.Relations[0]
+	}
+	return item
 }
 
 // Items collapses the history of a dataset branch into linear log items
@@ -1034,6 +1459,119 @@ func (book Book) Items(ctx context.Context, ref dsref.Ref, offset, limit int) ([
 	return branchToLogItems(branchLog, ref, offset, limit, true), nil
 }
 
+// ResolveRevision selects the version ref.Revision refers to from the
+// history of a dataset, setting ref.Path to the selected version & clearing
+// ref.Revision. Ancestors are counted from ref.Path, which must be set
+func (book Book) ResolveRevision(ctx context.Context, ref *dsref.Ref) error {
+	initID, err := book.RefToInitID(dsref.Ref{Username: ref.Username, Name: ref.Name})
+	if err != nil {
+		return err
+	}
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return err
+	}
+	return resolveRevision(branchLog, ref)
+}
+
+// ResolveLogRevision works like ResolveRevision, selecting from the history
+// in a dataset branch log
+func ResolveLogRevision(l *oplog.Log, ref *dsref.Ref) error {
+	return resolveRevision(newBranchLog(l), ref)
+}
+
+func resolveRevision(branchLog *BranchLog, ref *dsref.Ref) error {
+	if ref.Revision == "" {
+		return nil
+	}
+	rev, err := dsref.ParseRevision(ref.Revision)
+	if err != nil {
+		return err
+	}
+
+	items := branchToLogItems(branchLog, *ref, 0, -1, true)
+	history := make([]dsref.VersionInfo, len(items))
+	for i, item := range items {
+		history[i] = item.VersionInfo
+	}
+
+	tags := map[string]string{}
+	for name, tag := range branchTags(branchLog) {
+		tags[name] = tag.Path
+	}
+
+	path, err := rev.Select(history, ref.Path, tags)
+	if err != nil {
+		return err
+	}
+	ref.Path = path
+	ref.Revision = ""
+	return nil
+}
+
+// VersionRemotes lists the addresses of remotes a dataset version has been
+// pushed to and not since removed from, most recent push first. Addresses are
+// returned as they were recorded by the pushing client
+func (book Book) VersionRemotes(ctx context.Context, initID, path string) ([]string, error) {
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		paths   []string
+		remotes = map[string][]string{}
+	)
+	for _, op := range branchLog.Ops() {
+		switch op.Model {
+		case CommitModel:
+			switch op.Type {
+			case oplog.OpTypeInit:
+				paths = append(paths, op.Ref)
+			case oplog.OpTypeAmend:
+				if len(paths) > 0 {
+					paths[len(paths)-1] = op.Ref
+				}
+			case oplog.OpTypeRemove:
+				if n := int(op.Size); n < len(paths) {
+					paths = paths[:len(paths)-n]
+				} else {
+					paths = nil
+				}
+			}
+		case PushModel:
+			if len(op.Relations) == 0 {
+				continue
+			}
+			addr := op.Relations[0]
+			for i := 1; i <= int(op.Size) && i <= len(paths); i++ {
+				p := paths[len(paths)-i]
+				remotes[p] = withoutString(remotes[p], addr)
+				if op.Type == oplog.OpTypeInit {
+					remotes[p] = append(remotes[p], addr)
+				}
+			}
+		}
+	}
+
+	addrs := remotes[path]
+	for i := len(addrs)/2 - 1; i >= 0; i-- {
+		opp := len(addrs) - 1 - i
+		addrs[i], addrs[opp] = addrs[opp], addrs[i]
+	}
+	return addrs, nil
+}
+
+func withoutString(strs []string, s string) []string {
+	res := strs[:0]
+	for _, str := range strs {
+		if str != s {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
 // ConvertLogsToItems collapses the history of a dataset branch into linear log items
 func ConvertLogsToItems(l *oplog.Log, ref dsref.Ref) []DatasetLogItem {
 	return branchToLogItems(newBranchLog(l), ref, 0, -1, true)
@@ -1045,16 +1583,25 @@ func ConvertLogsToItems(l *oplog.Log, ref dsref.Ref) []DatasetLogItem {
 func branchToLogItems(blog *BranchLog, ref dsref.Ref, offset, limit int, collapseAllDeletes bool) []DatasetLogItem {
 	refs := []DatasetLogItem{}
 	deleteAtEnd := 0
+	// versions kept by a prune are removed & saved again, and stay published
+	prunePublished := map[string]bool{}
 	for _, op := range blog.Ops() {
 		switch op.Model {
 		case CommitModel:
 			switch op.Type {
 			case oplog.OpTypeInit:
-				refs = append(refs, itemFromOp(ref, op))
+				item := itemFromOp(ref, op)
+				item.Published = prunePublished[op.Ref]
+				refs = append(refs, item)
 			case oplog.OpTypeAmend:
 				deleteAtEnd = 0
 				refs[len(refs)-1] = itemFromOp(ref, op)
 			case oplog.OpTypeRemove:
+				if isPruneOp(op) {
+					for _, item := range refs[len(refs)-int(op.Size):] {
+						prunePublished[item.Path] = item.Published
+					}
+				}
 				if collapseAllDeletes {
 					refs = refs[:len(refs)-int(op.Size)]
 				} else {
@@ -1083,6 +1630,17 @@ func branchToLogItems(blog *BranchLog, ref dsref.Ref, offset, limit int, collaps
 		}
 	}
 
+	if tags := branchTags(blog); len(tags) > 0 {
+		for i, item := range refs {
+			for name, tag := range tags {
+				if tag.Path == item.Path {
+					refs[i].Tags = append(refs[i].Tags, name)
+				}
+			}
+			sort.Strings(refs[i].Tags)
+		}
+	}
+
 	// reverse the slice, placing newest first
 	// https://github.com/golang/go/wiki/SliceTricks#reversing
 	for i := len(refs)/2 - 1; i >= 0; i-- {
@@ -1145,6 +1703,7 @@ var actionStrings = map[uint32][3]string{
 	CommitModel:  {"save commit", "amend commit", "remove commit"},
 	PushModel:    {"publish", "", "unpublish"},
 	ACLModel:     {"update access", "update access", "remove all access"},
+	TagModel:     {"tag version", "move tag", "remove tag"},
 }
 
 func logEntryFromOp(author string, op oplog.Op) LogEntry {
@@ -1152,10 +1711,28 @@ func logEntryFromOp(author string, op oplog.Op) LogEntry {
 	if note == "" && op.Name != "" {
 		note = op.Name
 	}
+	action := actionStrings[op.Model][int(op.Type)-1]
+	if op.Model == CommitModel && op.Type == oplog.OpTypeInit && op.Name == RevertOpName {
+		action = "revert commit"
+		note = op.Note
+	}
+	if op.Model == CommitModel && op.Type == oplog.OpTypeRemove && op.Name == RewriteOpName {
+		action = "rewrite history"
+		note = op.Note
+	}
+	if isPruneOp(op) {
+		action = "prune versions"
+		note = op.Note
+		if note == "" && len(op.Relations) == 1 {
+			note = "1 version"
+		} else if note == "" {
+			note = fmt.Sprintf("%d versions", len(op.Relations))
+		}
+	}
 	return LogEntry{
 		Timestamp: time.Unix(0, op.Timestamp),
 		Author:    author,
-		Action:    actionStrings[op.Model][int(op.Type)-1],
+		Action:    action,
 		Note:      note,
 	}
 }
@@ -1235,6 +1812,10 @@ type DatasetLogItem struct {
 	CommitTitle string `json:"commitTitle,omitempty"`
 	// Message field from the commit
 	CommitMessage string `json:"commitMessage,omitempty"`
+	// Names of tags that point to this version
+	Tags []string `json:"tags,omitempty"`
+	// Path of the earlier version this version restores, if it's a revert
+	Reverted string `json:"reverted,omitempty"`
 }
 
 // PlainOp is a human-oriented representation of oplog.Op intended for serialization
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
@@ -15,6 +17,7 @@ import (
 	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/localfs"
 	testPeers "github.com/qri-io/qri/config/test"
 	"github.com/qri-io/qri/dsref"
 	dsrefspec "github.com/qri-io/qri/dsref/spec"
@@ -217,6 +220,9 @@ func TestNilCallable(t *testing.T) {
 	if err = book.WriteAuthorRename(ctx, ""); err != logbook.ErrNoLogbook {
 		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
 	}
+	if err = book.WriteKeyRotation(ctx, nil); err != logbook.ErrNoLogbook {
+		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
+	}
 	if _, err = book.WriteDatasetInit(ctx, ""); err != logbook.ErrNoLogbook {
 		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
 	}
@@ -241,6 +247,9 @@ func TestNilCallable(t *testing.T) {
 	if err = book.WriteVersionSave(ctx, initID, nil); err != logbook.ErrNoLogbook {
 		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
 	}
+	if err = book.WriteVersionRevert(ctx, initID, nil, ""); err != logbook.ErrNoLogbook {
+		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
+	}
 	if _, err = book.ResolveRef(ctx, nil); err != dsref.ErrRefNotFound {
 		t.Errorf("expected '%s', got: %v", dsref.ErrRefNotFound, err)
 	}
@@ -440,6 +449,9 @@ func TestWritePermissions(t *testing.T) {
 	if _, _, err := tr.Book.WriteRemoteDelete(ctx, initID, 1, "https://registry.example.com"); !errors.Is(err, logbook.ErrAccessDenied) {
 		t.Errorf("WriteUnpublish to an oplog the book author doesn't own must return a wrap of logbook.ErrAccessDenied")
 	}
+	if err := tr.Book.WriteTag(ctx, initID, "v1", "/ipld/QmExample", true); !errors.Is(err, logbook.ErrAccessDenied) {
+		t.Errorf("WriteTag to an oplog the book author doesn't own must return a wrap of logbook.ErrAccessDenied")
+	}
 }
 
 func TestPushModel(t *testing.T) {
@@ -531,6 +543,65 @@ func TestPushModel(t *testing.T) {
 
 }
 
+func TestVersionRemotes(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initID, err := tr.Book.WriteDatasetInit(ctx, "version_remotes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	save := func(path string) {
+		err := tr.Book.WriteVersionSave(ctx, initID, &dataset.Dataset{
+			Peername: tr.Username,
+			Name:     "version_remotes",
+			Commit:   &dataset.Commit{Title: path},
+			Path:     path,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	push := func(addr string) {
+		if _, _, err := tr.Book.WriteRemotePush(ctx, initID, 1, addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save("HashOfVersion1")
+	push("remote_a")
+	save("HashOfVersion2")
+	push("remote_a")
+	push("remote_b")
+	if _, _, err := tr.Book.WriteRemoteDelete(ctx, initID, 1, "remote_a"); err != nil {
+		t.Fatal(err)
+	}
+	save("HashOfVersion3")
+
+	cases := []struct {
+		path   string
+		expect []string
+	}{
+		{"HashOfVersion1", []string{"remote_a"}},
+		{"HashOfVersion2", []string{"remote_b"}},
+		{"HashOfVersion3", nil},
+	}
+
+	for _, c := range cases {
+		got, err := tr.Book.VersionRemotes(ctx, initID, c.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("%s remotes mismatch (-want +got):\n%s", c.path, diff)
+		}
+	}
+}
+
 func TestDatasetLogNaming(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
@@ -791,6 +862,99 @@ func TestRenameAuthor(t *testing.T) {
 
 }
 
+func TestWriteKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "logbook_key_rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fs, err := localfs.NewFS(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := filepath.Join(dir, "logbook.qfb")
+
+	pk := testPrivKey(t)
+	next := testPrivKey2(t)
+	book, err := logbook.NewJournal(pk, "rotator", event.NilBus, fs, location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevAuthor := identity.NewAuthor(book.AuthorID(), pk.GetPublic(), book.Username())
+	peerID, err := book.ActivePeerID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initID, err := book.WriteDatasetInit(ctx, "rotated")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := book.WriteKeyRotation(ctx, pk); err == nil {
+		t.Error("expected rotating to the current key to fail")
+	}
+	if err := book.WriteKeyRotation(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+
+	// the author keeps its ID, only the signing key changes
+	if book.AuthorID() != prevAuthor.AuthorID() {
+		t.Errorf("expected author ID to stay %q, got %q", prevAuthor.AuthorID(), book.AuthorID())
+	}
+	if id, _ := book.ActivePeerID(ctx); id != peerID {
+		t.Errorf("expected active peer ID to stay %q, got %q", peerID, id)
+	}
+	if !book.AuthorPubKey().Equals(next.GetPublic()) {
+		t.Error("expected author public key to be the new key")
+	}
+
+	// the logbook is re-encrypted with the new key
+	if _, err := logbook.NewJournal(pk, "rotator", event.NilBus, fs, location); err == nil {
+		t.Error("expected loading a rotated logbook with the replaced key to fail")
+	}
+	reloaded, err := logbook.NewJournal(next, "rotator", event.NilBus, fs, location)
+	if err != nil {
+		t.Fatalf("loading rotated logbook with new key: %s", err)
+	}
+	if _, err := reloaded.RefToInitID(dsref.Ref{Username: "rotator", Name: "rotated"}); err != nil {
+		t.Errorf("expected rotated logbook to keep datasets. got: %s", err)
+	}
+	if reloaded.AuthorID() != book.AuthorID() {
+		t.Errorf("expected reloaded author ID to be %q, got %q", book.AuthorID(), reloaded.AuthorID())
+	}
+	if id, _ := reloaded.ActivePeerID(ctx); id != peerID {
+		t.Errorf("expected reloaded active peer ID to be %q, got %q", peerID, id)
+	}
+
+	// peers that know the author by the replaced key accept logs signed with the
+	// new key, and see the rotation
+	lg, err := book.UserDatasetBranchesLog(ctx, initID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := book.SignLog(lg); err != nil {
+		t.Fatal(err)
+	}
+	peer, err := logbook.NewJournal(testPeers.GetTestPeerInfo(2).PrivKey, "peer", event.NilBus, qfs.NewMemFS(), "/mem/logbook.qfb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := peer.MergeLog(ctx, prevAuthor, lg); err != nil {
+		t.Fatalf("merging log signed with rotated key: %s", err)
+	}
+	merged, err := peer.Log(ctx, book.AuthorID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Author() != peerID {
+		t.Errorf("expected merged author log author to be %q, got %q", peerID, merged.Author())
+	}
+	if chain, err := merged.KeyChain(); err != nil || len(chain) != 2 || !chain[1].Equals(next.GetPublic()) {
+		t.Errorf("expected merged author log to rotate to the new key. got: %d keys, %v", len(chain), err)
+	}
+}
+
 func TestRenameDataset(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
@@ -894,6 +1058,422 @@ func TestItems(t *testing.T) {
 	}
 }
 
+func TestResolveRevision(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+
+	good := []struct {
+		revision string
+		expect   string
+	}{
+		{"~1", "QmHashOfVersion4"},
+		{"^^", "QmHashOfVersion3"},
+		{"@{2000-01-03T20:00:00-05:00}", "QmHashOfVersion4"},
+		{"@{2000-01-03}", "QmHashOfVersion3"},
+	}
+
+	for _, c := range good {
+		ref, err := dsref.Parse("test_author/world_bank_population" + c.revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref.Path = "QmHashOfVersion5"
+		if err := book.ResolveRevision(tr.Ctx, &ref); err != nil {
+			t.Errorf("revision %q: unexpected error: %s", c.revision, err)
+			continue
+		}
+		if ref.Path != c.expect {
+			t.Errorf("revision %q: path mismatch. want: %q, got: %q", c.revision, c.expect, ref.Path)
+		}
+		if ref.Revision != "" {
+			t.Errorf("revision %q: expected revision to be cleared, got %q", c.revision, ref.Revision)
+		}
+	}
+
+	bad := []string{"~3", "@{1999-01-01}", "@unknown_tag"}
+	for _, revision := range bad {
+		ref, err := dsref.Parse("test_author/world_bank_population" + revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref.Path = "QmHashOfVersion5"
+		if err := book.ResolveRevision(tr.Ctx, &ref); !errors.Is(err, dsref.ErrRevisionNotFound) {
+			t.Errorf("revision %q: expected error %q, got: %v", revision, dsref.ErrRevisionNotFound, err)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+
+	if err := book.WriteTag(tr.Ctx, initID, "v1.0", "QmHashOfVersion4", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.WriteTag(tr.Ctx, initID, "latest", "QmHashOfVersion5", false); err != nil {
+		t.Fatal(err)
+	}
+	// re-tagging the same version is a no-op
+	if err := book.WriteTag(tr.Ctx, initID, "v1.0", "QmHashOfVersion4", false); err != nil {
+		t.Errorf("expected re-tagging the same version to succeed, got: %s", err)
+	}
+
+	if err := book.WriteTag(tr.Ctx, initID, "v1.0", "QmHashOfVersion3", false); !errors.Is(err, logbook.ErrTagExists) {
+		t.Errorf("expected moving a tag without force to fail with %q, got: %v", logbook.ErrTagExists, err)
+	}
+	if err := book.WriteTag(tr.Ctx, initID, "v2", "QmHashOfVersion1", false); !errors.Is(err, logbook.ErrNotFound) {
+		t.Errorf("expected tagging a removed version to fail with %q, got: %v", logbook.ErrNotFound, err)
+	}
+	if err := book.WriteTag(tr.Ctx, initID, "-bad", "QmHashOfVersion5", false); err == nil {
+		t.Error("expected invalid tag name to error")
+	}
+
+	tags, err := book.Tags(tr.Ctx, initID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name+"="+tag.Path)
+	}
+	expect := []string{"latest=QmHashOfVersion5", "v1.0=QmHashOfVersion4"}
+	if diff := cmp.Diff(expect, names); diff != "" {
+		t.Errorf("tags mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := book.WriteTag(tr.Ctx, initID, "v1.0", "QmHashOfVersion3", true); err != nil {
+		t.Fatalf("moving a tag with force: %s", err)
+	}
+
+	items, err := book.Items(tr.Ctx, tr.WorldBankRef(), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotTags := [][]string{}
+	for _, item := range items {
+		gotTags = append(gotTags, item.Tags)
+	}
+	expectTags := [][]string{{"latest"}, nil, {"v1.0"}}
+	if diff := cmp.Diff(expectTags, gotTags); diff != "" {
+		t.Errorf("item tags mismatch (-want +got):\n%s", diff)
+	}
+
+	ref, err := dsref.Parse("test_author/world_bank_population@v1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref.Path = "QmHashOfVersion5"
+	if err := book.ResolveRevision(tr.Ctx, &ref); err != nil {
+		t.Fatal(err)
+	}
+	if ref.Path != "QmHashOfVersion3" {
+		t.Errorf("expected tag revision to resolve to moved tag. want: %q, got: %q", "QmHashOfVersion3", ref.Path)
+	}
+}
+
+func TestWriteVersionRewrite(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+
+	// a peer pulls the dataset before history is rewritten
+	peer, err := logbook.NewJournal(testPrivKey2(t), "user2", tr.bus, qfs.NewMemFS(), "/mem/peer.qfb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer := func() error {
+		l, err := book.UserDatasetBranchesLog(tr.Ctx, initID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := book.SignLog(l); err != nil {
+			t.Fatal(err)
+		}
+		return peer.MergeLog(tr.Ctx, book.Author(), l)
+	}
+	if err := transfer(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := book.WriteTag(tr.Ctx, initID, "v4", "QmHashOfVersion4", false); err != nil {
+		t.Fatal(err)
+	}
+	squashed := &dataset.Dataset{
+		Commit: &dataset.Commit{
+			Timestamp: time.Date(2000, time.January, 5, 0, 0, 0, 0, time.UTC),
+			Title:     "squash 2 versions",
+		},
+		Path:         "QmHashOfSquashed",
+		PreviousPath: "QmHashOfVersion3",
+	}
+	if err := book.WriteVersionRewrite(tr.Ctx, initID, 4, nil, ""); err == nil {
+		t.Error("expected removing more versions than exist to error")
+	}
+	versions := []logbook.RewrittenVersion{{Dataset: squashed, Replaces: []string{"QmHashOfVersion4", "QmHashOfVersion5"}}}
+	if err := book.WriteVersionRewrite(tr.Ctx, initID, 2, versions, "squash"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the peer merges the rewrite cleanly
+	if err := transfer(); err != nil {
+		t.Fatalf("merging a rewritten log: %s", err)
+	}
+	for _, b := range []*logbook.Book{book, peer} {
+		items, err := b.Items(tr.Ctx, tr.WorldBankRef(), 0, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths := []string{}
+		for _, item := range items {
+			paths = append(paths, item.Path)
+		}
+		if diff := cmp.Diff([]string{"QmHashOfSquashed", "QmHashOfVersion3"}, paths); diff != "" {
+			t.Errorf("history mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	tags, err := book.Tags(tr.Ctx, initID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Path != "QmHashOfSquashed" {
+		t.Errorf("expected tag to follow the rewritten version, got: %v", tags)
+	}
+
+	entries, err := book.LogEntries(tr.Ctx, tr.WorldBankRef(), 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, e := range entries {
+		if e.Action == "rewrite history" && e.Note == "squash" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected log entries to include the rewrite, got: %v", entries)
+	}
+
+	// a log that silently drops operations is refused
+	l, err := book.UserDatasetBranchesLog(tr.Ctx, initID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := l.DeepCopy()
+	blog := forged.Logs[0].Logs[0]
+	blog.Ops = append(blog.Ops[:1:1], blog.Ops[2:]...)
+	blog.Ops = append(blog.Ops, oplog.Op{Type: oplog.OpTypeInit, Model: logbook.CommitModel, Ref: "QmHashOfForgery"})
+	if err := book.SignLog(forged); err != nil {
+		t.Fatal(err)
+	}
+	if err := peer.MergeLog(tr.Ctx, book.Author(), forged); !errors.Is(err, oplog.ErrDivergentLog) {
+		t.Errorf("expected merging a divergent log to fail with %q, got: %v", oplog.ErrDivergentLog, err)
+	}
+}
+
+func TestWriteVersionPrune(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+	ref := tr.WorldBankRef()
+
+	historyPaths := func() []string {
+		items, err := book.Items(tr.Ctx, ref, 0, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths := []string{}
+		for _, item := range items {
+			paths = append(paths, item.Path)
+		}
+		return paths
+	}
+	before := historyPaths()
+	if len(before) < 3 {
+		t.Fatalf("expected at least 3 versions, got: %v", before)
+	}
+
+	if err := book.WriteVersionPrune(tr.Ctx, initID, []string{before[0]}, ""); err == nil {
+		t.Error("expected pruning the latest version to error")
+	}
+	if err := book.WriteVersionPrune(tr.Ctx, initID, []string{"QmHashOfNothing"}, ""); !errors.Is(err, logbook.ErrNotFound) {
+		t.Errorf("expected pruning an unknown version to return ErrNotFound, got: %v", err)
+	}
+
+	if _, _, err := book.WriteRemotePush(tr.Ctx, initID, 1, "remote/address"); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.WriteVersionPrune(tr.Ctx, initID, []string{before[1]}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := append([]string{before[0]}, before[2:]...)
+	if diff := cmp.Diff(expect, historyPaths()); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+
+	resolved := dsref.Ref{Username: ref.Username, Name: ref.Name}
+	if _, err := book.ResolveRef(tr.Ctx, &resolved); err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Path != before[0] {
+		t.Errorf("expected head to remain %q, got %q", before[0], resolved.Path)
+	}
+
+	items, err := book.Items(tr.Ctx, ref, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !items[0].Published {
+		t.Error("expected versions kept by a prune to stay published")
+	}
+
+	// peers that don't know about prunes read prunes as deletes, and get the
+	// same history
+	l, err := book.UserDatasetBranchesLog(tr.Ctx, initID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch := &oplog.Log{Ops: append([]oplog.Op{}, l.Logs[0].Logs[0].Ops...)}
+	for i, op := range branch.Ops {
+		if op.Name == logbook.PruneOpName {
+			branch.Ops[i].Name = ""
+		}
+	}
+	generic := []string{}
+	for _, item := range logbook.ConvertLogsToItems(branch, ref) {
+		generic = append(generic, item.Path)
+	}
+	if diff := cmp.Diff(expect, generic); diff != "" {
+		t.Errorf("history read without prune support mismatch (-want +got):\n%s", diff)
+	}
+
+	// pushes written after a prune apply to the versions that remain
+	if _, _, err := book.WriteRemotePush(tr.Ctx, initID, 2, "other/address"); err != nil {
+		t.Fatal(err)
+	}
+	remotes, err := book.VersionRemotes(tr.Ctx, initID, before[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"other/address"}, remotes); diff != "" {
+		t.Errorf("version remotes mismatch (-want +got):\n%s", diff)
+	}
+
+	paths, err := book.AllReferencedDatasetPaths(tr.Ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := paths[before[1]]; ok {
+		t.Errorf("expected pruned version not to be referenced")
+	}
+
+	entries, err := book.LogEntries(tr.Ctx, ref, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, e := range entries {
+		if e.Action == "prune versions" && e.Note == "1 version" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected log entries to include the prune, got: %v", entries)
+	}
+}
+
+func TestWriteVersionRevert(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+	ref := tr.WorldBankRef()
+
+	before, err := book.Items(tr.Ctx, ref, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverted := before[len(before)-1].Path
+	ds := &dataset.Dataset{
+		Peername: tr.Username,
+		Name:     ref.Name,
+		Commit: &dataset.Commit{
+			Timestamp: time.Date(2000, time.January, 6, 0, 0, 0, 0, time.UTC),
+			Title:     "revert to v1",
+		},
+		Path:         "QmHashOfVersion6",
+		PreviousPath: before[0].Path,
+	}
+	if err := book.WriteVersionRevert(tr.Ctx, initID, ds, reverted); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := book.Items(tr.Ctx, ref, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].Path != ds.Path || items[0].Reverted != reverted {
+		t.Errorf("expected head to be a revert to %q, got: %#v", reverted, items[0])
+	}
+	for _, item := range items[1:] {
+		if item.Reverted != "" {
+			t.Errorf("expected only the revert to record a reverted version, got: %#v", item)
+		}
+	}
+
+	// reverts aren't rewrites, the reverted version stays in history
+	resolved := dsref.Ref{Username: ref.Username, Name: ref.Name, Path: reverted}
+	if _, err := book.ResolveRef(tr.Ctx, &resolved); err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Path != reverted {
+		t.Errorf("expected reverted version to resolve to itself, got: %q", resolved.Path)
+	}
+
+	// versions kept by a prune are saved again, and stay reverts
+	if err := book.WriteVersionPrune(tr.Ctx, initID, []string{items[1].Path}, ""); err != nil {
+		t.Fatal(err)
+	}
+	items, err = book.Items(tr.Ctx, ref, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].Reverted != reverted {
+		t.Errorf("expected revert kept by a prune to record %q, got: %q", reverted, items[0].Reverted)
+	}
+
+	entries, err := book.LogEntries(tr.Ctx, ref, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, e := range entries {
+		if e.Action == "revert commit" && e.Note == ds.Commit.Title {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected log entries to include the revert, got: %v", entries)
+	}
+}
+
 func TestConstructDatasetLog(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
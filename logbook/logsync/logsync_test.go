@@ -2,6 +2,7 @@ package logsync
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"net/http/httptest"
@@ -297,6 +298,78 @@ func TestHookErrors(t *testing.T) {
 	}
 }
 
+func TestKeyRotationSync(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	nasdaqRef, err := writeNasdaqLogs(tr.Ctx, tr.A)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerID, err := tr.A.ActivePeerID(tr.Ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the remote knows the author by the same IDs before & after the rotation,
+	// with the key the author currently signs with
+	lsA := New(tr.A)
+	lsB := New(tr.B, func(o *Options) {
+		o.PushFinalCheck = func(ctx context.Context, author identity.Author, ref dsref.Ref, l *oplog.Log) error {
+			if author.AuthorID() != tr.A.AuthorID() {
+				return fmt.Errorf("expected sender author ID %q, got %q", tr.A.AuthorID(), author.AuthorID())
+			}
+			if !author.AuthorPubKey().Equals(tr.A.AuthorPubKey()) {
+				return fmt.Errorf("expected sender to use the current key")
+			}
+			if l.Author() != peerID {
+				return fmt.Errorf("expected log author %q, got %q", peerID, l.Author())
+			}
+			return nil
+		}
+	})
+	s := httptest.NewServer(HTTPHandler(lsB))
+	defer s.Close()
+
+	push, err := lsA.NewPush(nasdaqRef, s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := push.Do(tr.Ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tr.A.WriteKeyRotation(tr.Ctx, next); err != nil {
+		t.Fatal(err)
+	}
+
+	// pushes signed with the new key carry the rotation to the remote
+	if push, err = lsA.NewPush(nasdaqRef, s.URL); err != nil {
+		t.Fatal(err)
+	}
+	if err := push.Do(tr.Ctx); err != nil {
+		t.Fatalf("pushing after key rotation: %s", err)
+	}
+
+	authorLog, err := tr.B.Log(tr.Ctx, tr.A.AuthorID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorLog.Author() != peerID {
+		t.Errorf("expected remote copy of author log author to be %q, got %q", peerID, authorLog.Author())
+	}
+	if id, _ := tr.A.ActivePeerID(tr.Ctx); id != peerID {
+		t.Errorf("expected active peer ID to stay %q after key rotation, got %q", peerID, id)
+	}
+	if chain, err := authorLog.KeyChain(); err != nil || len(chain) != 2 {
+		t.Errorf("expected remote copy of author log to have a 2 key chain. got: %d keys, %v", len(chain), err)
+	}
+}
+
 func TestNilCallable(t *testing.T) {
 	var logsync *Logsync
 
@@ -17,6 +17,7 @@ import (
 	"crypto/md5"
 	"crypto/rand"
 	"encoding/base32"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 
 	flatbuffers "github.com/google/flatbuffers/go"
 	crypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/qri-io/qri/identity"
 	"github.com/qri-io/qri/logbook/oplog/logfb"
 	"golang.org/x/crypto/blake2b"
 )
@@ -31,6 +33,13 @@ import (
 var (
 	// ErrNotFound is a sentinel error for data not found in a logbook
 	ErrNotFound = fmt.Errorf("log: not found")
+	// ErrDivergentLog indicates two versions of a log disagree on operations
+	// they both contain. Logs are append-only, merging divergent logs would
+	// drop operations
+	ErrDivergentLog = fmt.Errorf("log: logs have diverged")
+	// ErrInvalidKeyRotation indicates a key rotation operation isn't signed by
+	// the key it replaces
+	ErrInvalidKeyRotation = fmt.Errorf("log: invalid key rotation")
 )
 
 // Logstore persists a set of operations organized into hierarchical append-only
@@ -131,6 +140,15 @@ func GetWithSparseAncestorsAllDescendants(ctx context.Context, store Logstore, i
 		}
 	}
 
+	// walk from a copy, sparse ancestors must not replace the parents of logs
+	// in the store
+	l = &Log{
+		parent:    l.parent,
+		ParentID:  l.ParentID,
+		Signature: l.Signature,
+		Ops:       l.Ops,
+		Logs:      l.Logs,
+	}
 	cursor := l
 	for cursor.ParentID != "" {
 		parent := cursor.parent
@@ -213,8 +231,7 @@ func (j *Journal) MergeLog(ctx context.Context, incoming *Log) error {
 	found, err := j.Get(ctx, incoming.ID())
 	if err == nil {
 		// If found, merge it
-		found.Merge(incoming)
-		return nil
+		return found.Merge(incoming)
 	} else if !errors.Is(err, ErrNotFound) {
 		// Okay if log is not found by id, but any other error should be returned
 		return err
@@ -224,8 +241,7 @@ func (j *Journal) MergeLog(ctx context.Context, incoming *Log) error {
 	for _, lg := range j.logs {
 		if lg.FirstOpAuthorID() == incoming.FirstOpAuthorID() {
 			found = lg
-			found.Merge(incoming)
-			return nil
+			return found.Merge(incoming)
 		}
 	}
 
@@ -473,7 +489,7 @@ func (lg *Log) Append(op Op) {
 		if op.Name != "" {
 			lg.name = op.Name
 		}
-		if op.AuthorID != "" {
+		if op.AuthorID != "" && !op.IsKeyRotation() {
 			lg.authorID = op.AuthorID
 		}
 	}
@@ -507,12 +523,13 @@ func (lg Log) Model() uint32 {
 
 // Author returns one of two different things: either the user's ProfileID,
 // or the has of the first Op for the UserLog, depending on if they have
-// ever changed their username.
+// ever changed their username. Key rotations don't change the author, the ID
+// of the key a log is signed with is tracked by the rotation ops
 func (lg Log) Author() (identifier string) {
 	if lg.authorID == "" {
 		m := lg.Model()
 		for _, o := range lg.Ops {
-			if o.Model == m && o.AuthorID != "" {
+			if o.Model == m && o.AuthorID != "" && !o.IsKeyRotation() {
 				lg.authorID = o.AuthorID
 			}
 		}
@@ -623,8 +640,49 @@ func (lg *Log) AddChild(l *Log) {
 // Merge combines two logs that are assumed to be a shared root, combining
 // children from both branches, matching branches prefer longer Opsets
 // Merging relies on comparison of initialization operations, which
-// must be present to constitute a match
-func (lg *Log) Merge(l *Log) {
+// must be present to constitute a match. Merge refuses to combine logs that
+// disagree on shared operations, returning ErrDivergentLog without making any
+// changes
+func (lg *Log) Merge(l *Log) error {
+	if err := lg.checkDivergence(l); err != nil {
+		return err
+	}
+	lg.merge(l)
+	return nil
+}
+
+// checkDivergence confirms the operations of one log are a prefix of the
+// other's, for a log & all matching descendants. Logs with different
+// initialization operations are distinct logs, & only their children are
+// compared
+func (lg *Log) checkDivergence(l *Log) error {
+	n := len(lg.Ops)
+	if len(l.Ops) < n {
+		n = len(l.Ops)
+	}
+	if n > 0 && !lg.Ops[0].Equal(l.Ops[0]) {
+		n = 0
+	}
+	for i := 0; i < n; i++ {
+		if !lg.Ops[i].Equal(l.Ops[i]) {
+			return fmt.Errorf("%w: log %s differs at operation %d", ErrDivergentLog, lg.ID(), i)
+		}
+	}
+
+	for _, x := range l.Logs {
+		for _, y := range lg.Logs {
+			if x.Ops[0].Equal(y.Ops[0]) {
+				if err := y.checkDivergence(x); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (lg *Log) merge(l *Log) {
 	// if the incoming log has more operations, use it & clear the cache
 	if len(l.Ops) > len(lg.Ops) {
 		lg.Ops = l.Ops
@@ -638,7 +696,7 @@ LOOP:
 		for j, y := range lg.Logs {
 			// if logs match. merge 'em
 			if x.Ops[0].Equal(y.Ops[0]) {
-				lg.Logs[j].Merge(x)
+				lg.Logs[j].merge(x)
 				continue LOOP
 			}
 		}
@@ -647,16 +705,38 @@ LOOP:
 	}
 }
 
-// Verify confirms that the signature for a log matches
+// Verify confirms that the signature for a log matches a public key. Logs
+// that rotate keys must have a valid chain of rotations, and also accept
+// signatures from keys that succeed pub in that chain, so an author known by
+// an earlier key can sign with the key valid at the head of the log
 func (lg Log) Verify(pub crypto.PubKey) error {
-	ok, err := pub.Verify(lg.SigningBytes(), lg.Signature)
+	root := &lg
+	for root.parent != nil {
+		root = root.parent
+	}
+	chain, err := root.KeyChain()
 	if err != nil {
 		return err
 	}
-	if !ok {
-		return fmt.Errorf("invalid signature")
+
+	signers := []crypto.PubKey{pub}
+	for i, key := range chain {
+		if key.Equals(pub) {
+			signers = append(signers, chain[i+1:]...)
+			break
+		}
 	}
-	return nil
+	err = fmt.Errorf("invalid signature")
+	for _, key := range signers {
+		ok, verr := key.Verify(lg.SigningBytes(), lg.Signature)
+		if ok && verr == nil {
+			return nil
+		}
+		if verr != nil {
+			err = verr
+		}
+	}
+	return err
 }
 
 // Sign assigns the log signature by signing the logging checksum with a given
@@ -672,6 +752,119 @@ func (lg *Log) Sign(pk crypto.PrivKey) (err error) {
 	return nil
 }
 
+// KeyRotationNote annotates operations that rotate the key an author signs
+// with
+const KeyRotationNote = "rotate key"
+
+// NewKeyRotationOp creates an amend operation that replaces the key signing a
+// log with next. The operation carries both public keys and a signature of the
+// next key by the outgoing one, chaining each key to the key before it
+func NewKeyRotationOp(model uint32, name string, prev crypto.PrivKey, next crypto.PubKey, timestamp int64) (Op, error) {
+	prevID, err := identity.KeyIDFromPriv(prev)
+	if err != nil {
+		return Op{}, err
+	}
+	nextID, err := identity.KeyIDFromPub(next)
+	if err != nil {
+		return Op{}, err
+	}
+	prevBytes, err := prev.GetPublic().Bytes()
+	if err != nil {
+		return Op{}, err
+	}
+	nextBytes, err := next.Bytes()
+	if err != nil {
+		return Op{}, err
+	}
+	sig, err := prev.Sign(nextBytes)
+	if err != nil {
+		return Op{}, err
+	}
+
+	return Op{
+		Type:     OpTypeAmend,
+		Model:    model,
+		Name:     name,
+		AuthorID: nextID,
+		Prev:     prevID,
+		Relations: []string{
+			base64.StdEncoding.EncodeToString(prevBytes),
+			base64.StdEncoding.EncodeToString(nextBytes),
+			base64.StdEncoding.EncodeToString(sig),
+		},
+		Timestamp: timestamp,
+		Note:      KeyRotationNote,
+	}, nil
+}
+
+// IsKeyRotation returns true if an operation rotates keys
+func (o Op) IsKeyRotation() bool {
+	return o.Type == OpTypeAmend && o.Note == KeyRotationNote
+}
+
+// rotationKeys decodes the outgoing & incoming keys of a key rotation,
+// confirming the outgoing key signed the incoming one
+func (o Op) rotationKeys() (prev, next crypto.PubKey, err error) {
+	if len(o.Relations) != 3 {
+		return nil, nil, fmt.Errorf("%w: expected 3 relations, got %d", ErrInvalidKeyRotation, len(o.Relations))
+	}
+	var data [3][]byte
+	for i, rel := range o.Relations {
+		if data[i], err = base64.StdEncoding.DecodeString(rel); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKeyRotation, err)
+		}
+	}
+	if prev, err = crypto.UnmarshalPublicKey(data[0]); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKeyRotation, err)
+	}
+	if next, err = crypto.UnmarshalPublicKey(data[1]); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKeyRotation, err)
+	}
+	if ok, err := prev.Verify(data[1], data[2]); err != nil || !ok {
+		return nil, nil, fmt.Errorf("%w: next key isn't signed by the previous key", ErrInvalidKeyRotation)
+	}
+	return prev, next, nil
+}
+
+// KeyChain lists the keys a log has been signed with in order of use, from
+// the key that initialized the log to the key valid at the head of the log.
+// Each key rotation must be signed by the key it replaces. Logs that have
+// never rotated keys return an empty chain
+func (lg Log) KeyChain() ([]crypto.PubKey, error) {
+	if len(lg.Ops) == 0 {
+		return nil, nil
+	}
+	var chain []crypto.PubKey
+	keyID := lg.FirstOpAuthorID()
+	m := lg.Model()
+	for i, op := range lg.Ops {
+		if op.Model != m || !op.IsKeyRotation() {
+			continue
+		}
+		prev, next, err := op.rotationKeys()
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		prevID, err := identity.KeyIDFromPub(prev)
+		if err != nil {
+			return nil, err
+		}
+		nextID, err := identity.KeyIDFromPub(next)
+		if err != nil {
+			return nil, err
+		}
+		if prevID != keyID || op.Prev != keyID || op.AuthorID != nextID {
+			return nil, fmt.Errorf("%w: operation %d doesn't rotate from key %s", ErrInvalidKeyRotation, i, keyID)
+		}
+		if len(chain) == 0 {
+			chain = append(chain, prev)
+		}
+		chain = append(chain, next)
+		keyID = nextID
+	}
+	return chain, nil
+}
+
 // SigningBytes perpares a byte slice for signing from a log's operations
 func (lg Log) SigningBytes() []byte {
 	hasher := md5.New()
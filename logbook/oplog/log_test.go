@@ -3,6 +3,7 @@ package oplog
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"errors"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	crypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/qri-io/qri/identity"
 	"github.com/qri-io/qri/logbook/oplog/logfb"
 )
 
@@ -208,6 +210,91 @@ func TestJournalSignLog(t *testing.T) {
 	}
 }
 
+func TestLogKeyRotation(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	first := tr.PrivKey
+	second, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	third, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstID, err := identity.KeyIDFromPriv(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lg := InitLog(Op{Type: OpTypeInit, Model: 0x1, AuthorID: firstID, Name: "author"})
+	if chain, err := lg.KeyChain(); err != nil || len(chain) != 0 {
+		t.Fatalf("expected log without rotations to have an empty key chain. got: %v, %v", chain, err)
+	}
+
+	for _, keys := range [][2]crypto.PrivKey{{first, second}, {second, third}} {
+		op, err := NewKeyRotationOp(0x1, "author", keys[0], keys[1].GetPublic(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lg.Append(op)
+	}
+	chain, err := lg.KeyChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 || !chain[2].Equals(third.GetPublic()) {
+		t.Fatalf("expected a chain of 3 keys ending with the latest key, got %d keys", len(chain))
+	}
+	// rotating keys doesn't change the author, including after a flatbuffer
+	// round trip
+	if lg.Author() != firstID {
+		t.Errorf("expected log author to stay %q after key rotation, got %q", firstID, lg.Author())
+	}
+	received, err := FromFlatbufferBytes(lg.FlatbufferBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Author() != firstID {
+		t.Errorf("expected flatbuffer log author to stay %q after key rotation, got %q", firstID, received.Author())
+	}
+	if chain, err := received.KeyChain(); err != nil || len(chain) != 3 {
+		t.Errorf("expected flatbuffer log to keep a 3 key chain. got: %d keys, %v", len(chain), err)
+	}
+
+	// a log signed with the latest key verifies for senders known by any key in
+	// the chain
+	if err := lg.Sign(third); err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range []crypto.PrivKey{first, second, third} {
+		if err := lg.Verify(key.GetPublic()); err != nil {
+			t.Errorf("key %d: expected signature by a later key to verify. got: %s", i, err)
+		}
+	}
+	// signatures by earlier keys don't verify for later keys
+	if err := lg.Sign(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := lg.Verify(third.GetPublic()); err == nil {
+		t.Error("expected signature by a replaced key not to verify for the latest key")
+	}
+
+	// rotations must be signed by the key they replace
+	forged, err := NewKeyRotationOp(0x1, "author", second, second.GetPublic(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Append(forged)
+	if _, err := lg.KeyChain(); !errors.Is(err, ErrInvalidKeyRotation) {
+		t.Errorf("expected rotation by a replaced key to fail with ErrInvalidKeyRotation. got: %v", err)
+	}
+	if err := lg.Verify(first.GetPublic()); !errors.Is(err, ErrInvalidKeyRotation) {
+		t.Errorf("expected verifying a log with an invalid rotation to fail. got: %v", err)
+	}
+}
+
 func TestLogHead(t *testing.T) {
 	l := &Log{}
 	if !l.Head().Equal(Op{}) {
@@ -343,7 +430,9 @@ func TestLogMerge(t *testing.T) {
 		},
 	}
 
-	left.Merge(right)
+	if err := left.Merge(right); err != nil {
+		t.Fatal(err)
+	}
 
 	expect := &Log{
 		Ops: []Op{
@@ -392,6 +481,41 @@ func TestLogMerge(t *testing.T) {
 	}
 }
 
+func TestLogMergeDivergent(t *testing.T) {
+	root := Op{Type: OpTypeInit, Model: 0x1, AuthorID: "author", Name: "root"}
+	left := &Log{
+		Ops: []Op{root, {Type: OpTypeInit, Model: 0x2, Ref: "a"}},
+		Logs: []*Log{
+			{Ops: []Op{{Type: OpTypeInit, Model: 0x3, Name: "child"}, {Type: OpTypeInit, Model: 0x4, Ref: "b"}}},
+		},
+	}
+
+	right := left.DeepCopy()
+	right.Ops[1].Ref = "not_a"
+	right.Ops = append(right.Ops, Op{Type: OpTypeInit, Model: 0x2, Ref: "c"})
+	if err := left.Merge(right); !errors.Is(err, ErrDivergentLog) {
+		t.Errorf("expected merging a divergent log to fail with %q, got: %v", ErrDivergentLog, err)
+	}
+	if left.Ops[1].Ref != "a" {
+		t.Errorf("expected failed merge to leave the log unchanged")
+	}
+
+	right = left.DeepCopy()
+	right.Logs[0].Ops[1].Ref = "not_b"
+	if err := left.Merge(right); !errors.Is(err, ErrDivergentLog) {
+		t.Errorf("expected merging a log with a divergent child to fail with %q, got: %v", ErrDivergentLog, err)
+	}
+
+	right = left.DeepCopy()
+	right.Logs[0].Ops = append(right.Logs[0].Ops, Op{Type: OpTypeRemove, Model: 0x4, Size: 1})
+	if err := left.Merge(right); err != nil {
+		t.Fatal(err)
+	}
+	if len(left.Logs[0].Ops) != 3 {
+		t.Errorf("expected appended operations to merge, got %d ops", len(left.Logs[0].Ops))
+	}
+}
+
 func TestHeadRefRemoveTracking(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
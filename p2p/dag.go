@@ -7,6 +7,7 @@ import (
 	"github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
 	"github.com/qri-io/dag"
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base/dsfs"
 )
@@ -124,6 +125,106 @@ func newDAGInfo(ctx context.Context, fs qfs.Filesystem, ng ipld.NodeGetter, path
 	return info, nil
 }
 
+// DedupInfo describes how much of a dataset version is stored in blocks it
+// shares with the previous version
+type DedupInfo struct {
+	// PrevPath is the path of the previous version, empty if there is none
+	PrevPath string
+	// Blocks is the number of blocks that make up the version
+	Blocks int
+	// SharedBlocks is the number of blocks also in the previous version
+	SharedBlocks int
+	// Size of all blocks in bytes
+	Size uint64
+	// SharedSize is the size of shared blocks in bytes
+	SharedSize uint64
+}
+
+// Ratio returns the fraction of bytes shared with the previous version
+func (d *DedupInfo) Ratio() float64 {
+	if d.Size == 0 {
+		return 0
+	}
+	return float64(d.SharedSize) / float64(d.Size)
+}
+
+// NewDedupInfo compares the blocks of a dataset version with the blocks of
+// its previous version. blocks of stored body partitions or chunks are
+// included, even though they aren't linked from the dataset
+func (node *QriNode) NewDedupInfo(ctx context.Context, path string) (*DedupInfo, error) {
+	ng, err := newNodeGetter(node)
+	if err != nil {
+		return nil, err
+	}
+	fs := node.Repo.Filesystem()
+
+	ds, blocks, err := versionBlocks(ctx, fs, ng, path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &DedupInfo{}
+	prevBlocks := map[string]uint64{}
+	if ds.PreviousPath != "" && ds.PreviousPath != "/" {
+		info.PrevPath = ds.PreviousPath
+		if _, prevBlocks, err = versionBlocks(ctx, fs, ng, ds.PreviousPath); err != nil {
+			return nil, fmt.Errorf("reading previous version: %w", err)
+		}
+	}
+
+	for id, size := range blocks {
+		info.Blocks++
+		info.Size += size
+		if _, ok := prevBlocks[id]; ok {
+			info.SharedBlocks++
+			info.SharedSize += size
+		}
+	}
+	return info, nil
+}
+
+// versionBlocks loads a dataset version, collecting the sizes of all blocks
+// that make up the version, keyed by CID
+func versionBlocks(ctx context.Context, fs qfs.Filesystem, ng ipld.NodeGetter, path string) (*dataset.Dataset, map[string]uint64, error) {
+	ds, err := dsfs.LoadDataset(ctx, fs, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	bodyPaths, err := dsfs.BodyBlockPaths(ctx, fs, ds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks := map[string]uint64{}
+	for _, p := range append([]string{path}, bodyPaths...) {
+		id, err := cid.Parse(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := addBlockSizes(ctx, ng, id, blocks); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ds, blocks, nil
+}
+
+func addBlockSizes(ctx context.Context, ng ipld.NodeGetter, id cid.Cid, blocks map[string]uint64) error {
+	if _, ok := blocks[id.String()]; ok {
+		return nil
+	}
+	nd, err := ng.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	blocks[id.String()] = uint64(len(nd.RawData()))
+	for _, l := range nd.Links() {
+		if err := addBlockSizes(ctx, ng, l.Cid, blocks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // newNodeGetter generates an ipld.NodeGetter from a QriNode
 func newNodeGetter(node *QriNode) (ipld.NodeGetter, error) {
 	capi, err := node.IPFSCoreAPI()
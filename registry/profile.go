@@ -29,6 +29,11 @@ type Profile struct {
 	ProfileID string `json:"profileid"`
 	PublicKey string `json:"publickey"`
 	Signature string `json:"signature"`
+
+	// PrevPublicKey & RotationSignature prove a key rotation, the previous key
+	// signs the new public key
+	PrevPublicKey     string `json:"prevpublickey,omitempty"`
+	RotationSignature string `json:"rotationsignature,omitempty"`
 }
 
 // Validate is a sanity check that all required values are present
@@ -55,6 +60,19 @@ func (p *Profile) Verify() error {
 	return verify(p.PublicKey, p.Signature, []byte(p.Username))
 }
 
+// VerifyKeyRotation checks a profile's proof that the previous public key
+// authorized replacing it with the profile's public key
+func (p *Profile) VerifyKeyRotation() error {
+	if p.PrevPublicKey == "" || p.RotationSignature == "" {
+		return fmt.Errorf("prevpublickey and rotationsignature are required to rotate keys")
+	}
+	next, err := base64.StdEncoding.DecodeString(p.PublicKey)
+	if err != nil {
+		return fmt.Errorf("publickey base64 encoding: %s", err.Error())
+	}
+	return verify(p.PrevPublicKey, p.RotationSignature, next)
+}
+
 // ProfileFromPrivateKey generates a profile struct from a private key & desired profile handle
 // It adds all the necessary components to pass profiles.Register, creating base64-encoded
 // PublicKey & Signature, and base58-encoded ProfileID
@@ -82,3 +100,34 @@ func ProfileFromPrivateKey(p *Profile, privKey crypto.PrivKey) (*Profile, error)
 
 	return p, nil
 }
+
+// ProfileKeyRotation prepares a profile to replace the key of an existing
+// registry profile with next, proving control of both keys. The profile keeps
+// its ProfileID, which must be set
+func ProfileKeyRotation(p *Profile, prev, next crypto.PrivKey) (*Profile, error) {
+	if p.ProfileID == "" {
+		return nil, fmt.Errorf("profileID is required")
+	}
+	profileID := p.ProfileID
+	p, err := ProfileFromPrivateKey(p, next)
+	if err != nil {
+		return nil, err
+	}
+	p.ProfileID = profileID
+
+	prevBytes, err := prev.GetPublic().Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("error getting pubkey bytes: %s", err.Error())
+	}
+	nextBytes, err := next.GetPublic().Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("error getting pubkey bytes: %s", err.Error())
+	}
+	sigbytes, err := prev.Sign(nextBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error signing %s", err.Error())
+	}
+	p.PrevPublicKey = base64.StdEncoding.EncodeToString(prevBytes)
+	p.RotationSignature = base64.StdEncoding.EncodeToString(sigbytes)
+	return p, nil
+}
@@ -90,6 +90,37 @@ func UpdateProfile(store Profiles, p *Profile) (err error) {
 	return store.Update(p.Username, p)
 }
 
+// RotateProfileKey replaces the public key of a registered profile. The
+// profile must be signed by the new key, and prove the registered key
+// authorized the rotation
+func RotateProfileKey(store Profiles, p *Profile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := p.Verify(); err != nil {
+		return err
+	}
+	if err := p.VerifyKeyRotation(); err != nil {
+		return err
+	}
+
+	pro, err := store.Load(p.Username)
+	if err != nil {
+		return err
+	}
+	if pro.ProfileID != p.ProfileID {
+		return fmt.Errorf("username '%s' is taken", p.Username)
+	}
+	if pro.PublicKey != p.PrevPublicKey {
+		return fmt.Errorf("previous public key doesn't match the registered key")
+	}
+
+	rotated := *pro
+	rotated.PublicKey = p.PublicKey
+	rotated.Signature = p.Signature
+	return store.Update(p.Username, &rotated)
+}
+
 // DeregisterProfile removes a profile from the registry if it exists
 // confirming the user has the authority to do so
 func DeregisterProfile(store Profiles, p *Profile) error {
@@ -82,6 +82,73 @@ func TestRegisterProfile(t *testing.T) {
 	}
 }
 
+func TestRotateProfileKey(t *testing.T) {
+	ps := NewMemProfiles()
+
+	src := rand.New(rand.NewSource(0))
+	key0, _, err := crypto.GenerateEd25519Key(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, _, err := crypto.GenerateEd25519Key(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _, err := crypto.GenerateEd25519Key(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ProfileFromPrivateKey(&Profile{Username: "rotator"}, key0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterProfile(ps, p); err != nil {
+		t.Fatal(err)
+	}
+	profileID := p.ProfileID
+
+	// rotations must be signed by the registered key
+	forged, err := ProfileKeyRotation(&Profile{Username: "rotator", ProfileID: profileID}, key1, key2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RotateProfileKey(ps, forged); err == nil {
+		t.Error("expected rotation from an unregistered key to fail")
+	}
+	// rotations must prove the new key
+	unproven, err := ProfileKeyRotation(&Profile{Username: "rotator", ProfileID: profileID}, key0, key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unproven.RotationSignature = p.Signature
+	if err := RotateProfileKey(ps, unproven); err == nil {
+		t.Error("expected rotation with an invalid rotation signature to fail")
+	}
+
+	rotated, err := ProfileKeyRotation(&Profile{Username: "rotator", ProfileID: profileID}, key0, key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RotateProfileKey(ps, rotated); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ps.Load("rotator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ProfileID != profileID {
+		t.Errorf("expected rotation to keep profileID %q, got %q", profileID, got.ProfileID)
+	}
+	if got.PublicKey != rotated.PublicKey {
+		t.Error("expected registered public key to be the new key")
+	}
+	if err := got.Verify(); err != nil {
+		t.Errorf("expected rotated profile to verify: %s", err)
+	}
+}
+
 func TestProfilesSortedRange(t *testing.T) {
 	ps := NewMemProfiles()
 
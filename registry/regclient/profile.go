@@ -68,6 +68,21 @@ func (c *Client) PutProfile(p *registry.Profile, privKey crypto.PrivKey) (*regis
 	return c.doJSONProfileReq("POST", p)
 }
 
+// RotateProfileKey replaces the key of a registry profile, signing the new key
+// with the previous one
+func (c *Client) RotateProfileKey(p *registry.Profile, prev, next crypto.PrivKey) (*registry.Profile, error) {
+	if c == nil {
+		return nil, registry.ErrNoRegistry
+	}
+
+	p, err := registry.ProfileKeyRotation(p, prev, next)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doJSONProfileReq("PUT", p)
+}
+
 // DeleteProfile removes a profile from the registry
 func (c *Client) DeleteProfile(p *registry.Profile, privKey crypto.PrivKey) error {
 	if c == nil {
@@ -1,9 +1,11 @@
 package regclient
 
 import (
+	"crypto/rand"
 	"encoding/base64"
 	"testing"
 
+	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/qri-io/qri/registry"
 )
 
@@ -50,3 +52,42 @@ func TestProfileRequests(t *testing.T) {
 		t.Error(err.Error())
 	}
 }
+
+func TestRotateProfileKey(t *testing.T) {
+	tr, cleanup := NewTestRunner(t)
+	defer cleanup()
+
+	client := tr.Client
+	pro, err := client.PutProfile(&registry.Profile{Username: "b5"}, tr.ClientPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := client.RotateProfileKey(&registry.Profile{Username: "b5", ProfileID: pro.ProfileID}, tr.ClientPrivKey, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.ProfileID != pro.ProfileID {
+		t.Errorf("expected rotation to keep profileID %q, got %q", pro.ProfileID, rotated.ProfileID)
+	}
+
+	pubBytes, err := next.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &registry.Profile{PublicKey: base64.StdEncoding.EncodeToString(pubBytes)}
+	if err := client.GetProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Username != "b5" {
+		t.Errorf("expected profile to be found by the new key, got username %q", p.Username)
+	}
+
+	if _, err := client.RotateProfileKey(&registry.Profile{Username: "b5", ProfileID: pro.ProfileID}, tr.ClientPrivKey, next); err == nil {
+		t.Error("expected rotating from a replaced key to fail")
+	}
+}
@@ -101,7 +101,11 @@ func NewProfileHandler(profiles registry.Profiles) http.HandlerFunc {
 				return
 			}
 		case "PUT":
-			if err := registry.UpdateProfile(profiles, p); err != nil {
+			update := registry.UpdateProfile
+			if p.PrevPublicKey != "" {
+				update = registry.RotateProfileKey
+			}
+			if err := update(profiles, p); err != nil {
 				apiutil.WriteErrResponse(w, http.StatusBadRequest, err)
 				return
 			}
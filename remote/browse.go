@@ -2,6 +2,7 @@ package remote
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/qri-io/dataset"
@@ -77,6 +78,9 @@ func (rf RepoFeeds) Feed(ctx context.Context, userID, name string, offset, limit
 type Previews interface {
 	Preview(ctx context.Context, userID, refStr string) (*dataset.Dataset, error)
 	PreviewComponent(ctx context.Context, userID, refStr, component string) (interface{}, error)
+	// PreviewBody reads a bounded page of body entries as JSON, keeping only the
+	// named columns if any are given
+	PreviewBody(ctx context.Context, userID, refStr string, offset, limit int, columns []string) (json.RawMessage, error)
 }
 
 // LocalPreviews implements the previews interface with a local repo
@@ -102,6 +106,20 @@ func (rp LocalPreviews) Preview(ctx context.Context, _, refStr string) (*dataset
 	return base.CreatePreview(ctx, rp.fs, ref)
 }
 
+// PreviewBody reads a page of body entries for a reference
+func (rp LocalPreviews) PreviewBody(ctx context.Context, _, refStr string, offset, limit int, columns []string) (json.RawMessage, error) {
+	ref, err := dsref.Parse(refStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := rp.localResolver.ResolveRef(ctx, &ref); err != nil {
+		return nil, err
+	}
+
+	return base.ReadBodyPage(ctx, rp.fs, ref, offset, limit, columns)
+}
+
 // PreviewComponent gets a component for a reference & component name
 func (rp LocalPreviews) PreviewComponent(ctx context.Context, _, refStr, component string) (interface{}, error) {
 	return nil, fmt.Errorf("not finished")
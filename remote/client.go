@@ -8,13 +8,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/cheggaaa/pb/v3"
 	coreiface "github.com/ipfs/interface-go-ipfs-core"
-	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"github.com/qri-io/dag/dsync"
 	"github.com/qri-io/dataset"
@@ -50,6 +50,10 @@ type Client interface {
 	// Preview fetches a size-bounded subset of a single dataset version,
 	// summarizing the contents of the dataset version
 	PreviewDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) (*dataset.Dataset, error)
+	// PreviewDatasetBody fetches a page of body entries of a single dataset
+	// version as JSON without pulling the version. columns optionally limits
+	// the page to the named columns of a tabular body
+	PreviewDatasetBody(ctx context.Context, ref dsref.Ref, remoteAddr string, offset, limit int, columns []string) (json.RawMessage, error)
 	// FetchLogs downloads logbook data on a dataset without storing the results
 	// locally
 	FetchLogs(ctx context.Context, ref dsref.Ref, remoteAddr string) (*oplog.Log, error)
@@ -63,6 +67,13 @@ type Client interface {
 	// PullDataset fetches & stores a dataset from a remote, synchronizing logbook
 	// data and pulling the dataset version data associated with ref.Path
 	PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (*dataset.Dataset, error)
+	// PullDatasetComponents works like PullDataset, but only fetches the files
+	// of the named components. Definitions of all components are always pulled,
+	// leaving a version that can be listed & inspected, but is missing data
+	PullDatasetComponents(ctx context.Context, ref *dsref.Ref, remoteAddr string, components []string) (*dataset.Dataset, error)
+	// PullDatasetVersion fetches & pins the data of the dataset version at
+	// ref.Path from a remote, leaving logbook data and stored references as-is
+	PullDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) error
 	// RemoveDataset removes a dataset from a remote entirely, delete logbook data
 	// on the remote and requesting the remote drop all stored dataset versions
 	RemoveDataset(ctx context.Context, ref dsref.Ref, remoteAddr string) error
@@ -82,7 +93,6 @@ type Client interface {
 // client talks to a remote in order to sync peer data
 type client struct {
 	profile *profile.Profile
-	pk      crypto.PrivKey
 	ds      *dsync.Dsync
 	logsync *logsync.Logsync
 	capi    coreiface.CoreAPI
@@ -136,7 +146,6 @@ func NewClient(ctx context.Context, node *p2p.QriNode, pub event.Publisher) (c C
 	}
 
 	cli := &client{
-		pk:      node.Repo.PrivateKey(),
 		profile: pro,
 		ds:      ds,
 		logsync: ls,
@@ -306,6 +315,62 @@ func (c *client) previewDatasetVersionHTTP(ctx context.Context, ref dsref.Ref, r
 	return env.Data, nil
 }
 
+// PreviewDatasetBody fetches a page of body data from a remote
+func (c *client) PreviewDatasetBody(ctx context.Context, ref dsref.Ref, remoteAddr string, offset, limit int, columns []string) (json.RawMessage, error) {
+	log.Debugf("client.PreviewDatasetBody ref=%q remoteAddr=%q offset=%d limit=%d", ref, remoteAddr, offset, limit)
+	if c == nil {
+		return nil, ErrNoRemoteClient
+	}
+	if at := addressType(remoteAddr); at != "http" {
+		return nil, fmt.Errorf("body pages are only supported over HTTP")
+	}
+
+	q := url.Values{}
+	q.Set("offset", strconv.Itoa(offset))
+	q.Set("limit", strconv.Itoa(limit))
+	if len(columns) > 0 {
+		q.Set("columns", strings.Join(columns, ","))
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/remote/dataset/body/%s?%s", remoteAddr, ref.String(), q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.signHTTPRequest(req); err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such host") {
+			return nil, ErrRemoteNotFound
+		}
+		log.Errorf("fetching body page from %q: %s", remoteAddr, err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// add response to an envelope
+	env := struct {
+		Data json.RawMessage
+		Meta struct {
+			Error  string
+			Status string
+			Code   int
+		}
+	}{}
+
+	if err := json.NewDecoder(res.Body).Decode(&env); err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error %d: %s", res.StatusCode, env.Meta.Error)
+	}
+
+	return env.Data, nil
+}
+
 // NewRemoteRefResolver creates a resolver backed by a remote
 func (c *client) NewRemoteRefResolver(remoteAddr string) dsref.Resolver {
 	log.Debugf("client.NewRemoteRefResolver remoteAddr=%q", remoteAddr)
@@ -439,12 +504,18 @@ func (c *client) pushDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAd
 		return err
 	}
 
-	params, err := sigParams(c.pk, c.profile.Peername, ref)
+	params, err := sigParams(c.node.Repo.PrivateKey(), c.profile.Peername, ref)
 	if err != nil {
 		return err
 	}
 	push.SetMeta(params)
 
+	// send body blocks first, so the body is complete when the remote adds the
+	// dataset version
+	if err := c.pushBodyBlocks(ctx, ref, remoteAddr, params); err != nil {
+		return err
+	}
+
 	go func() {
 		updates := push.Updates()
 		for {
@@ -476,12 +547,104 @@ func (c *client) pushDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAd
 	})
 }
 
+// bodyBlockMetaKey marks dsync requests that transfer the stored partitions
+// or chunks a dataset body is read from, instead of a dataset version
+const bodyBlockMetaKey = "body_block"
+
+func bodyBlockMeta(params map[string]string) map[string]string {
+	meta := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		meta[k] = v
+	}
+	meta[bodyBlockMetaKey] = "true"
+	return meta
+}
+
+// pushBodyBlocks pushes the stored partitions or chunks of a dataset body,
+// which aren't linked from the dataset itself. remotes only ask for blocks
+// they don't have, so chunks shared with versions already on the remote
+// aren't sent again
+func (c *client) pushBodyBlocks(ctx context.Context, ref dsref.Ref, remoteAddr string, params map[string]string) error {
+	fs := c.node.Repo.Filesystem()
+	ds, err := dsfs.LoadDataset(ctx, fs, ref.Path)
+	if err != nil {
+		return err
+	}
+	paths, err := dsfs.BodyBlockPaths(ctx, fs, ds)
+	if err != nil {
+		return err
+	}
+
+	meta := bodyBlockMeta(params)
+	for _, path := range paths {
+		log.Debugf("pushing body block path=%q", path)
+		push, err := c.ds.NewPush(path, remoteAddr, true)
+		if err != nil {
+			return err
+		}
+		push.SetMeta(meta)
+		if err := push.Do(ctx); err != nil {
+			return fmt.Errorf("pushing body block %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// pullBodyBlocks fetches & pins the stored partitions or chunks of a pulled
+// dataset body, only transferring blocks that aren't already stored locally
+func (c *client) pullBodyBlocks(ctx context.Context, ref dsref.Ref, remoteAddr string, params map[string]string) error {
+	fs := c.node.Repo.Filesystem()
+	ds, err := dsfs.LoadDataset(ctx, fs, ref.Path)
+	if err != nil {
+		return err
+	}
+	paths, err := dsfs.BodyBlockPaths(ctx, fs, ds)
+	if err != nil {
+		return err
+	}
+
+	meta := bodyBlockMeta(params)
+	pinner, _ := fs.Filesystem("ipfs").(qfs.PinningFS)
+	for _, path := range paths {
+		log.Debugf("pulling body block path=%q", path)
+		pull, err := c.ds.NewPull(path, remoteAddr, meta)
+		if err != nil {
+			return err
+		}
+		if err := pull.Do(ctx); err != nil {
+			return fmt.Errorf("pulling body block %q: %w", path, err)
+		}
+		if pinner != nil {
+			if err := pinner.Pin(ctx, path, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // PullDataset fetches & pins a dataset to the store, adding it to the list of
 // stored refs
 // TODO (b5) - remove all p2p calls from here into the p2p subsystem in a future
 // refactor
 func (c *client) PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (ds *dataset.Dataset, err error) {
 	log.Debugf("client.PullDataset ref=%q addr=%q", ref, remoteAddr)
+	return c.pullDataset(ctx, ref, remoteAddr, nil)
+}
+
+// PullDatasetComponents fetches & pins the named components of a dataset
+// version, adding the dataset to the list of stored refs
+func (c *client) PullDatasetComponents(ctx context.Context, ref *dsref.Ref, remoteAddr string, components []string) (*dataset.Dataset, error) {
+	log.Debugf("client.PullDatasetComponents ref=%q addr=%q components=%v", ref, remoteAddr, components)
+	if err := ValidateComponents(components); err != nil {
+		return nil, err
+	}
+	return c.pullDataset(ctx, ref, remoteAddr, components)
+}
+
+// pullDataset pulls logs & version data for a dataset. a nil list of
+// components pulls the complete version
+func (c *client) pullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string, components []string) (ds *dataset.Dataset, err error) {
 	if c == nil {
 		return nil, ErrNoRemoteClient
 	}
@@ -493,8 +656,13 @@ func (c *client) PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr str
 		return nil, err
 	}
 
-	if err := c.pullDatasetVersion(ctx, ref, remoteAddr); err != nil {
-		log.Debugf("client.pullDatasetVersion error=%q", err)
+	if components == nil {
+		if err := c.pullDatasetVersion(ctx, ref, remoteAddr); err != nil {
+			log.Debugf("client.pullDatasetVersion error=%q", err)
+			return nil, err
+		}
+	} else if err := c.pullDatasetComponents(ctx, ref, remoteAddr, components); err != nil {
+		log.Debugf("client.pullDatasetComponents error=%q", err)
 		return nil, err
 	}
 	node.LocalStreams.PrintErr(fmt.Sprintf("🗼 fetched from remote %q\n", remoteAddr))
@@ -544,6 +712,21 @@ func (c *client) PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr str
 	return ds, nil
 }
 
+// PullDatasetVersion fetches & pins the data of a single dataset version
+func (c *client) PullDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
+	log.Debugf("client.PullDatasetVersion ref=%q addr=%q", ref, remoteAddr)
+	if c == nil {
+		return ErrNoRemoteClient
+	}
+	if ref.Path == "" {
+		return fmt.Errorf("pulling a dataset version requires a path")
+	}
+	// logsync records http remote addresses with the logsync endpoint appended,
+	// accept addresses read from logbook push operations
+	remoteAddr = strings.TrimSuffix(remoteAddr, "/remote/logsync")
+	return c.pullDatasetVersion(ctx, &ref, remoteAddr)
+}
+
 // pullLogs fetches logbook data from a remote & stores it locally
 func (c *client) pullLogs(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
 	log.Debugf("client.pullLogs ref=%q remoteAddr=%q", ref, remoteAddr)
@@ -572,7 +755,7 @@ func (c *client) pullDatasetVersion(ctx context.Context, ref *dsref.Ref, remoteA
 		}
 	}
 
-	params, err := sigParams(c.pk, c.profile.Peername, *ref)
+	params, err := sigParams(c.node.Repo.PrivateKey(), c.profile.Peername, *ref)
 	if err != nil {
 		log.Debugf("generating sig params error=%q ", err)
 		return err
@@ -620,6 +803,10 @@ func (c *client) pullDatasetVersion(ctx context.Context, ref *dsref.Ref, remoteA
 		}
 	}
 
+	if err := c.pullBodyBlocks(ctx, *ref, remoteAddr, params); err != nil {
+		return err
+	}
+
 	return c.events.Publish(ctx, event.ETRemoteClientPullVersionCompleted, event.RemoteEvent{
 		Ref:        *ref,
 		RemoteAddr: remoteAddr,
@@ -661,7 +848,7 @@ func (c *client) RemoveDatasetVersion(ctx context.Context, ref dsref.Ref, remote
 		return ErrNoRemoteClient
 	}
 
-	params, err := sigParams(c.pk, c.profile.Peername, ref)
+	params, err := sigParams(c.node.Repo.PrivateKey(), c.profile.Peername, ref)
 	if err != nil {
 		return err
 	}
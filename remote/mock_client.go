@@ -2,6 +2,7 @@ package remote
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/qri-io/dataset"
@@ -77,6 +78,11 @@ func (c *MockClient) Feed(ctx context.Context, remoteAddr, feedName string, page
 	return nil, ErrNotImplemented
 }
 
+// PreviewDatasetBody is not implemented
+func (c *MockClient) PreviewDatasetBody(ctx context.Context, ref dsref.Ref, remoteAddr string, offset, limit int, columns []string) (json.RawMessage, error) {
+	return nil, ErrNotImplemented
+}
+
 // PreviewDatasetVersion is not implemented
 func (c *MockClient) PreviewDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) (*dataset.Dataset, error) {
 	return nil, ErrNotImplemented
@@ -286,13 +292,18 @@ func (c *MockClient) mockDagSync(ctx context.Context, ref dsref.Ref) (*dsref.Ver
 	return &vi, nil
 }
 
+// PullDatasetComponents is not implemented
+func (c *MockClient) PullDatasetComponents(ctx context.Context, ref *dsref.Ref, remoteAddr string, components []string) (*dataset.Dataset, error) {
+	return nil, ErrNotImplemented
+}
+
 // PushLogs is not implemented
 func (c *MockClient) PushLogs(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
 	return ErrNotImplemented
 }
 
 // PullDatasetVersion is not implemented
-func (c *MockClient) PullDatasetVersion(ctx context.Context, ref *dsref.Ref, remoteAddr string) error {
+func (c *MockClient) PullDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
 	return ErrNotImplemented
 }
 
@@ -0,0 +1,160 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qri-io/dag"
+)
+
+// defaultPushSessionTimeout is how long body blocks received ahead of a
+// version wait for the version to arrive when the remote doesn't configure an
+// accept timeout
+var defaultPushSessionTimeout = 10 * time.Minute
+
+// pushSession tracks the body blocks received ahead of the version they
+// belong to. Blocks and the version are sent as separate dsync pushes that
+// share the same signed request parameters
+type pushSession struct {
+	// combined size of all blocks received in the session
+	size uint64
+	// blocks the session stored. blocks the remote already had aren't listed,
+	// so they're never removed when the session fails
+	blocks []string
+	// last time a block was received
+	updated time.Time
+}
+
+// pushSessionID identifies the push session a dsync request belongs to
+func pushSessionID(meta map[string]string) string {
+	return fmt.Sprintf("%s%s@%s", meta["pid"], meta["path"], meta["timestamp"])
+}
+
+func (r *Remote) pushSessionTimeout() time.Duration {
+	if r.acceptTimeoutMs > 0 {
+		return r.acceptTimeoutMs * time.Millisecond
+	}
+	return defaultPushSessionTimeout
+}
+
+// receivedBlockSize returns the size of body blocks received in a session
+func (r *Remote) receivedBlockSize(id string) uint64 {
+	r.sessionsLk.Lock()
+	defer r.sessionsLk.Unlock()
+	if s, ok := r.pushSessions[id]; ok {
+		return s.size
+	}
+	return 0
+}
+
+// addPushBlock records a body block accepted for transfer in a push session
+func (r *Remote) addPushBlock(ctx context.Context, id string, info dag.Info) {
+	path := blockPath(info)
+	stored := true
+	if path != "" {
+		stored, _ = r.node.Repo.Filesystem().Has(ctx, path)
+	}
+
+	r.sessionsLk.Lock()
+	defer r.sessionsLk.Unlock()
+	s, ok := r.pushSessions[id]
+	if !ok {
+		s = &pushSession{}
+		r.pushSessions[id] = s
+	}
+	s.size += dagSize(info)
+	s.updated = time.Now()
+	if !stored {
+		s.blocks = append(s.blocks, path)
+	}
+}
+
+// endPushSession stops tracking a push session. Blocks a rejected session
+// stored are removed. Blocks listed in keep are read from by an accepted
+// version, and won't be removed by any other session that stored them
+func (r *Remote) endPushSession(ctx context.Context, id string, keep []string, rejected bool) {
+	r.sessionsLk.Lock()
+	s, ok := r.pushSessions[id]
+	delete(r.pushSessions, id)
+	if !rejected {
+		kept := map[string]bool{}
+		for _, path := range keep {
+			kept[path] = true
+		}
+		for _, other := range r.pushSessions {
+			other.blocks = filterPaths(other.blocks, kept)
+		}
+	}
+	r.sessionsLk.Unlock()
+
+	if ok && rejected {
+		r.removePushBlocks(ctx, s.blocks)
+	}
+}
+
+// expirePushSessions drops sessions that haven't received a block within the
+// session timeout, removing the blocks they stored. A client that gives up on
+// a push never sends the version those blocks belong to
+func (r *Remote) expirePushSessions(ctx context.Context) {
+	var expired []string
+	cutoff := time.Now().Add(-r.pushSessionTimeout())
+
+	r.sessionsLk.Lock()
+	for id, s := range r.pushSessions {
+		if s.updated.Before(cutoff) {
+			expired = append(expired, s.blocks...)
+			delete(r.pushSessions, id)
+		}
+	}
+	r.sessionsLk.Unlock()
+
+	r.removePushBlocks(ctx, expired)
+}
+
+// removePushBlocks deletes stored body blocks, skipping any block a session
+// that's still in progress has stored
+func (r *Remote) removePushBlocks(ctx context.Context, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	r.sessionsLk.Lock()
+	inUse := map[string]bool{}
+	for _, s := range r.pushSessions {
+		for _, path := range s.blocks {
+			inUse[path] = true
+		}
+	}
+	r.sessionsLk.Unlock()
+
+	fs := r.node.Repo.Filesystem()
+	for _, path := range paths {
+		if inUse[path] {
+			continue
+		}
+		if local, err := fs.Has(ctx, path); err != nil || !local {
+			continue
+		}
+		if err := fs.Delete(ctx, path); err != nil {
+			log.Debugf("removing body block %q: %s", path, err)
+		}
+	}
+}
+
+func blockPath(info dag.Info) string {
+	if info.Manifest == nil || len(info.Manifest.Nodes) == 0 {
+		return ""
+	}
+	return "/ipfs/" + info.Manifest.Nodes[0]
+}
+
+func filterPaths(paths []string, drop map[string]bool) []string {
+	kept := paths[:0]
+	for _, path := range paths {
+		if !drop[path] {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
@@ -10,14 +10,18 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
 	golog "github.com/ipfs/go-log"
 	"github.com/qri-io/dag"
 	"github.com/qri-io/dag/dsync"
 	"github.com/qri-io/qri/access"
 	apiutil "github.com/qri-io/qri/api/util"
 	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/identity"
@@ -97,6 +101,8 @@ type Remote struct {
 
 	dsync   *dsync.Dsync
 	logsync *logsync.Logsync
+	// nodes reads blocks from local storage only
+	nodes ipld.NodeGetter
 
 	Feeds    Feeds
 	Previews Previews
@@ -104,6 +110,14 @@ type Remote struct {
 	acceptSizeMax int64
 	// TODO (b5) - dsync needs to use timeouts
 	acceptTimeoutMs time.Duration
+	// retention policy for stored versions
+	retention *config.RetentionPolicy
+
+	// body blocks are pushed ahead of the version they belong to. push
+	// sessions tally the blocks received for each push, so blocks count
+	// toward the version's size limit
+	sessionsLk   sync.Mutex
+	pushSessions map[string]*pushSession
 
 	datasetPushPreCheck   Hook
 	datasetPushFinalCheck Hook
@@ -167,6 +181,8 @@ func NewRemote(node *p2p.QriNode, cfg *config.Remote, localResolver dsref.Resolv
 
 		acceptSizeMax:   cfg.AcceptSizeMax,
 		acceptTimeoutMs: cfg.AcceptTimeoutMs,
+		retention:       cfg.Retention,
+		pushSessions:    map[string]*pushSession{},
 
 		datasetPushPreCheck:   o.DatasetPushPreCheck,
 		datasetPushFinalCheck: o.DatasetPushFinalCheck,
@@ -205,6 +221,7 @@ func NewRemote(node *p2p.QriNode, cfg *config.Remote, localResolver dsref.Resolv
 	if err != nil {
 		return nil, err
 	}
+	r.nodes = lng
 
 	r.dsync, err = dsync.New(lng, capi.Block(), func(dsyncConfig *dsync.Config) {
 		if host := r.node.Host(); host != nil {
@@ -360,14 +377,15 @@ func (r *Remote) dsPushPreCheck(ctx context.Context, info dag.Info, meta map[str
 
 	// TODO(dlong): Customization for how to decide to accept the dataset.
 
-	// If size is -1, accept any size of dataset. Otherwise, check if the size is allowed.
+	// If size is -1, accept any size of dataset. Otherwise, check if the size
+	// is allowed. body blocks received in the same push session count toward
+	// the limit. blocks already received for a rejected push are removed
+	r.expirePushSessions(ctx)
+	id := pushSessionID(meta)
+	size := dagSize(info)
 	if r.acceptSizeMax != -1 {
-		var totalSize uint64
-		for _, s := range info.Sizes {
-			totalSize += s
-		}
-
-		if totalSize >= uint64(r.acceptSizeMax) {
+		if size+r.receivedBlockSize(id) >= uint64(r.acceptSizeMax) {
+			r.endPushSession(ctx, id, nil, true)
 			return fmt.Errorf("dataset size too large")
 		}
 	}
@@ -376,29 +394,109 @@ func (r *Remote) dsPushPreCheck(ctx context.Context, info dag.Info, meta map[str
 
 	if r.datasetPushPreCheck != nil {
 		if err := r.datasetPushPreCheck(ctx, pid, ref); err != nil {
+			r.endPushSession(ctx, id, nil, true)
 			return err
 		}
 	}
 
+	if meta[bodyBlockMetaKey] == "true" {
+		r.addPushBlock(ctx, id, info)
+	}
 	return nil
 }
 
 func (r *Remote) dsPushFinalCheck(ctx context.Context, info dag.Info, meta map[string]string) error {
+	subj, ref, err := r.subjAndRefFromMeta(meta)
+	if err != nil {
+		return err
+	}
+
+	if meta[bodyBlockMetaKey] == "true" {
+		if r.datasetPushFinalCheck != nil {
+			if err := r.datasetPushFinalCheck(ctx, subj.ID, ref); err != nil {
+				r.endPushSession(ctx, pushSessionID(meta), nil, true)
+				return err
+			}
+		}
+		return nil
+	}
+
+	// the version has been received. check its size against the body blocks
+	// it actually reads from, no matter what was claimed at push. body blocks
+	// pushed for a rejected version are removed
+	blocks, err := r.versionFinalCheck(ctx, subj, ref, info)
+	r.endPushSession(ctx, pushSessionID(meta), blocks, err != nil)
+	return err
+}
+
+// versionFinalCheck screens a received dataset version, returning the body
+// blocks the version reads from
+func (r *Remote) versionFinalCheck(ctx context.Context, subj *profile.Profile, ref dsref.Ref, info dag.Info) ([]string, error) {
+	blocks, size, err := r.versionSize(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if r.acceptSizeMax != -1 && size >= uint64(r.acceptSizeMax) {
+		return blocks, fmt.Errorf("dataset size too large")
+	}
+
 	if r.datasetPushFinalCheck != nil {
-		subj, ref, err := r.subjAndRefFromMeta(meta)
+		if err := r.datasetPushFinalCheck(ctx, subj.ID, ref); err != nil {
+			return blocks, err
+		}
+	}
+	return blocks, nil
+}
+
+// versionSize adds the size of the stored body blocks a received version reads
+// from to the size of the version itself. Paths of the body blocks are
+// returned alongside the size
+func (r *Remote) versionSize(ctx context.Context, info dag.Info) ([]string, uint64, error) {
+	size := dagSize(info)
+	path := blockPath(info)
+	if path == "" {
+		return nil, size, nil
+	}
+
+	fs := r.node.Repo.Filesystem()
+	ds, err := dsfs.LoadDataset(ctx, fs, path)
+	if err != nil {
+		return nil, 0, err
+	}
+	paths, err := dsfs.BodyBlockPaths(ctx, fs, ds)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, path := range paths {
+		// blocks that were never pushed aren't stored
+		if local, err := fs.Has(ctx, path); err != nil || !local {
+			continue
+		}
+		id, err := cid.Parse(path)
 		if err != nil {
-			return err
+			return nil, 0, err
 		}
-		pid := subj.ID
-		if err := r.datasetPushFinalCheck(ctx, pid, ref); err != nil {
-			return err
+		bi, err := dag.NewInfo(ctx, r.nodes, id)
+		if err != nil {
+			return nil, 0, err
 		}
+		size += dagSize(*bi)
 	}
+	return paths, size, nil
+}
 
-	return nil
+func dagSize(info dag.Info) uint64 {
+	var size uint64
+	for _, s := range info.Sizes {
+		size += s
+	}
+	return size
 }
 
 func (r *Remote) dsPushComplete(ctx context.Context, info dag.Info, meta map[string]string) error {
+	if meta[bodyBlockMetaKey] == "true" {
+		return nil
+	}
 	subj, ref, err := r.subjAndRefFromMeta(meta)
 	if err != nil {
 		return err
@@ -425,7 +523,30 @@ func (r *Remote) dsPushComplete(ctx context.Context, info dag.Info, meta map[str
 
 	// TODO (b5) - this could overwrite any FSI links & other ref details,
 	// need to investigate
-	return repo.PutVersionInfoShim(r.node.Repo, &vi)
+	if err := repo.PutVersionInfoShim(r.node.Repo, &vi); err != nil {
+		return err
+	}
+
+	r.pruneStoredVersions(ctx, ref)
+	return nil
+}
+
+// pruneStoredVersions drops versions of a dataset the author has removed from
+// history and the remote's retention policy doesn't keep from storage. History
+// belongs to the dataset author and isn't changed. Pruning is housekeeping,
+// failures are logged instead of returned
+func (r *Remote) pruneStoredVersions(ctx context.Context, ref dsref.Ref) {
+	if r.retention.KeepsAll() {
+		return
+	}
+	pruned, err := base.PruneStoredVersions(ctx, r.node.Repo, ref, r.retention)
+	if err != nil {
+		log.Debugf("pruning stored versions of %q: %s", ref.Human(), err)
+		return
+	}
+	if len(pruned) > 0 {
+		log.Debugf("pruned %d stored versions of %q", len(pruned), ref.Human())
+	}
 }
 
 func (r *Remote) dsRemovePreCheck(ctx context.Context, info dag.Info, meta map[string]string) error {
@@ -459,7 +580,7 @@ func (r *Remote) dsGetDagInfo(ctx context.Context, into dag.Info, meta map[strin
 	pid := subj.ID
 	log.Debugf("pid %s pulling ref %s", pid.String(), ref.String())
 
-	if r.datasetPulled != nil {
+	if r.datasetPulled != nil && meta[bodyBlockMetaKey] != "true" {
 		if err = r.datasetPulled(ctx, pid, ref); err != nil {
 			log.Errorf("dataset pulled hook: %s", err.Error())
 			return err
@@ -569,6 +690,7 @@ func (r *Remote) AddDefaultRoutes(mux *http.ServeMux) {
 	if ps := r.Previews; ps != nil {
 		mux.Handle("/remote/dataset/preview/", r.PreviewHTTPHandler("/remote/dataset/preview/"))
 		mux.Handle("/remote/dataset/component/", r.ComponentHTTPHandler("/remote/dataset/component/"))
+		mux.Handle("/remote/dataset/body/", r.BodyHTTPHandler("/remote/dataset/body/"))
 	}
 }
 
@@ -663,6 +785,47 @@ func (r *Remote) PreviewHTTPHandler(prefix string) http.HandlerFunc {
 	}
 }
 
+// BodyHTTPHandler handles requests for pages of dataset body data over HTTP.
+// pages are selected with offset & limit query parameters, and a
+// comma-separated list of columns to keep
+func (r *Remote) BodyHTTPHandler(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		refStr := strings.TrimPrefix(req.URL.Path, prefix)
+		if r.PreviewPreCheck != nil {
+			id, err := profile.IDB58Decode(req.Header.Get("pid"))
+			if err != nil {
+				apiutil.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("missing signature details"))
+				return
+			}
+			ref, err := dsref.Parse(refStr)
+			if err != nil {
+				apiutil.WriteErrResponse(w, http.StatusBadRequest, err)
+				return
+			}
+			if err := r.PreviewPreCheck(ctx, id, ref); err != nil {
+				apiutil.WriteErrResponse(w, http.StatusForbidden, err)
+				return
+			}
+		}
+
+		var columns []string
+		if c := req.FormValue("columns"); c != "" {
+			columns = strings.Split(c, ",")
+		}
+		offset := apiutil.ReqParamInt(req, "offset", 0)
+		limit := apiutil.ReqParamInt(req, "limit", base.MaxBodyPageRows)
+
+		page, err := r.Previews.PreviewBody(ctx, "", refStr, offset, limit, columns)
+		if err != nil {
+			apiutil.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		apiutil.WriteResponse(w, page)
+	}
+}
+
 // ComponentHTTPHandler handles dataset component requests over HTTP
 func (r *Remote) ComponentHTTPHandler(prefix string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
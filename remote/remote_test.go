@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	core "github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/interface-go-ipfs-core/path"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/access"
@@ -181,6 +183,238 @@ func TestDatasetPullPushDeleteFeedsPreviewHTTP(t *testing.T) {
 	}
 }
 
+func TestRemoteRetention(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	cfg := &config.Remote{
+		Enabled:       true,
+		AcceptSizeMax: 10000,
+		Retention:     &config.RetentionPolicy{KeepLast: 1},
+	}
+	rem, err := NewRemote(tr.NodeA, cfg, tr.NodeA.Repo.Logbook())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := tr.RemoteTestServer(rem)
+	defer server.Close()
+	cli := tr.NodeBClient(t)
+
+	first := writeVideoViewStats(tr.Ctx, t, tr.NodeB.Repo)
+	if err := cli.PushDataset(tr.Ctx, first, server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	ds := &dataset.Dataset{
+		Name:      "video_view_stats",
+		Commit:    &dataset.Commit{Title: "second commit"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[20]")))
+	second := saveDataset(tr.Ctx, tr.NodeB.Repo, first.Username, ds)
+	if err := cli.PushDataset(tr.Ctx, second, server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	capi, err := tr.NodeA.IPFSCoreAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	isPinned := func(p string) bool {
+		_, pinned, err := capi.Pin().IsPinned(tr.Ctx, path.New(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pinned
+	}
+	if !isPinned(second.Path) {
+		t.Error("expected remote to store the latest version")
+	}
+	// versions in the author's history are listed as held by the remote
+	if !isPinned(first.Path) {
+		t.Error("expected remote to store versions that remain in history")
+	}
+
+	items, err := tr.NodeA.Repo.Logbook().Items(tr.Ctx, second, 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected remote to leave history intact, got %d versions", len(items))
+	}
+
+	// once the author prunes versions, the remote drops them on the next push,
+	// leaving blocks for garbage collection
+	ds = &dataset.Dataset{
+		Name:      "video_view_stats",
+		Commit:    &dataset.Commit{Title: "third commit"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[30]")))
+	third := saveDataset(tr.Ctx, tr.NodeB.Repo, first.Username, ds)
+	third.InitID, err = tr.NodeB.Repo.Logbook().RefToInitID(dsref.Ref{Username: third.Username, Name: third.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.PruneVersions(tr.Ctx, tr.NodeB.Repo, third, &config.RetentionPolicy{KeepLast: 2}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.PushDataset(tr.Ctx, third, server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if !isPinned(third.Path) || !isPinned(second.Path) {
+		t.Error("expected remote to store versions that remain in history")
+	}
+	if isPinned(first.Path) {
+		t.Error("expected remote to drop the version the author pruned")
+	}
+}
+
+func TestRemoteAcceptSizeMaxCountsBodyBlocks(t *testing.T) {
+	threshold, min, avg, max := dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize
+	defer func() {
+		dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize = threshold, min, avg, max
+	}()
+	dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize = 4096, 256, 1024, 4096
+
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	ref, blocks := saveChunkedCities(t, tr)
+	info, err := tr.NodeB.NewDAGInfo(tr.Ctx, ref.Path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionSize := dagSize(*info)
+
+	// accept the version alone, but not the version and its body combined
+	cfg := &config.Remote{
+		Enabled:       true,
+		AcceptSizeMax: int64(versionSize + 1024),
+	}
+	rem, err := NewRemote(tr.NodeA, cfg, tr.NodeA.Repo.Logbook())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := tr.RemoteTestServer(rem)
+	defer server.Close()
+	cli := tr.NodeBClient(t)
+
+	if err := cli.PushDataset(tr.Ctx, ref, server.URL); err == nil {
+		t.Error("expected pushing a version whose body blocks exceed the size limit to fail")
+	}
+	expectPushBlocksRemoved(t, tr, rem, blocks)
+}
+
+func TestRemoteRejectedVersionRemovesBodyBlocks(t *testing.T) {
+	threshold, min, avg, max := dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize
+	defer func() {
+		dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize = threshold, min, avg, max
+	}()
+	dsfs.BodyChunkThreshold, dsfs.BodyChunkMinSize, dsfs.BodyChunkAvgSize, dsfs.BodyChunkMaxSize = 4096, 256, 1024, 4096
+
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	ref, blocks := saveChunkedCities(t, tr)
+
+	// accept every body block, then reject the version they belong to
+	checks := 0
+	rejectVersion := func(o *Options) {
+		o.DatasetPushFinalCheck = func(ctx context.Context, pid profile.ID, ref dsref.Ref) error {
+			if checks++; checks > len(blocks) {
+				return fmt.Errorf("rejected")
+			}
+			return nil
+		}
+	}
+	cfg := &config.Remote{
+		Enabled:       true,
+		AcceptSizeMax: -1,
+	}
+	rem, err := NewRemote(tr.NodeA, cfg, tr.NodeA.Repo.Logbook(), rejectVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := tr.RemoteTestServer(rem)
+	defer server.Close()
+	cli := tr.NodeBClient(t)
+
+	if err := cli.PushDataset(tr.Ctx, ref, server.URL); err == nil {
+		t.Fatal("expected pushing a rejected version to fail")
+	}
+	if checks != len(blocks)+1 {
+		t.Fatalf("expected final check for %d blocks & the version, got %d checks", len(blocks), checks)
+	}
+	expectPushBlocksRemoved(t, tr, rem, blocks)
+}
+
+// saveChunkedCities saves a dataset to node B with a body large enough to be
+// stored as chunks, returning the dataset ref and the paths of body chunks
+func saveChunkedCities(t *testing.T, tr *testRunner) (dsref.Ref, []string) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("city,pop\n")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(buf, "city_%d,%d\n", i, i*7919%100003)
+	}
+	ds := &dataset.Dataset{
+		Name:   "cities",
+		Commit: &dataset.Commit{Title: "initial commit"},
+		Structure: &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true},
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "city", "type": "string"},
+						map[string]interface{}{"title": "pop", "type": "integer"},
+					},
+				},
+			},
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", buf.Bytes()))
+	ref := saveDataset(tr.Ctx, tr.NodeB.Repo, "peer", ds)
+
+	fs := tr.NodeB.Repo.Filesystem()
+	stored, err := dsfs.LoadDataset(tr.Ctx, fs, ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := dsfs.BodyBlockPaths(tr.Ctx, fs, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) < 2 {
+		t.Fatalf("expected body to be stored as chunks, got %d blocks", len(blocks))
+	}
+	return ref, blocks
+}
+
+// expectPushBlocksRemoved checks body blocks of a rejected push are unpinned
+// on node A, leaving them for garbage collection
+func expectPushBlocksRemoved(t *testing.T, tr *testRunner, rem *Remote, blocks []string) {
+	t.Helper()
+	capi, err := tr.NodeA.IPFSCoreAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range blocks {
+		if _, pinned, err := capi.Pin().IsPinned(tr.Ctx, path.New(p)); err != nil {
+			t.Fatal(err)
+		} else if pinned {
+			t.Errorf("expected body block %q of a rejected push to be unpinned", p)
+		}
+	}
+	rem.sessionsLk.Lock()
+	defer rem.sessionsLk.Unlock()
+	if len(rem.pushSessions) != 0 {
+		t.Errorf("expected rejected push sessions to be dropped, got %d sessions", len(rem.pushSessions))
+	}
+}
+
 func TestAddress(t *testing.T) {
 	if _, err := Address(&config.Config{}, ""); err == nil {
 		t.Error("expected error, got nil")
@@ -0,0 +1,202 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	caopts "github.com/ipfs/interface-go-ipfs-core/options"
+	ipath "github.com/ipfs/interface-go-ipfs-core/path"
+	"github.com/qri-io/dag"
+	"github.com/qri-io/dag/dsync"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
+)
+
+// SparseComponents are the components a sparse pull fetches when none are
+// specified, enough to browse a dataset version without transferring its body
+var SparseComponents = []string{"meta", "structure", "readme", "stats"}
+
+// sparseComponents lists components that can be left out of a pull
+var sparseComponents = map[string]bool{
+	"body":      true,
+	"meta":      true,
+	"structure": true,
+	"readme":    true,
+	"stats":     true,
+	"transform": true,
+	"viz":       true,
+}
+
+// componentFiles maps the names of stored files a pull can skip to the
+// component they belong to. Component definitions like meta.json are always
+// pulled, a version can't be read without them. Bodies are matched by prefix
+var componentFiles = map[string]string{
+	"transform_script": "transform",
+	"viz_script":       "viz",
+	"index.html":       "viz",
+	"readme.md":        "readme",
+	"readme.html":      "readme",
+}
+
+// ValidateComponents checks a list of component names can be used to pull a
+// subset of a dataset version
+func ValidateComponents(components []string) error {
+	for _, c := range components {
+		if !sparseComponents[c] {
+			return fmt.Errorf("unknown component %q. must be one of body, meta, structure, readme, stats, transform, viz", c)
+		}
+	}
+	return nil
+}
+
+// componentForFile returns the component a stored file belongs to, returning
+// the empty string for files that are always pulled
+func componentForFile(name string) string {
+	if strings.HasPrefix(name, "body") {
+		return "body"
+	}
+	return componentFiles[name]
+}
+
+// pullDatasetComponents fetches the files of a dataset version that belong to
+// the given components. Files are transferred with dsync, so only blocks
+// missing locally are fetched. Sparse pulls require an HTTP remote, which can
+// be asked for individual blocks
+func (c *client) pullDatasetComponents(ctx context.Context, ref *dsref.Ref, remoteAddr string, components []string) error {
+	log.Debugf("client.pullDatasetComponents: ref=%q remoteAddr=%q components=%v", ref, remoteAddr, components)
+	if addressType(remoteAddr) != "http" {
+		return fmt.Errorf("pulling selected components requires an HTTP remote")
+	}
+	if c.ds == nil || c.capi == nil {
+		return fmt.Errorf("pulling selected components requires an IPFS repo")
+	}
+
+	if ref.Path == "" {
+		if _, err := c.NewRemoteRefResolver(remoteAddr).ResolveRef(ctx, ref); err != nil {
+			log.Errorf("resolving head ref: %s", err.Error())
+			return err
+		}
+	}
+
+	params, err := sigParams(c.node.Repo.PrivateKey(), c.profile.Peername, *ref)
+	if err != nil {
+		log.Debugf("generating sig params error=%q ", err)
+		return err
+	}
+
+	rem := &dsync.HTTPClient{URL: remoteAddr + "/remote/dsync"}
+	info, err := rem.GetDagInfo(ctx, ref.Path, params)
+	if err != nil {
+		return err
+	}
+
+	// the root of a version is a directory, fetch it to read file names
+	rootID := info.RootCID()
+	data, err := rem.GetBlock(ctx, rootID.String())
+	if err != nil {
+		return err
+	}
+	if _, err := c.capi.Block().Put(ctx, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	root, err := c.capi.Dag().Get(ctx, rootID)
+	if err != nil {
+		return err
+	}
+
+	include := map[string]bool{}
+	for _, comp := range components {
+		include[comp] = true
+	}
+
+	var (
+		files  []string
+		nodes  = []string{rootID.String()}
+		sizes  = []uint64{0}
+		listed = map[string]bool{rootID.String(): true}
+	)
+	if len(info.Sizes) > 0 {
+		sizes[0] = info.Sizes[0]
+	}
+	for _, l := range root.Links() {
+		if comp := componentForFile(l.Name); comp != "" && !include[comp] {
+			log.Debugf("skipping file %q of component %q", l.Name, comp)
+			continue
+		}
+		sub, err := info.InfoAtID(l.Cid.String())
+		if err != nil {
+			return fmt.Errorf("finding file %q in version: %w", l.Name, err)
+		}
+		files = append(files, "/ipfs/"+l.Cid.String())
+		for i, id := range sub.Manifest.Nodes {
+			if listed[id] {
+				continue
+			}
+			listed[id] = true
+			nodes = append(nodes, id)
+			if len(sub.Sizes) > i {
+				sizes = append(sizes, sub.Sizes[i])
+			}
+		}
+	}
+
+	sparse := &dag.Info{Manifest: &dag.Manifest{Nodes: nodes}}
+	if len(sizes) == len(nodes) {
+		sparse.Sizes = sizes
+	}
+
+	lng, err := dsync.NewLocalNodeGetter(c.capi)
+	if err != nil {
+		return err
+	}
+	pull, err := dsync.NewPullWithInfo(sparse, lng, c.capi.Block(), rem, params)
+	if err != nil {
+		return err
+	}
+	go func() {
+		updates := pull.Updates()
+		for {
+			select {
+			case update := <-updates:
+				go func() {
+					prog := event.RemoteEvent{
+						Ref:        *ref,
+						RemoteAddr: remoteAddr,
+						Progress:   update,
+					}
+					if err := c.events.Publish(ctx, event.ETRemoteClientPullVersionProgress, prog); err != nil {
+						log.Error("publishing %q event: %q", event.ETRemoteClientPullVersionProgress, err)
+					}
+				}()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	if err := pull.Do(ctx); err != nil {
+		return err
+	}
+
+	// pin the root directly, a recursive pin would require skipped files
+	if err := c.capi.Pin().Add(ctx, ipath.New(ref.Path), caopts.Pin.Recursive(false)); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := c.capi.Pin().Add(ctx, ipath.New(f)); err != nil {
+			return err
+		}
+	}
+
+	if include["body"] {
+		if err := c.pullBodyBlocks(ctx, *ref, remoteAddr+"/remote/dsync", params); err != nil {
+			return err
+		}
+	}
+
+	return c.events.Publish(ctx, event.ETRemoteClientPullVersionCompleted, event.RemoteEvent{
+		Ref:        *ref,
+		RemoteAddr: remoteAddr,
+	})
+}
@@ -0,0 +1,34 @@
+package remote
+
+import (
+	"testing"
+)
+
+func TestValidateComponents(t *testing.T) {
+	if err := ValidateComponents(SparseComponents); err != nil {
+		t.Errorf("expected sparse components to be valid, got: %s", err)
+	}
+	if err := ValidateComponents([]string{"meta", "commit"}); err == nil {
+		t.Error("expected unknown component to error")
+	}
+}
+
+func TestComponentForFile(t *testing.T) {
+	cases := []struct {
+		name, expect string
+	}{
+		{"body.csv", "body"},
+		{"body", "body"},
+		{"transform_script", "transform"},
+		{"viz_script", "viz"},
+		{"index.html", "viz"},
+		{"readme.md", "readme"},
+		{"meta.json", ""},
+		{"dataset.json", ""},
+	}
+	for _, c := range cases {
+		if got := componentForFile(c.name); got != c.expect {
+			t.Errorf("%q: expected component %q, got %q", c.name, c.expect, got)
+		}
+	}
+}
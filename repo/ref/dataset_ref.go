@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/repo/profile"
 )
 
@@ -101,9 +102,10 @@ func (r DatasetRef) Complete() bool {
 }
 
 // Match checks returns true if Peername and Name are equal,
-// and/or path is equal
+// and/or path is equal. Paths that address the same content with different
+// CID versions are equal
 func (r DatasetRef) Match(b DatasetRef) bool {
-	return (r.Path != "" && b.Path != "" && r.Path == b.Path) || (r.ProfileID == b.ProfileID || r.Peername == b.Peername) && r.Name == b.Name
+	return (r.Path != "" && b.Path != "" && dsref.EqualPaths(r.Path, b.Path)) || (r.ProfileID == b.ProfileID || r.Peername == b.Peername) && r.Name == b.Name
 }
 
 // Equal returns true only if Peername Name and Path are equal
@@ -128,7 +128,9 @@ func CanonicalizeDatasetRef(r Repo, ref *reporef.DatasetRef) error {
 	}
 
 	// TODO (b5) - this is the assign pattern, refactor into a method on reporef.DatasetRef
-	if ref.Path == "" {
+	// paths that use a different CID version than the stored path are
+	// canonicalized to the stored path
+	if ref.Path == "" || dsref.EqualPaths(ref.Path, got.Path) {
 		ref.Path = got.Path
 	}
 	if ref.ProfileID == "" {
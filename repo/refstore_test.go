@@ -201,6 +201,26 @@ func TestParseDatasetRef(t *testing.T) {
 	}
 }
 
+func TestRefsFlatbufferMixedPaths(t *testing.T) {
+	refs := RefList{
+		{Peername: "lucille", Name: "cidv0", Path: "/ipfs/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn"},
+		{Peername: "lucille", Name: "cidv1", Path: "/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu"},
+		{Peername: "lucille", Name: "elsewhere", Path: "/arweave/Some1Hash"},
+	}
+	got, err := UnmarshalRefsFlatbuffer(FlatbufferBytes(refs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(refs) {
+		t.Fatalf("expected %d refs, got %d", len(refs), len(got))
+	}
+	for i, ref := range refs {
+		if err := CompareDatasetRef(got[i], ref); err != nil {
+			t.Errorf("ref %d: %s", i, err)
+		}
+	}
+}
+
 func TestMatch(t *testing.T) {
 	cases := []struct {
 		a, b  string
@@ -213,6 +233,9 @@ func TestMatch(t *testing.T) {
 		{"a/different_name@/b/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", "a/b@/b/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", true},
 		{"different_peername/b@/b/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", "a/b@/b/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", true},
 		{"different_peername/b@/b/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", "QmYCvbfNbCwFR45HiNP45rwJgvatpiW38D961L5qAhUM5Y/b@/b/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", true},
+
+		{"a/b@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", "c/d@/ipfs/bafybeibq5y3ndsmzx4srizqwssqravof5esn5k5v57w7x732dejlpihb3y", true},
+		{"a/b@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", "c/d@/mem/bafybeibq5y3ndsmzx4srizqwssqravof5esn5k5v57w7x732dejlpihb3y", false},
 	}
 
 	for i, c := range cases {
@@ -351,6 +374,9 @@ func TestCanonicalizeDatasetRef(t *testing.T) {
 		{ProfileID: lucille.ID, Peername: "lucille", Name: "foo", Path: "/ipfs/QmTest"},
 		{ProfileID: carla.ID, Peername: carla.Peername, Name: "hockey_stats", Path: "/ipfs/QmTest2"},
 		{ProfileID: lucille.ID, Peername: "lucille", Name: "ball", Path: "/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1"},
+		// repos can mix CID versions & networks
+		{ProfileID: lucille.ID, Peername: "lucille", Name: "cids", Path: "/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu"},
+		{ProfileID: lucille.ID, Peername: "lucille", Name: "elsewhere", Path: "/arweave/Some1Hash"},
 	} {
 		if err := rs.PutRef(r); err != nil {
 			t.Fatal(err)
@@ -378,6 +404,11 @@ func TestCanonicalizeDatasetRef(t *testing.T) {
 		{"me/ball@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", "lucille/ball@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", ""},
 		{"@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", "lucille/ball@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", ""},
 		{"renamed/ball@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", "lucille/ball@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", ""},
+		{"me/ball@/ipfs/bafybeibq5y3ndsmzx4srizqwssqravof5esn5k5v57w7x732dejlpihb3y", "lucille/ball@/ipfs/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", ""},
+		{"me/cids", "lucille/cids@/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu", ""},
+		{"@/ipfs/QmXATayrFgsS3tpCi2ykfpNJ8uiCWT74dttnvJvVo1J7Rn", "lucille/cids@/ipfs/bafybeieddtgc6cjdb6rryaee66baqxnvjxchzg2l3r4vemkiqsocgjj6lu", ""},
+		{"me/elsewhere", "lucille/elsewhere@/arweave/Some1Hash", ""},
+		{"@/arweave/Some1Hash", "lucille/elsewhere@/arweave/Some1Hash", ""},
 	}
 
 	for i, c := range cases {
@@ -0,0 +1,80 @@
+// +build !arm
+
+package sql
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cube2222/octosql"
+	"github.com/cube2222/octosql/execution"
+	"github.com/cube2222/octosql/output"
+)
+
+// rawCSVOutput writes records as CSV with a header row. Unlike octosql's csv
+// output values are written without display formatting (strings aren't
+// quoted, nulls are empty), making output suitable for use as a dataset body
+type rawCSVOutput struct {
+	w       io.Writer
+	records []*execution.Record
+}
+
+var _ output.Output = (*rawCSVOutput)(nil)
+
+func newRawCSVOutput(w io.Writer) output.Output {
+	return &rawCSVOutput{w: w}
+}
+
+// WriteRecord buffers a record. records are written on close, once the full
+// set of fields is known
+func (o *rawCSVOutput) WriteRecord(record *execution.Record) error {
+	o.records = append(o.records, record)
+	return nil
+}
+
+// Close writes all buffered records
+func (o *rawCSVOutput) Close() error {
+	var fields []string
+	seen := map[string]bool{}
+	for _, record := range o.records {
+		for _, field := range record.Fields() {
+			if name := field.Name.String(); !seen[name] {
+				seen[name] = true
+				fields = append(fields, name)
+			}
+		}
+	}
+
+	out := csv.NewWriter(o.w)
+	if err := out.Write(fields); err != nil {
+		return fmt.Errorf("writing header row: %w", err)
+	}
+
+	row := make([]string, len(fields))
+	for _, record := range o.records {
+		for i, field := range fields {
+			row[i] = rawString(record.Value(octosql.NewVariableName(field)))
+		}
+		if err := out.Write(row); err != nil {
+			return fmt.Errorf("writing row: %w", err)
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func rawString(v octosql.Value) string {
+	switch v.GetType() {
+	case octosql.TypeZero, octosql.TypeNull:
+		return ""
+	case octosql.TypeString:
+		return v.AsString()
+	case octosql.TypeTime:
+		return v.AsTime().Format(time.RFC3339Nano)
+	default:
+		return v.Show()
+	}
+}
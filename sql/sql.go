@@ -42,7 +42,8 @@ func New(r repo.Repo, loadDataset dsref.ParseResolveLoad) *Service {
 	}
 }
 
-// Exec runs an SQL query against a given dataset mapping
+// Exec runs an SQL query against a given dataset mapping. outFormat is one of
+// "table", "table_row_separated", "json", "csv", "tabbed", or "csv_raw"
 func (svc *Service) Exec(ctx context.Context, w io.Writer, outFormat, query string) error {
 	processedQuery, sources, err := preprocess.Query(query)
 	if err != nil {
@@ -89,6 +90,8 @@ func (svc *Service) Exec(ctx context.Context, w io.Writer, outFormat, query stri
 		out = csvoutput.NewOutput(',', w)
 	case "tabbed":
 		out = csvoutput.NewOutput('\t', w)
+	case "csv_raw":
+		out = newRawCSVOutput(w)
 	default:
 		err = fmt.Errorf("invalid output type: %s", w)
 		log.Error(err)
@@ -368,3 +368,44 @@ func (d *Dataset) writeStructure(data starlark.Value) *dataset.Structure {
 		Schema: sch,
 	}
 }
+
+// ReadBody reads all entries from a dataset body file into an array or object,
+// replacing the consumed file so the body can be read again
+func ReadBody(ds *dataset.Dataset) (interface{}, error) {
+	f := ds.BodyFile()
+	if f == nil || ds.Structure == nil {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes(f.FileName(), data))
+
+	rr, err := dsio.NewEntryReader(ds.Structure, qfs.NewMemfileBytes(f.FileName(), data))
+	if err != nil {
+		return nil, err
+	}
+
+	if ds.Structure.Schema != nil && ds.Structure.Schema["type"] == "object" {
+		obj := map[string]interface{}{}
+		err = dsio.EachEntry(rr, func(_ int, ent dsio.Entry, err error) error {
+			if err != nil {
+				return err
+			}
+			obj[ent.Key] = ent.Value
+			return nil
+		})
+		return obj, err
+	}
+
+	arr := []interface{}{}
+	err = dsio.EachEntry(rr, func(_ int, ent dsio.Entry, err error) error {
+		if err != nil {
+			return err
+		}
+		arr = append(arr, ent.Value)
+		return nil
+	})
+	return arr, err
+}
@@ -1,11 +1,20 @@
 package qri
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/deepdiff"
+	"github.com/qri-io/qri/base/toqtype"
+	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/repo"
+	skyds "github.com/qri-io/qri/startf/ds"
+	"github.com/qri-io/starlib/util"
 	"go.starlark.net/starlark"
 	"go.starlark.net/starlarkstruct"
 )
@@ -14,20 +23,58 @@ import (
 // in starlark's load() function, eg: load('qri.star', 'qri')
 const ModuleName = "qri.star"
 
-var (
-	once      sync.Once
-	qriModule starlark.StringDict
-)
+// StatsFunc calculates statistics for a dataset with an open body file
+type StatsFunc func(ctx context.Context, ds *dataset.Dataset) (*dataset.Stats, error)
+
+// QueryFunc executes an SQL query, loading referenced datasets with loader &
+// writing results to w in the given format. The qri module requests "json",
+// a JSON array of objects
+type QueryFunc func(ctx context.Context, loader dsref.ParseResolveLoad, w io.Writer, outFormat, query string) error
 
 // NewModule creates a new qri module instance
-func NewModule(repo repo.Repo) *Module {
-	return &Module{repo: repo}
+func NewModule(ctx context.Context, repo repo.Repo, opts ...func(m *Module)) *Module {
+	m := &Module{
+		ctx:       ctx,
+		repo:      repo,
+		resources: map[string]*dataset.TransformResource{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// OptLoader sets the function the module uses to load datasets
+func OptLoader(loader dsref.ParseResolveLoad) func(m *Module) {
+	return func(m *Module) {
+		m.loadDataset = loader
+	}
+}
+
+// OptStatsFunc sets the function the module uses to calculate stats
+func OptStatsFunc(stats StatsFunc) func(m *Module) {
+	return func(m *Module) {
+		m.stats = stats
+	}
+}
+
+// OptQueryFunc sets the function the module uses to execute SQL queries
+func OptQueryFunc(query QueryFunc) func(m *Module) {
+	return func(m *Module) {
+		m.query = query
+	}
 }
 
 // Module encapsulates state for a qri starlark module
 type Module struct {
-	repo repo.Repo
-	ds   *dataset.Dataset
+	ctx         context.Context
+	repo        repo.Repo
+	loadDataset dsref.ParseResolveLoad
+	stats       StatsFunc
+	query       QueryFunc
+
+	lk        sync.Mutex
+	resources map[string]*dataset.TransformResource
 }
 
 // Namespace produces this module's exported namespace
@@ -45,17 +92,60 @@ func (m *Module) Struct() *starlarkstruct.Struct {
 // AddAllMethods augments a starlark.StringDict with all qri builtins. Should really only be used during "transform" step
 func (m *Module) AddAllMethods(sd starlark.StringDict) starlark.StringDict {
 	sd["list_datasets"] = starlark.NewBuiltin("list_datasets", m.ListDatasets)
+	sd["load_dataset"] = starlark.NewBuiltin("load_dataset", m.LoadDataset)
+	sd["history"] = starlark.NewBuiltin("history", m.History)
+	sd["stats"] = starlark.NewBuiltin("stats", m.Stats)
+	sd["diff"] = starlark.NewBuiltin("diff", m.Diff)
+	sd["sql"] = starlark.NewBuiltin("sql", m.SQL)
 	return sd
 }
 
+// Resources returns the dataset versions read by module calls, keyed by path
+func (m *Module) Resources() map[string]*dataset.TransformResource {
+	m.lk.Lock()
+	defer m.lk.Unlock()
+	res := make(map[string]*dataset.TransformResource, len(m.resources))
+	for path, r := range m.resources {
+		res[path] = r
+	}
+	return res
+}
+
+// RecordResource adds a dataset version to the set of resources read during
+// script execution
+func (m *Module) RecordResource(ds *dataset.Dataset) {
+	if ds == nil || ds.Path == "" {
+		return
+	}
+	m.lk.Lock()
+	defer m.lk.Unlock()
+	m.resources[ds.Path] = &dataset.TransformResource{
+		Path: fmt.Sprintf("%s/%s@%s", ds.Peername, ds.Name, ds.Path),
+	}
+}
+
 // ListDatasets shows current local datasets
 func (m *Module) ListDatasets(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var offset, limit int = 0, -1
+	if err := starlark.UnpackArgs("list_datasets", args, kwargs, "offset?", &offset, "limit?", &limit); err != nil {
+		return starlark.None, err
+	}
 	if m.repo == nil {
 		return starlark.None, fmt.Errorf("no qri repo available to list datasets")
 	}
 
-	// TODO (b5) - remove hardcoded limit
-	refs, err := m.repo.References(0, 100000)
+	if limit < 0 {
+		count, err := m.repo.RefCount()
+		if err != nil {
+			return starlark.None, fmt.Errorf("error getting dataset count: %s", err.Error())
+		}
+		limit = count - offset
+	}
+	if limit <= 0 {
+		return &starlark.List{}, nil
+	}
+
+	refs, err := m.repo.References(offset, limit)
 	if err != nil {
 		return starlark.None, fmt.Errorf("error getting dataset list: %s", err.Error())
 	}
@@ -66,3 +156,195 @@ func (m *Module) ListDatasets(thread *starlark.Thread, _ *starlark.Builtin, args
 	}
 	return l, nil
 }
+
+// LoadDataset loads a dataset version. References may include a version path
+// to load a specific historical version, eg: "me/dataset@/ipfs/QmFoo"
+func (m *Module) LoadDataset(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var refstr starlark.String
+	if err := starlark.UnpackArgs("load_dataset", args, kwargs, "ref", &refstr); err != nil {
+		return starlark.None, err
+	}
+
+	ds, err := m.load(refstr.GoString())
+	if err != nil {
+		return starlark.None, err
+	}
+	return skyds.NewDataset(ds, nil).Methods(), nil
+}
+
+// History lists the versions of a dataset, newest first
+func (m *Module) History(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		refstr        starlark.String
+		offset, limit int = 0, 25
+	)
+	if err := starlark.UnpackArgs("history", args, kwargs, "ref", &refstr, "offset?", &offset, "limit?", &limit); err != nil {
+		return starlark.None, err
+	}
+	if m.repo == nil {
+		return starlark.None, fmt.Errorf("no qri repo available to read history")
+	}
+	book := m.repo.Logbook()
+	if book == nil {
+		return starlark.None, fmt.Errorf("no logbook available to read history")
+	}
+
+	ref, err := dsref.Parse(refstr.GoString())
+	if err != nil {
+		return starlark.None, err
+	}
+	if ref.Username == "me" {
+		pro, err := m.repo.Profile()
+		if err != nil {
+			return starlark.None, err
+		}
+		ref.Username = pro.Peername
+	}
+	if _, err := m.repo.ResolveRef(m.ctx, &ref); err != nil {
+		return starlark.None, err
+	}
+	m.RecordResource(&dataset.Dataset{Peername: ref.Username, Name: ref.Name, Path: ref.Path})
+
+	items, err := book.Items(m.ctx, ref, offset, limit)
+	if err != nil {
+		return starlark.None, err
+	}
+	return toStarlark(items)
+}
+
+// Stats calculates statistics for a dataset version
+func (m *Module) Stats(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var refstr starlark.String
+	if err := starlark.UnpackArgs("stats", args, kwargs, "ref", &refstr); err != nil {
+		return starlark.None, err
+	}
+	if m.stats == nil {
+		return starlark.None, fmt.Errorf("stats are not enabled")
+	}
+
+	ds, err := m.load(refstr.GoString())
+	if err != nil {
+		return starlark.None, err
+	}
+	sa, err := m.stats(m.ctx, ds)
+	if err != nil {
+		return starlark.None, err
+	}
+	return toStarlark(sa.Stats)
+}
+
+// Diff compares two dataset versions, returning a list of changes and a
+// summary of change statistics
+func (m *Module) Diff(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var left, right starlark.String
+	if err := starlark.UnpackArgs("diff", args, kwargs, "left", &left, "right", &right); err != nil {
+		return starlark.None, err
+	}
+
+	leftData, err := m.diffData(left.GoString())
+	if err != nil {
+		return starlark.None, err
+	}
+	rightData, err := m.diffData(right.GoString())
+	if err != nil {
+		return starlark.None, err
+	}
+
+	deltas, stat, err := deepdiff.New().StatDiff(m.ctx, leftData, rightData)
+	if err != nil {
+		return starlark.None, err
+	}
+	return toStarlark(map[string]interface{}{
+		"diff": deltas,
+		"stat": stat,
+	})
+}
+
+// diffData loads a dataset as a map, including the body
+func (m *Module) diffData(refstr string) (map[string]interface{}, error) {
+	ds, err := m.load(refstr)
+	if err != nil {
+		return nil, err
+	}
+	body, err := skyds.ReadBody(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	// ignore fields that change with every version
+	cp := &dataset.Dataset{}
+	cp.Assign(ds)
+	cp.Name, cp.Peername, cp.Path, cp.PreviousPath = "", "", "", ""
+	cp.Commit = nil
+
+	data, err := toqtype.StructToMap(cp)
+	if err != nil {
+		return nil, err
+	}
+	data["body"] = body
+	return data, nil
+}
+
+// SQL runs a query against datasets, returning a list of rows. Each row is
+// a dict keyed by column name
+func (m *Module) SQL(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var query starlark.String
+	if err := starlark.UnpackArgs("sql", args, kwargs, "query", &query); err != nil {
+		return starlark.None, err
+	}
+	if m.query == nil || m.loadDataset == nil {
+		return starlark.None, fmt.Errorf("sql is not enabled")
+	}
+
+	// wrap the dataset loader to record which versions the query reads
+	buf := &bytes.Buffer{}
+	if err := m.query(m.ctx, m.recordingLoader(m.loadDataset), buf, "json", query.GoString()); err != nil {
+		return starlark.None, err
+	}
+
+	rows := []interface{}{}
+	// queries that match no rows don't write an opening bracket
+	if data := bytes.TrimSpace(buf.Bytes()); len(data) > 0 && !bytes.Equal(data, []byte("]")) {
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return starlark.None, err
+		}
+	}
+	return util.Marshal(rows)
+}
+
+// recordingLoader wraps a loader, recording each dataset loaded as a resource
+// of the module
+func (m *Module) recordingLoader(loader dsref.ParseResolveLoad) dsref.ParseResolveLoad {
+	return func(ctx context.Context, refstr string) (*dataset.Dataset, error) {
+		ds, err := loader(ctx, refstr)
+		if err == nil {
+			m.RecordResource(ds)
+		}
+		return ds, err
+	}
+}
+
+func (m *Module) load(refstr string) (*dataset.Dataset, error) {
+	if m.loadDataset == nil {
+		return nil, fmt.Errorf("loading datasets is not enabled")
+	}
+	ds, err := m.loadDataset(m.ctx, refstr)
+	if err != nil {
+		return nil, err
+	}
+	m.RecordResource(ds)
+	return ds, nil
+}
+
+// toStarlark converts a go value to starlark via it's JSON representation
+func toStarlark(v interface{}) (starlark.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return starlark.None, err
+	}
+	var val interface{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return starlark.None, err
+	}
+	return util.Marshal(val)
+}
@@ -1,6 +1,7 @@
 package qri
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestNewModule(t *testing.T) {
 func newLoader(ds *dataset.Dataset) func(thread *starlark.Thread, module string) (starlark.StringDict, error) {
 	return func(thread *starlark.Thread, module string) (starlark.StringDict, error) {
 		if module == ModuleName {
-			return starlark.StringDict{"qri": NewModule(nil).Struct()}, nil
+			return starlark.StringDict{"qri": NewModule(context.Background(), nil).Struct()}, nil
 		}
 
 		return nil, fmt.Errorf("invalid module")
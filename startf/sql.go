@@ -0,0 +1,95 @@
+package startf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/detect"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/dsref"
+	skyqri "github.com/qri-io/qri/startf/qri"
+)
+
+const (
+	// SyntaxStarlark identifies transforms written in starlark
+	SyntaxStarlark = "starlark"
+	// SyntaxSQL identifies transforms that are an SQL query. The query result
+	// becomes the dataset body
+	SyntaxSQL = "sql"
+)
+
+// execSQL runs an SQL transform, setting the body of next to the result of
+// the query & inferring it's structure. Every dataset version the query reads
+// is recorded as a transform resource
+func execSQL(ctx context.Context, next *dataset.Dataset, o *ExecOpts) error {
+	if o.QueryFunc == nil || o.DatasetLoader == nil {
+		return fmt.Errorf("sql transforms are not enabled")
+	}
+	if o.MutateFieldCheck != nil {
+		for _, field := range []string{"body", "structure"} {
+			if err := o.MutateFieldCheck(field); err != nil {
+				return fmt.Errorf("cannot use an sql transform and set the %s of a dataset at the same time", field)
+			}
+		}
+	}
+
+	data, err := ioutil.ReadAll(next.Transform.ScriptFile())
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(string(data))
+	if query == "" {
+		return fmt.Errorf("sql transform: empty query")
+	}
+
+	// record the datasets the query reads via a qri module
+	mod := skyqri.NewModule(ctx, o.Repo)
+	loader := func(ctx context.Context, refstr string) (*dataset.Dataset, error) {
+		ds, err := o.DatasetLoader(ctx, refstr)
+		if err == nil {
+			mod.RecordResource(ds)
+		}
+		return ds, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := o.QueryFunc(ctx, dsref.ParseResolveLoad(loader), buf, "csv_raw", query); err != nil {
+		return err
+	}
+
+	// a query that matches nothing is a valid result, saved as an empty body
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		next.Structure = &dataset.Structure{
+			Format: dataset.CSVDataFormat.String(),
+			Schema: map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "array", "items": []interface{}{}},
+			},
+		}
+	} else {
+		st, _, err := detect.FromReader(dataset.CSVDataFormat, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return fmt.Errorf("sql transform: determining result structure: %w", err)
+		}
+		next.Structure = st
+	}
+	next.SetBodyFile(qfs.NewMemfileBytes("body.csv", buf.Bytes()))
+
+	if resources := mod.Resources(); len(resources) > 0 {
+		if next.Transform.Resources == nil {
+			next.Transform.Resources = map[string]*dataset.TransformResource{}
+		}
+		for path, r := range resources {
+			next.Transform.Resources[path] = r
+		}
+	}
+
+	next.Transform.Syntax = SyntaxSQL
+	next.Transform.SyntaxVersion = Version
+	next.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.sql", data))
+	return nil
+}
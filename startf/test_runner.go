@@ -0,0 +1,397 @@
+package startf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	skyds "github.com/qri-io/qri/startf/ds"
+	"github.com/qri-io/starlib/util"
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// TestFuncPrefix marks a function defined in a transform test script as a
+// test case
+const TestFuncPrefix = "test_"
+
+// TestScriptPath returns the conventional companion test script path for a
+// transform script, eg: "transform.star" -> "transform_test.star"
+func TestScriptPath(scriptPath string) string {
+	return strings.TrimSuffix(scriptPath, ".star") + "_test.star"
+}
+
+// TestResult is the outcome of running a single transform test function
+type TestResult struct {
+	// name of the test function
+	Name string `json:"name"`
+	// true if the test ran to completion without any failed assertions
+	Passed bool `json:"passed"`
+	// messages from failed assertions
+	Failures []string `json:"failures,omitempty"`
+	// error that halted test execution, if any
+	Error string `json:"error,omitempty"`
+	// "stderr" output written by the transform & test script
+	Output string `json:"output,omitempty"`
+	// time spent running the test function
+	Duration time.Duration `json:"duration"`
+}
+
+// RunTests executes each test_* function defined in testScript. Test functions
+// are called with a single argument that can run the transform script against
+// a fake previous dataset, config and secrets, and assert on what the
+// transform produced. Transforms are always executed with the network
+// disabled. opts are applied to every transform execution
+func RunTests(ctx context.Context, script, testScript qfs.File, opts ...func(o *ExecOpts)) ([]*TestResult, error) {
+	scriptData, err := ioutil.ReadAll(script)
+	if err != nil {
+		return nil, fmt.Errorf("reading transform script: %w", err)
+	}
+
+	o := &ExecOpts{}
+	DefaultExecOpts(o)
+	for _, opt := range opts {
+		opt(o)
+	}
+	// test scripts are held to the same dialect settings as the transform
+	resolve.AllowFloat = o.AllowFloat
+	resolve.AllowSet = o.AllowSet
+	resolve.AllowLambda = o.AllowLambda
+	resolve.AllowNestedDef = o.AllowNestedDef
+	starlark.Universe["error"] = starlark.NewBuiltin("error", Error)
+
+	errOut := &bytes.Buffer{}
+	thread := &starlark.Thread{
+		Load: o.ModuleLoader,
+		Print: func(thread *starlark.Thread, msg string) {
+			errOut.WriteString(msg + "\n")
+		},
+	}
+
+	globals, err := starlark.ExecFile(thread, testScript.FileName(), testScript, nil)
+	if err != nil {
+		if evalErr, ok := err.(*starlark.EvalError); ok {
+			return nil, fmt.Errorf(evalErr.Backtrace())
+		}
+		return nil, err
+	}
+
+	tests := []*starlark.Function{}
+	for name, val := range globals {
+		if fn, ok := val.(*starlark.Function); ok && strings.HasPrefix(name, TestFuncPrefix) {
+			tests = append(tests, fn)
+		}
+	}
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("no test functions found in %s", testScript.FileName())
+	}
+	// run tests in the order they're defined
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Position().Line < tests[j].Position().Line
+	})
+
+	results := make([]*TestResult, 0, len(tests))
+	for _, fn := range tests {
+		errOut.Reset()
+		tc := &testCase{
+			ctx:        ctx,
+			scriptName: script.FileName(),
+			script:     scriptData,
+			opts:       opts,
+			errOut:     errOut,
+		}
+
+		start := time.Now()
+		_, err := starlark.Call(thread, fn, starlark.Tuple{tc.Struct()}, nil)
+		res := &TestResult{
+			Name:     fn.Name(),
+			Failures: tc.failures,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			if evalErr, ok := err.(*starlark.EvalError); ok {
+				res.Error = evalErr.Backtrace()
+			} else {
+				res.Error = err.Error()
+			}
+		}
+		res.Passed = res.Error == "" && len(res.Failures) == 0
+		res.Output = errOut.String()
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// testCase carries the state of a single test function call
+type testCase struct {
+	ctx        context.Context
+	scriptName string
+	script     []byte
+	opts       []func(o *ExecOpts)
+	errOut     *bytes.Buffer
+
+	// result of the most recent call to run
+	ds       *dataset.Dataset
+	failures []string
+}
+
+// Struct exposes testCase methods as a starlark struct
+func (tc *testCase) Struct() *starlarkstruct.Struct {
+	return starlarkstruct.FromStringDict(starlark.String("test"), starlark.StringDict{
+		"run":           starlark.NewBuiltin("run", tc.run),
+		"fail":          starlark.NewBuiltin("fail", tc.fail),
+		"assert_eq":     starlark.NewBuiltin("assert_eq", tc.assertEq),
+		"assert_body":   starlark.NewBuiltin("assert_body", tc.assertBody),
+		"assert_schema": starlark.NewBuiltin("assert_schema", tc.assertSchema),
+		"assert_meta":   starlark.NewBuiltin("assert_meta", tc.assertMeta),
+	})
+}
+
+func (tc *testCase) errorf(format string, args ...interface{}) {
+	tc.failures = append(tc.failures, fmt.Sprintf(format, args...))
+}
+
+// run executes the transform script, returning the resulting dataset
+func (tc *testCase) run(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var prevx, configx, secretsx, download starlark.Value
+	if err := starlark.UnpackArgs("run", args, kwargs, "prev?", &prevx, "config?", &configx, "secrets?", &secretsx, "download?", &download); err != nil {
+		return starlark.None, err
+	}
+
+	prev, err := fakeDataset(prevx)
+	if err != nil {
+		return starlark.None, fmt.Errorf("prev: %w", err)
+	}
+	config, err := unmarshalDict(configx)
+	if err != nil {
+		return starlark.None, fmt.Errorf("config: %w", err)
+	}
+	secrets, err := unmarshalDict(secretsx)
+	if err != nil {
+		return starlark.None, fmt.Errorf("secrets: %w", err)
+	}
+
+	next := &dataset.Dataset{Transform: &dataset.Transform{Config: config}}
+	next.Transform.SetScriptFile(qfs.NewMemfileBytes(tc.scriptName, tc.script))
+
+	opts := append(tc.opts, SetErrWriter(tc.errOut), func(o *ExecOpts) {
+		o.Secrets = secrets
+		o.DisableNetwork = true
+		o.DownloadResult = download
+	})
+	if err := ExecScript(tc.ctx, next, prev, opts...); err != nil {
+		return starlark.None, err
+	}
+
+	tc.ds = next
+	return skyds.NewDataset(next, nil).Methods(), nil
+}
+
+// fail marks the test as failed with a message
+func (tc *testCase) fail(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var msg starlark.String
+	if err := starlark.UnpackPositionalArgs("fail", args, kwargs, 1, &msg); err != nil {
+		return starlark.None, err
+	}
+	tc.errorf("%s", msg.GoString())
+	return starlark.None, nil
+}
+
+func (tc *testCase) assertEq(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		got, want starlark.Value
+		msg       starlark.String
+	)
+	if err := starlark.UnpackArgs("assert_eq", args, kwargs, "got", &got, "want", &want, "msg?", &msg); err != nil {
+		return starlark.None, err
+	}
+
+	eq, err := starlark.Equal(got, want)
+	if err != nil {
+		return starlark.None, err
+	}
+	if !eq {
+		if msg != "" {
+			tc.errorf("%s: got %s, want %s", msg.GoString(), got.String(), want.String())
+		} else {
+			tc.errorf("got %s, want %s", got.String(), want.String())
+		}
+	}
+	return starlark.None, nil
+}
+
+func (tc *testCase) assertBody(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var wantx starlark.Value
+	if err := starlark.UnpackPositionalArgs("assert_body", args, kwargs, 1, &wantx); err != nil {
+		return starlark.None, err
+	}
+	if tc.ds == nil {
+		return starlark.None, fmt.Errorf("assert_body called before run")
+	}
+
+	want, err := util.Unmarshal(wantx)
+	if err != nil {
+		return starlark.None, err
+	}
+	got, err := skyds.ReadBody(tc.ds)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	tc.assertJSONEqual("body", got, want)
+	return starlark.None, nil
+}
+
+func (tc *testCase) assertSchema(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var wantx starlark.Value
+	if err := starlark.UnpackPositionalArgs("assert_schema", args, kwargs, 1, &wantx); err != nil {
+		return starlark.None, err
+	}
+	if tc.ds == nil {
+		return starlark.None, fmt.Errorf("assert_schema called before run")
+	}
+
+	want, err := util.Unmarshal(wantx)
+	if err != nil {
+		return starlark.None, err
+	}
+	var got map[string]interface{}
+	if tc.ds.Structure != nil {
+		got = tc.ds.Structure.Schema
+	}
+
+	tc.assertJSONEqual("schema", got, want)
+	return starlark.None, nil
+}
+
+func (tc *testCase) assertMeta(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		key   starlark.String
+		wantx starlark.Value
+	)
+	if err := starlark.UnpackPositionalArgs("assert_meta", args, kwargs, 2, &key, &wantx); err != nil {
+		return starlark.None, err
+	}
+	if tc.ds == nil {
+		return starlark.None, fmt.Errorf("assert_meta called before run")
+	}
+
+	want, err := util.Unmarshal(wantx)
+	if err != nil {
+		return starlark.None, err
+	}
+	var got interface{}
+	if tc.ds.Meta != nil {
+		data, err := json.Marshal(tc.ds.Meta)
+		if err != nil {
+			return starlark.None, err
+		}
+		meta := map[string]interface{}{}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return starlark.None, err
+		}
+		got = meta[key.GoString()]
+	}
+
+	tc.assertJSONEqual(fmt.Sprintf("meta.%s", key.GoString()), got, want)
+	return starlark.None, nil
+}
+
+// assertJSONEqual compares two values by their JSON representation, which
+// smooths over numeric type differences between go & starlark
+func (tc *testCase) assertJSONEqual(field string, got, want interface{}) {
+	gotNorm, err := normalizeJSON(got)
+	if err != nil {
+		tc.errorf("%s: %s", field, err)
+		return
+	}
+	wantNorm, err := normalizeJSON(want)
+	if err != nil {
+		tc.errorf("%s: %s", field, err)
+		return
+	}
+
+	if !reflect.DeepEqual(gotNorm, wantNorm) {
+		gotData, _ := json.Marshal(gotNorm)
+		wantData, _ := json.Marshal(wantNorm)
+		tc.errorf("%s mismatch:\n  got:  %s\n  want: %s", field, gotData, wantData)
+	}
+}
+
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+// fakeDataset builds a previous dataset from a starlark dict. A "body" key
+// is converted to a JSON body file
+func fakeDataset(v starlark.Value) (*dataset.Dataset, error) {
+	ds := &dataset.Dataset{}
+	if v == nil || v == starlark.None {
+		return ds, nil
+	}
+
+	val, err := util.Unmarshal(v)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, ds); err != nil {
+		return nil, err
+	}
+
+	if ds.Body != nil {
+		bodyData, err := json.Marshal(ds.Body)
+		if err != nil {
+			return nil, err
+		}
+		st := &dataset.Structure{Schema: dataset.BaseSchemaArray}
+		if ds.Structure != nil {
+			st = ds.Structure
+		}
+		if _, ok := ds.Body.(map[string]interface{}); ok && ds.Structure == nil {
+			st.Schema = dataset.BaseSchemaObject
+		}
+		// fake bodies are always JSON-encoded
+		st.Format = "json"
+		st.FormatConfig = nil
+		ds.Structure = st
+		ds.Body = nil
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", bodyData))
+	}
+
+	return ds, nil
+}
+
+func unmarshalDict(v starlark.Value) (map[string]interface{}, error) {
+	if v == nil || v == starlark.None {
+		return nil, nil
+	}
+	val, err := util.Unmarshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a dict, got %s", v.Type())
+	}
+	return m, nil
+}
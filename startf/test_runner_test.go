@@ -0,0 +1,66 @@
+package startf
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunTests(t *testing.T) {
+	ctx := context.Background()
+	res, err := RunTests(ctx, scriptFile(t, "testdata/tf.star"), scriptFile(t, TestScriptPath("testdata/tf.star")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 3 {
+		t.Fatalf("expected 3 results, got: %d", len(res))
+	}
+
+	expectNames := []string{"test_body", "test_failing_assertions", "test_error"}
+	for i, name := range expectNames {
+		if res[i].Name != name {
+			t.Errorf("result %d name mismatch. expected: %q, got: %q", i, name, res[i].Name)
+		}
+	}
+
+	if !res[0].Passed {
+		t.Errorf("expected %s to pass. failures: %v error: %s", res[0].Name, res[0].Failures, res[0].Error)
+	}
+	if !strings.Contains(res[0].Output, "hello world!") {
+		t.Errorf("expected output to contain script print. got: %q", res[0].Output)
+	}
+
+	if res[1].Passed {
+		t.Errorf("expected %s to fail", res[1].Name)
+	}
+	if len(res[1].Failures) != 3 {
+		t.Errorf("expected 3 failures, got: %v", res[1].Failures)
+	}
+
+	if res[2].Passed || !strings.Contains(res[2].Error, "oh noes") {
+		t.Errorf("expected %s to fail with a script error. got: %q", res[2].Name, res[2].Error)
+	}
+}
+
+func TestRunTestsPrevConfig(t *testing.T) {
+	ctx := context.Background()
+	res, err := RunTests(ctx, scriptFile(t, "testdata/prev_config.star"), scriptFile(t, "testdata/prev_config_test.star"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res[0].Passed {
+		t.Errorf("expected %s to pass. failures: %v error: %s", res[0].Name, res[0].Failures, res[0].Error)
+	}
+	if res[1].Passed || !strings.Contains(res[1].Error, ErrNtwkDisabled.Error()) {
+		t.Errorf("expected %s to fail with network disabled error, got: %q", res[1].Name, res[1].Error)
+	}
+}
+
+func TestRunTestsNoTests(t *testing.T) {
+	ctx := context.Background()
+	if _, err := RunTests(ctx, scriptFile(t, "testdata/tf.star"), scriptFile(t, "testdata/tf.star")); err == nil {
+		t.Error("expected error running a test script without test functions")
+	}
+}
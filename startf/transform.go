@@ -49,6 +49,15 @@ type ExecOpts struct {
 	ErrWriter io.Writer
 	// starlark module loader function
 	ModuleLoader ModuleLoader
+	// prevent network access for all steps, including download
+	DisableNetwork bool
+	// if set, used as the result of the download step in place of calling the
+	// script's download function
+	DownloadResult starlark.Value
+	// function for calculating dataset stats, enables qri.stats
+	StatsFunc skyqri.StatsFunc
+	// function for running SQL queries, enables qri.sql
+	QueryFunc skyqri.QueryFunc
 }
 
 // AddDatasetLoader is required to enable the load_dataset starlark builtin
@@ -66,6 +75,20 @@ func AddQriRepo(repo repo.Repo) func(o *ExecOpts) {
 	}
 }
 
+// AddStatsFunc is required to enable the qri.stats starlark builtin
+func AddStatsFunc(stats skyqri.StatsFunc) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.StatsFunc = stats
+	}
+}
+
+// AddQueryFunc is required to enable the qri.sql starlark builtin
+func AddQueryFunc(query skyqri.QueryFunc) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.QueryFunc = query
+	}
+}
+
 // AddMutateFieldCheck provides a checkFunc to ExecScript
 func AddMutateFieldCheck(check func(path ...string) error) func(o *ExecOpts) {
 	return func(o *ExecOpts) {
@@ -120,6 +143,8 @@ type transform struct {
 	bodyFile     qfs.File
 	stderr       io.Writer
 	moduleLoader ModuleLoader
+	noNetwork    bool
+	downloadRes  starlark.Value
 
 	download starlark.Iterable
 }
@@ -132,7 +157,8 @@ var DefaultModuleLoader = func(thread *starlark.Thread, module string) (dict sta
 	return starlib.Loader(thread, module)
 }
 
-// ExecScript executes a transformation against a starlark script file. The next dataset pointer
+// ExecScript executes a transformation against a starlark script file, or an
+// SQL query if the transform syntax is "sql". The next dataset pointer
 // may be modified, while the prev dataset point is read-only. At a bare minimum this function
 // will set transformation details, but starlark scripts can modify many parts of the dataset
 // pointer, including meta, structure, and transform. opts may provide more ways for output to
@@ -149,6 +175,14 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 		opt(o)
 	}
 
+	switch next.Transform.Syntax {
+	case "", SyntaxStarlark:
+	case SyntaxSQL:
+		return execSQL(ctx, next, o)
+	default:
+		return fmt.Errorf("unsupported transform syntax: %q", next.Transform.Syntax)
+	}
+
 	// hoist execution settings to resolve package settings
 	resolve.AllowFloat = o.AllowFloat
 	resolve.AllowSet = o.AllowSet
@@ -162,7 +196,7 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 	}
 
 	// set transform details
-	next.Transform.Syntax = "starlark"
+	next.Transform.Syntax = SyntaxStarlark
 	next.Transform.SyntaxVersion = Version
 
 	script := next.Transform.ScriptFile()
@@ -173,15 +207,21 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 	pipeScript := qfs.NewMemfileReader(script.FileName(), tr)
 
 	t := &transform{
-		ctx:          ctx,
-		loadDataset:  o.DatasetLoader,
-		repo:         o.Repo,
-		next:         next,
-		prev:         prev,
-		skyqri:       skyqri.NewModule(o.Repo),
+		ctx:         ctx,
+		loadDataset: o.DatasetLoader,
+		repo:        o.Repo,
+		next:        next,
+		prev:        prev,
+		skyqri: skyqri.NewModule(ctx, o.Repo,
+			skyqri.OptLoader(o.DatasetLoader),
+			skyqri.OptStatsFunc(o.StatsFunc),
+			skyqri.OptQueryFunc(o.QueryFunc),
+		),
 		checkFunc:    o.MutateFieldCheck,
 		stderr:       o.ErrWriter,
 		moduleLoader: o.ModuleLoader,
+		noNetwork:    o.DisableNetwork,
+		downloadRes:  o.DownloadResult,
 	}
 
 	skyCtx := skyctx.NewContext(next.Transform.Config, o.Secrets)
@@ -226,6 +266,16 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 		return fmt.Errorf(evalErr.Backtrace())
 	}
 
+	// record every dataset version the script read
+	if resources := t.skyqri.Resources(); len(resources) > 0 {
+		if next.Transform.Resources == nil {
+			next.Transform.Resources = map[string]*dataset.TransformResource{}
+		}
+		for path, r := range resources {
+			next.Transform.Resources[path] = r
+		}
+	}
+
 	// restore consumed script file
 	next.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.star", buf.Bytes()))
 
@@ -281,8 +331,13 @@ func (t *transform) specialFuncs() (defined map[string]specialFunc, err error) {
 type specialFunc func(t *transform, thread *starlark.Thread, ctx *skyctx.Context) (result starlark.Value, err error)
 
 func callDownloadFunc(t *transform, thread *starlark.Thread, ctx *skyctx.Context) (result starlark.Value, err error) {
-	httpGuard.EnableNtwk()
-	defer httpGuard.DisableNtwk()
+	if t.downloadRes != nil {
+		return t.downloadRes, nil
+	}
+	if !t.noNetwork {
+		httpGuard.EnableNtwk()
+		defer httpGuard.DisableNtwk()
+	}
 	t.print("📡 running download...\n")
 
 	var download *starlark.Function
@@ -354,17 +409,10 @@ func (t *transform) LoadDataset(thread *starlark.Thread, _ *starlark.Builtin, ar
 		return starlark.None, err
 	}
 
-	if t.next.Transform.Resources == nil {
-		t.next.Transform.Resources = map[string]*dataset.TransformResource{}
-	}
-
-	t.next.Transform.Resources[ds.Path] = &dataset.TransformResource{
-		// TODO(b5) - this should be a method on dataset.Dataset
-		// we should add an ID field to dataset, set that to the InitID, and
-		// add fields to dataset.TransformResource that effectively make it the
-		// same data structure as dsref.Ref
-		Path: fmt.Sprintf("%s/%s@%s", ds.Peername, ds.Name, ds.Path),
-	}
+	// TODO(b5) - we should add an ID field to dataset, set that to the InitID,
+	// and add fields to dataset.TransformResource that effectively make it the
+	// same data structure as dsref.Ref
+	t.skyqri.RecordResource(ds)
 
 	return skyds.NewDataset(ds, nil).Methods(), nil
 }
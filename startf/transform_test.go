@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -156,7 +157,145 @@ func TestLoadDataset(t *testing.T) {
 	}
 }
 
+func TestQriModule(t *testing.T) {
+	ctx := context.Background()
+	r := testRepo(t)
+
+	ds := &dataset.Dataset{
+		Transform: &dataset.Transform{},
+	}
+	ds.Transform.SetScriptFile(scriptFile(t, "testdata/qri_module.star"))
+
+	statsCalled := false
+	queries := []string{}
+	err := ExecScript(ctx, ds, nil,
+		AddStatsFunc(func(ctx context.Context, ds *dataset.Dataset) (*dataset.Stats, error) {
+			statsCalled = true
+			return &dataset.Stats{Stats: []interface{}{"a", "b"}}, nil
+		}),
+		AddQueryFunc(func(ctx context.Context, loader dsref.ParseResolveLoad, w io.Writer, outFormat, query string) error {
+			queries = append(queries, query)
+			if _, err := loader(ctx, "peer/movies"); err != nil {
+				return err
+			}
+			_, err := w.Write([]byte(`[{"m.title":"a"}]`))
+			return err
+		}),
+		func(o *ExecOpts) {
+			o.Repo = r
+			o.ModuleLoader = testModuleLoader(t)
+			o.DatasetLoader = newParseResolveLoadFunc("", r, repoLoader{r})
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !statsCalled {
+		t.Error("expected stats func to be called")
+	}
+	if len(queries) != 1 {
+		t.Errorf("expected 1 query, got: %d", len(queries))
+	}
+	if len(ds.Transform.Resources) != 1 {
+		t.Errorf("expected read dataset to be recorded in transform resources. got: %v", ds.Transform.Resources)
+	}
+}
+
+func TestQriModuleHistoryRecordsResource(t *testing.T) {
+	ctx := context.Background()
+	r := testRepo(t)
+
+	ds := &dataset.Dataset{
+		Transform: &dataset.Transform{},
+	}
+	ds.Transform.SetScriptFile(scriptFile(t, "testdata/qri_history.star"))
+
+	err := ExecScript(ctx, ds, nil, func(o *ExecOpts) {
+		o.Repo = r
+		o.ModuleLoader = testModuleLoader(t)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Transform.Resources) != 1 {
+		t.Errorf("expected dataset read by history to be recorded in transform resources. got: %v", ds.Transform.Resources)
+	}
+}
+
 // TODO(b5) - we should think about moving this somewhere more general
+func TestExecSQL(t *testing.T) {
+	ctx := context.Background()
+	r := testRepo(t)
+
+	ds := &dataset.Dataset{
+		Transform: &dataset.Transform{Syntax: SyntaxSQL},
+	}
+	ds.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.sql", []byte("SELECT * FROM peer/movies AS m")))
+
+	err := ExecScript(ctx, ds, nil,
+		AddQueryFunc(func(ctx context.Context, loader dsref.ParseResolveLoad, w io.Writer, outFormat, query string) error {
+			if outFormat != "csv_raw" {
+				t.Errorf("expected query output format to be csv_raw, got: %q", outFormat)
+			}
+			if _, err := loader(ctx, "peer/movies"); err != nil {
+				return err
+			}
+			_, err := w.Write([]byte("title,duration\nAvatar,178\n"))
+			return err
+		}),
+		func(o *ExecOpts) {
+			o.Repo = r
+			o.DatasetLoader = newParseResolveLoadFunc("", r, repoLoader{r})
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ds.Structure == nil || ds.Structure.Format != "csv" {
+		t.Errorf("expected csv structure to be inferred, got: %v", ds.Structure)
+	}
+	if ds.BodyFile() == nil {
+		t.Error("expected body file to be set")
+	}
+	if len(ds.Transform.Resources) != 1 {
+		t.Errorf("expected read dataset to be recorded in transform resources. got: %v", ds.Transform.Resources)
+	}
+
+	// a query that matches nothing saves an empty body
+	for _, result := range []string{"", "title,duration\n"} {
+		ds = &dataset.Dataset{
+			Transform: &dataset.Transform{Syntax: SyntaxSQL},
+		}
+		ds.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.sql", []byte("SELECT * FROM peer/movies AS m WHERE false")))
+		err = ExecScript(ctx, ds, nil,
+			AddQueryFunc(func(ctx context.Context, loader dsref.ParseResolveLoad, w io.Writer, outFormat, query string) error {
+				_, err := w.Write([]byte(result))
+				return err
+			}),
+			func(o *ExecOpts) {
+				o.Repo = r
+				o.DatasetLoader = newParseResolveLoadFunc("", r, repoLoader{r})
+			})
+		if err != nil {
+			t.Fatalf("result %q: expected empty result to save, got: %s", result, err)
+		}
+		if ds.Structure == nil || ds.Structure.Format != "csv" {
+			t.Errorf("result %q: expected csv structure, got: %v", result, ds.Structure)
+		}
+		if ds.BodyFile() == nil {
+			t.Errorf("result %q: expected body file to be set", result)
+		}
+	}
+
+	ds = &dataset.Dataset{
+		Transform: &dataset.Transform{Syntax: "cobol"},
+	}
+	ds.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.cbl", []byte("")))
+	if err := ExecScript(ctx, ds, nil); err == nil {
+		t.Error("expected unsupported syntax to error")
+	}
+}
+
 type repoLoader struct {
 	r repo.Repo
 }
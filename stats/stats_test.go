@@ -139,7 +139,7 @@ func TestStatsFSI(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	fsiSvc := fsi.NewFSI(mr, nil)
+	fsiSvc := fsi.NewFSI(mr, nil, "")
 	vi, _, err := fsiSvc.CreateLink(fsiDir, ref)
 	if err != nil {
 		t.Fatal(err)
@@ -3,18 +3,26 @@ package watchfs
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	golog "github.com/ipfs/go-log"
 	"github.com/qri-io/qri/base/component"
 	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/fsi"
+	"github.com/qri-io/qri/fsi/linkfile"
 	"github.com/qri-io/qri/repo"
 )
 
 var log = golog.Logger("watchfs")
 
+// DefaultDebounce is the default length of time a directory must go without
+// filesystem activity before events for that directory are published
+const DefaultDebounce = 100 * time.Millisecond
+
 // EventPath stores information about a path that is capable of generating events
 type EventPath struct {
 	Path     string
@@ -29,15 +37,39 @@ type EventPath struct {
 // * An existing file was deleted
 // * One of the folders being watched was renamed, but that folder is still being watched
 // * One of the folders was removed, which makes it no longer watched
-// TODO(dlong): Folder rename and remove are not supported yet.
+//
+// File events are debounced: bursts of activity within a directory are
+// collapsed into at most one event per file, published once the directory has
+// been quiet for the Debounce duration. After each burst the recalculated
+// status of the directory is published
 type FilesysWatcher struct {
+	// Debounce is how long a directory must be quiet before pending events
+	// are published
+	Debounce time.Duration
+
+	lk      sync.Mutex
 	watcher *fsnotify.Watcher
 	assoc   map[string]EventPath
+	// parent directories of watched paths, with a count of watched children.
+	// parents are watched to detect linked directories being moved
+	parents map[string]int
+	// pending file events, keyed by directory
+	pending map[string]*pendingEvents
 	bus     event.Bus
+	fsi     *fsi.FSI
 }
 
-// NewFilesysWatcher returns a new FilesysWatcher
-func NewFilesysWatcher(ctx context.Context, bus event.Bus) (*FilesysWatcher, error) {
+// pendingEvents collects file events for a directory until activity settles
+type pendingEvents struct {
+	timer *time.Timer
+	// order files were first seen, for deterministic publishing
+	order []string
+	types map[string]event.Type
+}
+
+// NewFilesysWatcher returns a new FilesysWatcher. If fsint is non-nil the
+// watcher publishes the status of directories after changes
+func NewFilesysWatcher(ctx context.Context, bus event.Bus, fsint *fsi.FSI) (*FilesysWatcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		log.Error(err)
@@ -49,9 +81,13 @@ func NewFilesysWatcher(ctx context.Context, bus event.Bus) (*FilesysWatcher, err
 	}
 
 	w := &FilesysWatcher{
-		assoc:   map[string]EventPath{},
-		watcher: watcher,
-		bus:     bus,
+		Debounce: DefaultDebounce,
+		assoc:    map[string]EventPath{},
+		parents:  map[string]int{},
+		pending:  map[string]*pendingEvents{},
+		watcher:  watcher,
+		bus:      bus,
+		fsi:      fsint,
 	}
 
 	bus.Subscribe(w.eventHandler,
@@ -67,17 +103,9 @@ func NewFilesysWatcher(ctx context.Context, bus event.Bus) (*FilesysWatcher, err
 					log.Debugf("error getting event")
 					continue
 				}
-				if fsEvent.Op == fsnotify.Chmod {
-					// Don't care about CHMOD, skip it
-					continue
-				}
-				if fsEvent.Op&fsnotify.Write == fsnotify.Write {
-					w.publishEvent(event.ETModifiedFile, fsEvent.Name, "")
-				}
-				if fsEvent.Op&fsnotify.Create == fsnotify.Create {
-					w.publishEvent(event.ETCreatedNewFile, fsEvent.Name, "")
-				}
+				w.handleFsEvent(fsEvent)
 			case <-ctx.Done():
+				w.watcher.Close()
 				return
 			}
 		}
@@ -107,7 +135,11 @@ func (w *FilesysWatcher) eventHandler(ctx context.Context, t event.Type, payload
 // WatchAllFSIPaths sets up filesystem watchers for all FSI-linked datasets in
 // a given qri repo
 func (w *FilesysWatcher) WatchAllFSIPaths(ctx context.Context, r repo.Repo) error {
-	refs, err := r.References(0, 100)
+	count, err := r.RefCount()
+	if err != nil {
+		return err
+	}
+	refs, err := r.References(0, count)
 	if err != nil {
 		return err
 	}
@@ -131,40 +163,242 @@ func (w *FilesysWatcher) WatchAllFSIPaths(ctx context.Context, r repo.Repo) erro
 // Begin will start watching the given directory paths
 func (w *FilesysWatcher) watchPaths(paths []EventPath) {
 	for _, p := range paths {
-		err := w.watcher.Add(p.Path)
-		if err != nil {
-			log.Errorf("%s", err)
-		}
-		w.assoc[p.Path] = p
+		w.Watch(p)
 	}
 }
 
 // Watch starts watching an additional path
 func (w *FilesysWatcher) Watch(path EventPath) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	w.watch(path)
+}
+
+// watch adds a path. must be called with the lock held
+func (w *FilesysWatcher) watch(path EventPath) {
+	if _, ok := w.assoc[path.Path]; ok {
+		w.assoc[path.Path] = path
+		return
+	}
 	w.assoc[path.Path] = path
-	w.watcher.Add(path.Path)
-}
-
-// publishEvent sends a message on the channel about an event
-func (w *FilesysWatcher) publishEvent(etype event.Type, sour, dest string) {
-	if w.filterSource(sour) {
-		log.Debugf("filesystem event %q %s -> %s\n", etype, sour, dest)
-
-		dir := filepath.Dir(sour)
-		ep := w.assoc[dir]
-		event := event.WatchfsChange{
-			Username:    ep.Username,
-			Dsname:      ep.Dsname,
-			Source:      sour,
-			Destination: dest,
-			Time:        time.Now(),
+	if err := w.watcher.Add(path.Path); err != nil {
+		log.Errorf("%s", err)
+	}
+
+	parent := filepath.Dir(path.Path)
+	if w.parents[parent] == 0 {
+		if err := w.watcher.Add(parent); err != nil {
+			log.Debugf("watching parent directory %q: %s", parent, err)
+		}
+	}
+	w.parents[parent]++
+}
+
+// unwatch stops watching a path. must be called with the lock held
+func (w *FilesysWatcher) unwatch(path string) {
+	if _, ok := w.assoc[path]; !ok {
+		return
+	}
+	delete(w.assoc, path)
+	// removing a watch for a path that no longer exists is expected to fail
+	_ = w.watcher.Remove(path)
+
+	parent := filepath.Dir(path)
+	w.parents[parent]--
+	if w.parents[parent] <= 0 {
+		delete(w.parents, parent)
+		_ = w.watcher.Remove(parent)
+	}
+}
+
+func (w *FilesysWatcher) handleFsEvent(fsEvent fsnotify.Event) {
+	if fsEvent.Op == fsnotify.Chmod {
+		// Don't care about CHMOD, skip it
+		return
+	}
+	name := filepath.Clean(fsEvent.Name)
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+
+	// event concerns a linked directory itself
+	if ep, ok := w.assoc[name]; ok {
+		if fsEvent.Op&fsnotify.Remove == fsnotify.Remove || fsEvent.Op&fsnotify.Rename == fsnotify.Rename {
+			// a moved directory will be picked up by a create event in the parent
+			// directory. wait before deciding it's been removed, create events
+			// may arrive after the rename
+			time.AfterFunc(w.Debounce, func() { w.checkRemoved(ep) })
 		}
+		return
+	}
 
-		go func() {
-			if err := w.bus.Publish(context.Background(), etype, event); err != nil {
-				log.Error(err)
-			}
-		}()
+	dir := filepath.Dir(name)
+	if _, ok := w.assoc[dir]; ok {
+		if !w.filterSource(name) {
+			return
+		}
+		switch {
+		case fsEvent.Op&fsnotify.Create == fsnotify.Create:
+			w.queue(dir, name, event.ETCreatedNewFile)
+		case fsEvent.Op&fsnotify.Write == fsnotify.Write:
+			w.queue(dir, name, event.ETModifiedFile)
+		case fsEvent.Op&fsnotify.Remove == fsnotify.Remove, fsEvent.Op&fsnotify.Rename == fsnotify.Rename:
+			w.queue(dir, name, event.ETDeletedFile)
+		}
+		return
+	}
+
+	if w.parents[dir] > 0 && fsEvent.Op&fsnotify.Create == fsnotify.Create {
+		w.checkMoved(name)
+	}
+}
+
+// checkMoved checks if a directory created alongside watched directories is a
+// watched directory that's been moved. must be called with the lock held
+func (w *FilesysWatcher) checkMoved(path string) {
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		return
+	}
+	ref, err := linkfile.Read(filepath.Join(path, linkfile.RefLinkHiddenFilename))
+	if err != nil {
+		return
+	}
+
+	for prev, ep := range w.assoc {
+		if ep.Username != ref.Username || ep.Dsname != ref.Name {
+			continue
+		}
+		if _, err := os.Stat(prev); !os.IsNotExist(err) {
+			// previous location still exists, this is a copy
+			continue
+		}
+
+		log.Debugf("linked directory moved from %q to %q", prev, path)
+		w.unwatch(prev)
+		moved := ep
+		moved.Path = path
+		w.watch(moved)
+		w.publishEvent(event.ETRenamedFolder, moved, prev, path)
+		return
+	}
+}
+
+// checkRemoved publishes a removed folder event for a directory that no
+// longer exists
+func (w *FilesysWatcher) checkRemoved(ep EventPath) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	if _, ok := w.assoc[ep.Path]; !ok {
+		// directory was moved
+		return
+	}
+	if _, err := os.Stat(ep.Path); !os.IsNotExist(err) {
+		return
+	}
+	log.Debugf("linked directory removed: %q", ep.Path)
+	w.unwatch(ep.Path)
+	if p, ok := w.pending[ep.Path]; ok {
+		p.timer.Stop()
+		delete(w.pending, ep.Path)
+	}
+	w.publishEvent(event.ETRemovedFolder, ep, ep.Path, "")
+}
+
+// queue adds a file event to the pending events for a directory, restarting
+// the debounce timer. must be called with the lock held
+func (w *FilesysWatcher) queue(dir, source string, etype event.Type) {
+	p, ok := w.pending[dir]
+	if !ok {
+		p = &pendingEvents{types: map[string]event.Type{}}
+		p.timer = time.AfterFunc(w.Debounce, func() { w.flush(dir) })
+		w.pending[dir] = p
+	} else {
+		p.timer.Reset(w.Debounce)
+	}
+
+	prev, seen := p.types[source]
+	if !seen {
+		p.order = append(p.order, source)
+		p.types[source] = etype
+		return
+	}
+	p.types[source] = coalesce(prev, etype)
+}
+
+// coalesce combines two events for the same file into a single event. An
+// empty type means the events cancel out
+func coalesce(prev, next event.Type) event.Type {
+	switch {
+	case prev == event.ETCreatedNewFile && next == event.ETDeletedFile:
+		return ""
+	case prev == event.ETCreatedNewFile:
+		return event.ETCreatedNewFile
+	case prev == event.ETDeletedFile && next == event.ETCreatedNewFile:
+		// editors often save by replacing a file
+		return event.ETModifiedFile
+	case prev == "" && next == event.ETDeletedFile:
+		return ""
+	default:
+		return next
+	}
+}
+
+// flush publishes pending events for a directory, followed by the directory's
+// status
+func (w *FilesysWatcher) flush(dir string) {
+	w.lk.Lock()
+	p, ok := w.pending[dir]
+	delete(w.pending, dir)
+	ep, watched := w.assoc[dir]
+	w.lk.Unlock()
+	if !ok || !watched {
+		return
+	}
+
+	changed := false
+	for _, source := range p.order {
+		if etype := p.types[source]; etype != "" {
+			w.publish(etype, changeEvent(ep, source, ""))
+			changed = true
+		}
+	}
+
+	if changed && w.fsi != nil {
+		status, err := w.fsi.Status(context.Background(), dir)
+		if err != nil {
+			log.Debugf("calculating status for %q: %s", dir, err)
+			return
+		}
+		w.publish(event.ETWorkingDirStatusChanged, event.WorkingDirStatus{
+			Username: ep.Username,
+			Dsname:   ep.Dsname,
+			FSIPath:  dir,
+			Status:   status,
+			Time:     time.Now(),
+		})
+	}
+}
+
+func changeEvent(ep EventPath, sour, dest string) event.WatchfsChange {
+	return event.WatchfsChange{
+		Username:    ep.Username,
+		Dsname:      ep.Dsname,
+		Source:      sour,
+		Destination: dest,
+		Time:        time.Now(),
+	}
+}
+
+// publishEvent sends a message on the bus about an event without blocking
+func (w *FilesysWatcher) publishEvent(etype event.Type, ep EventPath, sour, dest string) {
+	log.Debugf("filesystem event %q %s -> %s\n", etype, sour, dest)
+	evt := changeEvent(ep, sour, dest)
+	go w.publish(etype, evt)
+}
+
+func (w *FilesysWatcher) publish(etype event.Type, payload interface{}) {
+	if err := w.bus.Publish(context.Background(), etype, payload); err != nil {
+		log.Error(err)
 	}
 }
 
@@ -7,9 +7,13 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/fsi"
+	"github.com/qri-io/qri/fsi/linkfile"
 	reporef "github.com/qri-io/qri/repo/ref"
 	repotest "github.com/qri-io/qri/repo/test"
 )
@@ -45,7 +49,7 @@ func TestFilesysWatcher(t *testing.T) {
 	// Create a directory, and watch it
 	watchdir := filepath.Join(tmpdir, "watch_me")
 	_ = os.Mkdir(watchdir, 0755)
-	w, err := NewFilesysWatcher(ctx, bus)
+	w, err := NewFilesysWatcher(ctx, bus, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -111,7 +115,7 @@ func TestWatchAllFSIPaths(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	watcher, err := NewFilesysWatcher(ctx, event.NilBus)
+	watcher, err := NewFilesysWatcher(ctx, event.NilBus, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -135,3 +139,188 @@ func TestWatchAllFSIPaths(t *testing.T) {
 		t.Errorf("expected eventPath to have path %q, instead had path %q", ref.FSIPath, eventPath.Path)
 	}
 }
+
+// eventCollector gathers published events on a channel
+func eventCollector(bus event.Bus, types ...event.Type) chan event.Type {
+	ch := make(chan event.Type, 100)
+	bus.Subscribe(func(_ context.Context, typ event.Type, payload interface{}) error {
+		ch <- typ
+		return nil
+	}, types...)
+	return ch
+}
+
+// collectFor returns all events received within a duration
+func collectFor(ch chan event.Type, d time.Duration) []event.Type {
+	got := []event.Type{}
+	timeout := time.After(d)
+	for {
+		select {
+		case t := <-ch:
+			got = append(got, t)
+		case <-timeout:
+			return got
+		}
+	}
+}
+
+func TestFilesysWatcherDebounceAndDelete(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "watchfs_debounce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := event.NewBus(ctx)
+	events := eventCollector(bus, event.ETCreatedNewFile, event.ETModifiedFile, event.ETDeletedFile)
+
+	w, err := NewFilesysWatcher(ctx, bus, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Debounce = 50 * time.Millisecond
+	w.Watch(EventPath{Path: tmpdir, Username: "peer", Dsname: "ds"})
+
+	// a burst of writes produces a single event
+	target := filepath.Join(tmpdir, "meta.json")
+	for i := 0; i < 5; i++ {
+		if err := ioutil.WriteFile(target, []byte(`{"title":"test"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := collectFor(events, 300*time.Millisecond)
+	if diff := cmp.Diff([]event.Type{event.ETCreatedNewFile}, got); diff != "" {
+		t.Errorf("burst events mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := os.Remove(target); err != nil {
+		t.Fatal(err)
+	}
+	got = collectFor(events, 300*time.Millisecond)
+	if diff := cmp.Diff([]event.Type{event.ETDeletedFile}, got); diff != "" {
+		t.Errorf("delete events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFilesysWatcherMoveAndRemoveFolder(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "watchfs_move")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := event.NewBus(ctx)
+	changes := make(chan event.WatchfsChange, 10)
+	bus.Subscribe(func(_ context.Context, typ event.Type, payload interface{}) error {
+		changes <- payload.(event.WatchfsChange)
+		return nil
+	}, event.ETRenamedFolder, event.ETRemovedFolder)
+
+	w, err := NewFilesysWatcher(ctx, bus, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Debounce = 50 * time.Millisecond
+
+	src := filepath.Join(tmpdir, "working_dir")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := linkfile.WriteHiddenInDir(src, dsref.Ref{Username: "peer", Name: "ds"}); err != nil {
+		t.Fatal(err)
+	}
+	w.Watch(EventPath{Path: src, Username: "peer", Dsname: "ds"})
+
+	dest := filepath.Join(tmpdir, "moved_dir")
+	if err := os.Rename(src, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-changes:
+		if got.Source != src || got.Destination != dest {
+			t.Errorf("rename event mismatch. want: %q -> %q, got: %q -> %q", src, dest, got.Source, got.Destination)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for rename event")
+	}
+	w.lk.Lock()
+	if _, ok := w.assoc[dest]; !ok {
+		t.Errorf("expected watcher to follow moved directory")
+	}
+	w.lk.Unlock()
+
+	if err := os.RemoveAll(dest); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-changes:
+		if got.Source != dest {
+			t.Errorf("remove event source mismatch. want: %q, got: %q", dest, got.Source)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for remove event")
+	}
+}
+
+func TestFilesysWatcherStatusEvent(t *testing.T) {
+	r, err := repotest.NewTestRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpdir, err := ioutil.TempDir("", "watchfs_status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := event.NewBus(ctx)
+
+	fsint := fsi.NewFSI(r, bus, "")
+	workDir := filepath.Join(tmpdir, "status_ds")
+	if _, err := fsint.InitDataset(fsi.InitParams{
+		Name:      "status_ds",
+		TargetDir: workDir,
+		Format:    "csv",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := make(chan event.WorkingDirStatus, 10)
+	bus.Subscribe(func(_ context.Context, typ event.Type, payload interface{}) error {
+		statuses <- payload.(event.WorkingDirStatus)
+		return nil
+	}, event.ETWorkingDirStatusChanged)
+
+	w, err := NewFilesysWatcher(ctx, bus, fsint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Debounce = 50 * time.Millisecond
+	w.Watch(EventPath{Path: workDir, Username: "peer", Dsname: "status_ds"})
+
+	if err := ioutil.WriteFile(filepath.Join(workDir, "body.csv"), []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-statuses:
+		if got.FSIPath != workDir {
+			t.Errorf("status path mismatch. want: %q, got: %q", workDir, got.FSIPath)
+		}
+		items, ok := got.Status.([]fsi.StatusItem)
+		if !ok || len(items) == 0 {
+			t.Errorf("expected status items, got: %#v", got.Status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for status event")
+	}
+}